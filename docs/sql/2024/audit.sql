@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS `audit_log`;
+CREATE TABLE IF NOT EXISTS `audit_log` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_actor_id` INT,
+    `action` VARCHAR(100) NOT NULL,
+    `target_type` VARCHAR(50) NOT NULL,
+    `target_id` INT,
+    `request_id` VARCHAR(255),
+    `ip_address` VARCHAR(45),
+    `user_agent` VARCHAR(512),
+    `before_value` TEXT,
+    `after_value` TEXT,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_audit_log_actor` (`fk_actor_id`),
+    KEY `idx_audit_log_target` (`target_type`, `target_id`),
+    KEY `idx_audit_log_created_at` (`created_at`)
+) ENGINE = INNODB;
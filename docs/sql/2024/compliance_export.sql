@@ -0,0 +1,45 @@
+DROP TABLE IF EXISTS `compliance_export_chunk`;
+DROP TABLE IF EXISTS `compliance_export`;
+CREATE TABLE IF NOT EXISTS `compliance_export` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_requested_by` INT NOT NULL,
+    `subject_type` VARCHAR(50) NOT NULL,
+    `subject_id` INT NOT NULL,
+    `range_start` TIMESTAMP NOT NULL,
+    `range_end` TIMESTAMP NOT NULL,
+    `export_status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+    `file_name` VARCHAR(255),
+    `file_size` BIGINT,
+    `error_message` VARCHAR(255),
+    `started_at` TIMESTAMP,
+    `completed_at` TIMESTAMP,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    KEY `idx_compliance_export_status` (`export_status`, `id`),
+    KEY `idx_compliance_export_requester` (`fk_requested_by`),
+    FOREIGN KEY (`fk_requested_by`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+
+-- archives are stored in chunks so every replica can serve a download
+CREATE TABLE IF NOT EXISTS `compliance_export_chunk` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_export_id` INT NOT NULL,
+    `chunk_index` INT NOT NULL,
+    `content` MEDIUMBLOB NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_compliance_export_chunk` (`fk_export_id`, `chunk_index`),
+    KEY `idx_compliance_export_chunk_created` (`created_at`),
+    FOREIGN KEY (`fk_export_id`) REFERENCES `compliance_export` (`id`)
+) ENGINE = INNODB;
@@ -0,0 +1,30 @@
+DROP TABLE IF EXISTS `contact`;
+CREATE TABLE IF NOT EXISTS `contact` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `fk_contact_id` INT NOT NULL,
+    `contact_status` VARCHAR(50) NOT NULL,
+    `responded_at` TIMESTAMP,
+    -- the pair in id order and 1 while the request is pending or accepted, so two users have at most
+    -- one live request between them whichever side sent it
+    `pair_low_id` INT AS (LEAST(`fk_user_id`, `fk_contact_id`)) STORED,
+    `pair_high_id` INT AS (GREATEST(`fk_user_id`, `fk_contact_id`)) STORED,
+    `is_live` TINYINT AS (IF(`contact_status` IN ('pending', 'accepted') AND `status` = 1, 1, NULL)) STORED,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_contact_live_pair` (`pair_low_id`, `pair_high_id`, `is_live`),
+    KEY `idx_contact_user` (`fk_user_id`, `contact_status`),
+    KEY `idx_contact_contact` (`fk_contact_id`, `contact_status`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`),
+    FOREIGN KEY (`fk_contact_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
@@ -17,7 +17,9 @@ CREATE TABLE IF NOT EXISTS `user` (
     `updated_by` VARCHAR(255),
     `deleted_at`TIMESTAMP,
     `deleted_by` VARCHAR(255),
-    PRIMARY KEY (`id`)
+    PRIMARY KEY (`id`),
+    -- erased users get a placeholder email, so every stored address belongs to an active or suspended user
+    UNIQUE KEY `uq_user_email` (`email`)
 ) ENGINE = INNODB;
 
 DROP TABLE IF EXISTS `role`;
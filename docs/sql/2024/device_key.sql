@@ -0,0 +1,53 @@
+DROP TABLE IF EXISTS `device_key`;
+CREATE TABLE IF NOT EXISTS `device_key` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `fk_device_id` INT NOT NULL,
+    `identity_key` VARCHAR(255) NOT NULL,
+    `signed_prekey_id` INT NOT NULL,
+    `signed_prekey` VARCHAR(255) NOT NULL,
+    `signed_prekey_signature` VARCHAR(255) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_device_key_device` (`fk_device_id`),
+    KEY `idx_device_key_user` (`fk_user_id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`),
+    FOREIGN KEY (`fk_device_id`) REFERENCES `device` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `one_time_prekey`;
+CREATE TABLE IF NOT EXISTS `one_time_prekey` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `fk_device_id` INT NOT NULL,
+    `key_id` INT NOT NULL,
+    `public_key` VARCHAR(255) NOT NULL,
+    `fk_claimed_by` INT,
+    `claimed_at` TIMESTAMP,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_one_time_prekey_device_key` (`fk_device_id`, `key_id`),
+    KEY `idx_one_time_prekey_device` (`fk_device_id`, `status`, `id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`),
+    FOREIGN KEY (`fk_device_id`) REFERENCES `device` (`id`)
+) ENGINE = INNODB;
@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS `legal_hold`;
+CREATE TABLE IF NOT EXISTS `legal_hold` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `reason` VARCHAR(255) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    KEY `idx_legal_hold_scope` (`scope_type`, `scope_id`, `status`)
+) ENGINE = INNODB;
@@ -0,0 +1,73 @@
+DROP TABLE IF EXISTS `notification`;
+CREATE TABLE IF NOT EXISTS `notification` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `fk_actor_id` INT,
+    `event_id` VARCHAR(255),
+    `notification_type` VARCHAR(50) NOT NULL,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `title` VARCHAR(255) NOT NULL,
+    `body` TEXT NOT NULL,
+    `read_status` VARCHAR(20) NOT NULL DEFAULT 'unread',
+    `read_at` TIMESTAMP,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_notification_event` (`event_id`, `fk_user_id`),
+    KEY `idx_notification_user` (`fk_user_id`, `read_status`, `created_at`),
+    KEY `idx_notification_scope` (`scope_type`, `scope_id`, `created_at`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `device`;
+CREATE TABLE IF NOT EXISTS `device` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `platform` VARCHAR(20) NOT NULL,
+    `token` VARCHAR(512) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_device_token` (`token`),
+    KEY `idx_device_user` (`fk_user_id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `notification_job`;
+CREATE TABLE IF NOT EXISTS `notification_job` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `event_id` VARCHAR(255) NOT NULL,
+    `event_type` VARCHAR(100) NOT NULL,
+    `payload` MEDIUMTEXT NOT NULL,
+    `attempt` INT NOT NULL DEFAULT '0',
+    `job_status` VARCHAR(50) NOT NULL,
+    `next_attempt_at` TIMESTAMP NULL,
+    `error_message` TEXT,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_notification_job_event` (`event_id`),
+    KEY `idx_notification_job_due` (`job_status`, `next_attempt_at`)
+) ENGINE = INNODB;
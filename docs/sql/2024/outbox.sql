@@ -0,0 +1,47 @@
+DROP TABLE IF EXISTS `outbox`;
+CREATE TABLE IF NOT EXISTS `outbox` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `event_id` VARCHAR(255) NOT NULL,
+    `event_type` VARCHAR(100) NOT NULL,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `actor_id` INT NOT NULL DEFAULT '0',
+    -- seq is assigned by the relay in commit order, readers page on it instead of id
+    `seq` BIGINT NULL,
+    `payload` TEXT NOT NULL,
+    `published_at` TIMESTAMP NULL,
+    `attempts` INT NOT NULL DEFAULT '0',
+    `last_error` TEXT,
+    `next_attempt_at` TIMESTAMP NULL,
+    `dead_at` TIMESTAMP NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_outbox_event_id` (`event_id`),
+    UNIQUE KEY `uq_outbox_seq` (`seq`),
+    KEY `idx_outbox_pending` (`published_at`, `dead_at`, `id`),
+    KEY `idx_outbox_scope` (`scope_type`, `scope_id`, `seq`)
+) ENGINE = INNODB;
+
+-- a single row holding the last assigned outbox seq, relays lock it until they commit so seqs
+-- become visible in increasing order
+DROP TABLE IF EXISTS `outbox_sequence`;
+CREATE TABLE IF NOT EXISTS `outbox_sequence` (
+    `id` INT NOT NULL,
+    `last_seq` BIGINT NOT NULL DEFAULT '0',
+    PRIMARY KEY (`id`)
+) ENGINE = INNODB;
+
+INSERT INTO `outbox_sequence` (`id`, `last_seq`) VALUES (1, 0);
+
+-- the highest seq purged by retention per scope, sync tokens below it have missed events and must resync
+DROP TABLE IF EXISTS `outbox_purge`;
+CREATE TABLE IF NOT EXISTS `outbox_purge` (
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `purged_seq` BIGINT NOT NULL DEFAULT '0',
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`scope_type`, `scope_id`)
+) ENGINE = INNODB;
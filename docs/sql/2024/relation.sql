@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS `relation`;
+CREATE TABLE IF NOT EXISTS `relation` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `relation_type` VARCHAR(50) NOT NULL,
+    `target_type` VARCHAR(50) NOT NULL,
+    `target_id` INT NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_relation` (`fk_user_id`, `relation_type`, `target_type`, `target_id`),
+    KEY `idx_relation_target` (`target_type`, `target_id`, `relation_type`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
@@ -0,0 +1,56 @@
+DROP TABLE IF EXISTS `report_case`;
+CREATE TABLE IF NOT EXISTS `report_case` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `target_type` VARCHAR(50) NOT NULL,
+    `target_id` INT NOT NULL,
+    `case_status` VARCHAR(20) NOT NULL DEFAULT 'open',
+    `report_count` INT NOT NULL DEFAULT '0',
+    `fk_assignee_id` INT,
+    `resolution` VARCHAR(50),
+    `resolution_note` VARCHAR(1000),
+    `fk_resolved_by` INT,
+    `resolved_at` TIMESTAMP,
+    -- 1 while the case is open and NULL once it is closed, so a target has at most one open case
+    `is_open` TINYINT DEFAULT '1',
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_report_case_open_target` (`target_type`, `target_id`, `is_open`),
+    KEY `idx_report_case_status` (`case_status`, `status`, `id`),
+    KEY `idx_report_case_assignee` (`fk_assignee_id`, `case_status`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `report`;
+CREATE TABLE IF NOT EXISTS `report` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_case_id` INT NOT NULL,
+    `fk_reporter_id` INT NOT NULL,
+    `target_type` VARCHAR(50) NOT NULL,
+    `target_id` INT NOT NULL,
+    `reason` VARCHAR(50) NOT NULL,
+    `details` VARCHAR(1000),
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_report_case_reporter` (`fk_case_id`, `fk_reporter_id`),
+    FOREIGN KEY (`fk_case_id`) REFERENCES `report_case` (`id`),
+    FOREIGN KEY (`fk_reporter_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
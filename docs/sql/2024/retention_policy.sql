@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS `retention_policy`;
+CREATE TABLE IF NOT EXISTS `retention_policy` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `retention_days` INT NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_retention_policy_scope` (`scope_type`, `scope_id`)
+) ENGINE = INNODB;
@@ -0,0 +1,43 @@
+DROP TABLE IF EXISTS `user_preference`;
+CREATE TABLE IF NOT EXISTS `user_preference` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `do_not_disturb` BOOLEAN NOT NULL DEFAULT FALSE,
+    `quiet_hours_start` VARCHAR(5) NOT NULL DEFAULT '',
+    `quiet_hours_end` VARCHAR(5) NOT NULL DEFAULT '',
+    `time_zone` VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    `email_digest` BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_preference_user` (`fk_user_id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `scope_preference`;
+CREATE TABLE IF NOT EXISTS `scope_preference` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `notification_level` VARCHAR(20) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_scope_preference` (`fk_user_id`, `scope_type`, `scope_id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
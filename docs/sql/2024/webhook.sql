@@ -0,0 +1,51 @@
+DROP TABLE IF EXISTS `webhook`;
+CREATE TABLE IF NOT EXISTS `webhook` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_user_id` INT NOT NULL,
+    `scope_type` VARCHAR(50) NOT NULL,
+    `scope_id` INT NOT NULL,
+    `url` VARCHAR(2048) NOT NULL,
+    `secret` VARCHAR(255) NOT NULL,
+    `events` VARCHAR(1024) NOT NULL,
+    `failure_count` INT NOT NULL DEFAULT '0',
+    `is_disabled` BOOLEAN NOT NULL DEFAULT FALSE,
+    `disabled_at` TIMESTAMP,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    KEY `idx_webhook_scope` (`scope_type`, `scope_id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `webhook_delivery`;
+CREATE TABLE IF NOT EXISTS `webhook_delivery` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_webhook_id` INT NOT NULL,
+    `event_id` VARCHAR(255) NOT NULL,
+    `event_type` VARCHAR(100) NOT NULL,
+    `payload` MEDIUMTEXT NOT NULL,
+    `attempt` INT NOT NULL DEFAULT '0',
+    `delivery_status` VARCHAR(50) NOT NULL,
+    `next_attempt_at` TIMESTAMP NULL,
+    `response_code` INT NOT NULL DEFAULT '0',
+    `error_message` TEXT,
+    `duration_ms` INT NOT NULL DEFAULT '0',
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_webhook_delivery_event` (`fk_webhook_id`, `event_id`),
+    KEY `idx_webhook_delivery_due` (`delivery_status`, `next_attempt_at`),
+    FOREIGN KEY (`fk_webhook_id`) REFERENCES `webhook` (`id`)
+) ENGINE = INNODB;
@@ -0,0 +1,45 @@
+DROP TABLE IF EXISTS `workspace`;
+CREATE TABLE IF NOT EXISTS `workspace` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_owner_id` INT NOT NULL,
+    `name` VARCHAR(255) NOT NULL,
+    `slug` VARCHAR(50) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_workspace_slug` (`slug`),
+    FOREIGN KEY (`fk_owner_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
+
+DROP TABLE IF EXISTS `workspace_member`;
+CREATE TABLE IF NOT EXISTS `workspace_member` (
+    `id` INT NOT NULL AUTO_INCREMENT,
+    `fk_workspace_id` INT NOT NULL,
+    `fk_user_id` INT NOT NULL,
+    `member_role` VARCHAR(50) NOT NULL,
+
+    -- Utility columns
+    `status` SMALLINT NOT NULL DEFAULT '1',
+    `flag` INT NOT NULL DEFAULT '0',
+    `meta` VARCHAR(255),
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by` VARCHAR(255),
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `updated_by` VARCHAR(255),
+    `deleted_at`TIMESTAMP,
+    `deleted_by` VARCHAR(255),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_workspace_member` (`fk_workspace_id`, `fk_user_id`),
+    KEY `idx_workspace_member_user` (`fk_user_id`),
+    FOREIGN KEY (`fk_workspace_id`) REFERENCES `workspace` (`id`),
+    FOREIGN KEY (`fk_user_id`) REFERENCES `user` (`id`)
+) ENGINE = INNODB;
@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/reqctx"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+
+	// lengths of the ip_address and user_agent columns
+	maxIPAddressLength = 45
+	maxUserAgentLength = 512
+)
+
+// redactedFields are never written to the audit trail in clear text
+var redactedFields = map[string]bool{
+	"password":        true,
+	"confirmPassword": true,
+	"refreshToken":    true,
+	"accessToken":     true,
+	"secret":          true,
+}
+
+type Interface interface {
+	// Create records an audit entry, request metadata and the actor are taken from ctx when not set
+	Create(ctx context.Context, inputParam entity.AuditInputParam) error
+	GetList(ctx context.Context, param entity.AuditParam) ([]entity.Audit, *entity.Pagination, error)
+	// DeleteBefore hard deletes up to limit entries created before the given time
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	// Anonymize clears the recorded values and client details of entries by or about a user, the entries
+	// themselves are kept
+	Anonymize(ctx context.Context, userID int64) error
+}
+
+type audit struct {
+	db   sql.Interface
+	log  log.Interface
+	json parser.JSONInterface
+}
+
+type InitParam struct {
+	Db   sql.Interface
+	Log  log.Interface
+	Json parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &audit{
+		db:   param.Db,
+		log:  param.Log,
+		json: param.Json,
+	}
+}
+
+func (a *audit) Create(ctx context.Context, inputParam entity.AuditInputParam) error {
+	if !inputParam.ActorID.Valid {
+		if userID := appcontext.GetUserId(ctx); userID > 0 {
+			inputParam.ActorID = null.Int64From(int64(userID))
+		}
+	}
+
+	if inputParam.RequestID == "" {
+		inputParam.RequestID = appcontext.GetRequestId(ctx)
+	}
+
+	if inputParam.OmitClient {
+		inputParam.IPAddress = ""
+		inputParam.UserAgent = ""
+	} else {
+		if inputParam.IPAddress == "" {
+			inputParam.IPAddress = reqctx.GetClientIP(ctx)
+		}
+
+		if inputParam.UserAgent == "" {
+			inputParam.UserAgent = appcontext.GetUserAgent(ctx)
+		}
+	}
+
+	inputParam.IPAddress = truncate(inputParam.IPAddress, maxIPAddressLength)
+	inputParam.UserAgent = truncate(inputParam.UserAgent, maxUserAgentLength)
+
+	if !inputParam.CreatedAt.Valid {
+		inputParam.CreatedAt = null.TimeFrom(time.Now())
+	}
+
+	before, after, err := a.diff(inputParam.BeforeValue, inputParam.AfterValue)
+	if err != nil {
+		return err
+	}
+	inputParam.Before = before
+	inputParam.After = after
+
+	return a.createSQL(ctx, inputParam)
+}
+
+func (a *audit) GetList(ctx context.Context, param entity.AuditParam) ([]entity.Audit, *entity.Pagination, error) {
+	return a.getListSQL(ctx, param)
+}
+
+func (a *audit) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return a.deleteBeforeSQL(ctx, before, limit)
+}
+
+func (a *audit) Anonymize(ctx context.Context, userID int64) error {
+	return a.anonymizeSQL(ctx, userID)
+}
+
+// diff keeps only the fields whose value changed between before and after
+func (a *audit) diff(before, after interface{}) (null.String, null.String, error) {
+	beforeMap, err := a.toMap(before)
+	if err != nil {
+		return null.String{}, null.String{}, err
+	}
+
+	afterMap, err := a.toMap(after)
+	if err != nil {
+		return null.String{}, null.String{}, err
+	}
+
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range beforeMap {
+		if afterValue, ok := afterMap[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changedBefore[key] = value
+		}
+	}
+
+	for key, value := range afterMap {
+		if beforeValue, ok := beforeMap[key]; !ok || !reflect.DeepEqual(value, beforeValue) {
+			changedAfter[key] = value
+		}
+	}
+
+	beforeResult, err := a.toJSON(changedBefore)
+	if err != nil {
+		return null.String{}, null.String{}, err
+	}
+
+	afterResult, err := a.toJSON(changedAfter)
+	if err != nil {
+		return null.String{}, null.String{}, err
+	}
+
+	return beforeResult, afterResult, nil
+}
+
+func (a *audit) toMap(value interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if value == nil {
+		return result, nil
+	}
+
+	marshalled, err := a.json.Marshal(value)
+	if err != nil {
+		return result, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = a.json.Unmarshal(marshalled, &result)
+	if err != nil {
+		return result, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	for key := range result {
+		if redactedFields[key] {
+			result[key] = redactedValue
+		}
+	}
+
+	return result, nil
+}
+
+func (a *audit) toJSON(value map[string]interface{}) (null.String, error) {
+	if len(value) == 0 {
+		return null.String{}, nil
+	}
+
+	marshalled, err := a.json.Marshal(value)
+	if err != nil {
+		return null.String{}, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	return null.StringFrom(string(marshalled)), nil
+}
+
+// truncate cuts s to length characters, the columns are sized in characters rather than bytes
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length])
+}
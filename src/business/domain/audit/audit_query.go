@@ -0,0 +1,84 @@
+package audit
+
+const (
+	insertAudit = `
+		INSERT INTO audit_log
+		(
+			fk_actor_id,
+			action,
+			target_type,
+			target_id,
+			request_id,
+			ip_address,
+			user_agent,
+			before_value,
+			after_value,
+			created_at
+		)
+		VALUES
+		(
+			:fk_actor_id,
+			:action,
+			:target_type,
+			:target_id,
+			:request_id,
+			:ip_address,
+			:user_agent,
+			:before_value,
+			:after_value,
+			:created_at
+		)
+	`
+
+	readAudit = `
+		SELECT
+			id,
+			fk_actor_id,
+			action,
+			target_type,
+			target_id,
+			request_id,
+			ip_address,
+			user_agent,
+			before_value,
+			after_value,
+			status,
+			created_at
+		FROM
+			audit_log
+	`
+
+	countAudit = `
+		SELECT
+			COUNT(*)
+		FROM
+			audit_log
+	`
+
+	// entries by or about a user on legal hold are kept
+	deleteAuditBefore = `
+		DELETE FROM
+			audit_log
+		WHERE
+			created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_hold h
+				WHERE h.scope_type = 'user' AND h.status = 1
+				AND (h.scope_id = audit_log.fk_actor_id OR (audit_log.target_type = 'user' AND h.scope_id = audit_log.target_id))
+			)
+		LIMIT ?
+	`
+
+	anonymizeAudit = `
+		UPDATE
+			audit_log
+		SET
+			ip_address = '',
+			user_agent = '',
+			before_value = NULL,
+			after_value = NULL
+		WHERE
+			fk_actor_id = ?
+			OR (target_type = 'user' AND target_id = ?)
+	`
+)
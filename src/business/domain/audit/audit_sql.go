@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (a *audit) createSQL(ctx context.Context, inputParam entity.AuditInputParam) error {
+	a.log.Debug(ctx, fmt.Sprintf("create audit %s on %s %v", inputParam.Action, inputParam.TargetType, inputParam.TargetID.Int64))
+
+	tx, err := a.db.Leader().BeginTx(ctx, "txAudit", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewAudit", insertAudit, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no audit created")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	a.log.Debug(ctx, fmt.Sprintf("success create audit %s on %s %v", inputParam.Action, inputParam.TargetType, inputParam.TargetID.Int64))
+
+	return nil
+}
+
+func (a *audit) getListSQL(ctx context.Context, param entity.AuditParam) ([]entity.Audit, *entity.Pagination, error) {
+	audits := []entity.Audit{}
+
+	a.log.Debug(ctx, fmt.Sprintf("get audit list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return audits, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := a.db.Follower().Query(ctx, "rAuditList", readAudit+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return audits, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		audit := entity.Audit{}
+		err := rows.StructScan(&audit)
+		if err != nil {
+			return audits, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		audits = append(audits, audit)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(audits)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(audits) > 0 && param.IncludePagination {
+		err := a.db.Follower().Get(ctx, "cAuditList", countAudit+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return audits, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	a.log.Debug(ctx, fmt.Sprintf("success get audit list with body: %v", param))
+
+	return audits, &pg, nil
+}
+
+func (a *audit) deleteBeforeSQL(ctx context.Context, before time.Time, limit int) (int64, error) {
+	a.log.Debug(ctx, fmt.Sprintf("delete audit created before %v", before))
+
+	tx, err := a.db.Leader().BeginTx(ctx, "txAudit", sql.TxOptions{})
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("dAuditBefore", deleteAuditBefore, before, limit)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	a.log.Debug(ctx, fmt.Sprintf("success delete %v audit created before %v", rowCount, before))
+
+	return rowCount, nil
+}
+
+func (a *audit) anonymizeSQL(ctx context.Context, userID int64) error {
+	a.log.Debug(ctx, fmt.Sprintf("anonymize audit of user %v", userID))
+
+	tx, err := a.db.Leader().BeginTx(ctx, "txAudit", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("uAnonymizeAudit", anonymizeAudit, userID, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	a.log.Debug(ctx, fmt.Sprintf("success anonymize audit of user %v", userID))
+
+	return nil
+}
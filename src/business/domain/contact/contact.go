@@ -0,0 +1,149 @@
+package contact
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	GetList(ctx context.Context, param entity.ContactParam) ([]entity.Contact, *entity.Pagination, error)
+	Get(ctx context.Context, param entity.ContactParam) (entity.Contact, error)
+	Create(ctx context.Context, inputParam entity.ContactInputParam) (entity.Contact, error)
+	Update(ctx context.Context, updateParam entity.ContactUpdateParam, selectParam entity.ContactParam) error
+	// GetUserList returns a page of the users on the other side of the accepted contacts of param.UserID
+	GetUserList(ctx context.Context, param entity.ContactUserParam) ([]entity.ContactUser, *entity.Pagination, error)
+	// EraseTx deactivates every contact the user sent or received inside tx, DeleteCache must be called once tx is committed
+	EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+	// DeleteCache drops the cached contacts after a change committed through another domain's transaction
+	DeleteCache(ctx context.Context) error
+}
+
+type contact struct {
+	db    sql.Interface
+	log   log.Interface
+	redis redis.Interface
+	json  parser.JSONInterface
+}
+
+type InitParam struct {
+	Db    sql.Interface
+	Log   log.Interface
+	Redis redis.Interface
+	Json  parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &contact{
+		db:    param.Db,
+		log:   param.Log,
+		redis: param.Redis,
+		json:  param.Json,
+	}
+}
+
+func (c *contact) GetList(ctx context.Context, param entity.ContactParam) ([]entity.Contact, *entity.Pagination, error) {
+	if !param.BypassCache {
+		contacts, pg, err := c.getCacheList(ctx, param)
+		switch {
+		case errors.Is(err, redis.Nil):
+			c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return contacts, &pg, nil
+		}
+	}
+
+	contacts, pg, err := c.getListSQL(ctx, param)
+	if err != nil {
+		return contacts, pg, err
+	}
+
+	err = c.upsertCacheList(ctx, param, contacts, *pg, c.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return contacts, pg, nil
+}
+
+func (c *contact) Get(ctx context.Context, param entity.ContactParam) (entity.Contact, error) {
+	contact := entity.Contact{}
+
+	marshalledParam, err := c.json.Marshal(param)
+	if err != nil {
+		return contact, err
+	}
+
+	if !param.BypassCache {
+		contact, err = c.getCache(ctx, fmt.Sprintf(getContactByKey, string(marshalledParam)))
+		switch {
+		case errors.Is(err, redis.Nil):
+			c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return contact, nil
+		}
+	}
+
+	contact, err = c.getSQL(ctx, param)
+	if err != nil {
+		return contact, err
+	}
+
+	err = c.upsertCache(ctx, fmt.Sprintf(getContactByKey, string(marshalledParam)), contact, c.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return contact, nil
+}
+
+func (c *contact) Create(ctx context.Context, inputParam entity.ContactInputParam) (entity.Contact, error) {
+	contact, err := c.createSQL(ctx, inputParam)
+	if err != nil {
+		return contact, err
+	}
+
+	err = c.deleteCache(ctx)
+	if err != nil {
+		c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return contact, nil
+}
+
+func (c *contact) Update(ctx context.Context, updateParam entity.ContactUpdateParam, selectParam entity.ContactParam) error {
+	err := c.updateSQL(ctx, updateParam, selectParam)
+	if err != nil {
+		return err
+	}
+
+	err = c.deleteCache(ctx)
+	if err != nil {
+		c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (c *contact) GetUserList(ctx context.Context, param entity.ContactUserParam) ([]entity.ContactUser, *entity.Pagination, error) {
+	return c.getUserListSQL(ctx, param)
+}
+
+func (c *contact) EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return c.eraseTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (c *contact) DeleteCache(ctx context.Context) error {
+	return c.deleteCache(ctx)
+}
@@ -0,0 +1,104 @@
+package contact
+
+const (
+	insertContact = `
+		INSERT INTO contact
+		(
+			fk_user_id,
+			fk_contact_id,
+			contact_status,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:fk_contact_id,
+			:contact_status,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readContact = `
+		SELECT
+			id,
+			fk_user_id,
+			fk_contact_id,
+			contact_status,
+			responded_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			contact
+	`
+
+	countContact = `
+		SELECT
+			COUNT(*)
+		FROM
+			contact
+	`
+
+	updateContact = `
+		UPDATE
+			contact
+	`
+
+	// the other side of every accepted contact of a user, excluded users are joined into the %s placeholder
+	readContactUser = `
+		SELECT
+			u.id,
+			u.name,
+			c.responded_at AS since
+		FROM
+			contact c
+			JOIN user u ON u.id = IF(c.fk_user_id = ?, c.fk_contact_id, c.fk_user_id)
+		WHERE
+			(c.fk_user_id = ? OR c.fk_contact_id = ?)
+			AND c.contact_status = 'accepted'
+			AND c.status = 1
+			AND u.status = 1
+			%s
+		ORDER BY
+			u.name ASC,
+			u.id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	countContactUser = `
+		SELECT
+			COUNT(*)
+		FROM
+			contact c
+			JOIN user u ON u.id = IF(c.fk_user_id = ?, c.fk_contact_id, c.fk_user_id)
+		WHERE
+			(c.fk_user_id = ? OR c.fk_contact_id = ?)
+			AND c.contact_status = 'accepted'
+			AND c.status = 1
+			AND u.status = 1
+			%s
+	`
+
+	// contacts are erased from both sides, the requests the user sent and the ones they received
+	eraseUserContact = `
+		UPDATE
+			contact
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			(fk_user_id = ? OR fk_contact_id = ?)
+			AND status = 1
+	`
+)
@@ -0,0 +1,124 @@
+package contact
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	getContactByKey           = "boilerplate:contact:get:%s"
+	getContactByQueryKey      = "boilerplate:contact:get:q:%s"
+	getContactByPaginationKey = "boilerplate:contact:get:p:%s"
+	deleteContactKeysPattern  = "boilerplate:contact*"
+)
+
+func (c *contact) upsertCache(ctx context.Context, key string, contact entity.Contact, ttl time.Duration) error {
+	marshalledContact, err := c.json.Marshal(contact)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = c.redis.SetEX(ctx, key, string(marshalledContact), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (c *contact) getCache(ctx context.Context, key string) (entity.Contact, error) {
+	contact := entity.Contact{}
+
+	marshalledContact, err := c.redis.Get(ctx, key)
+	if err != nil {
+		return contact, err
+	}
+
+	err = c.json.Unmarshal([]byte(marshalledContact), &contact)
+	if err != nil {
+		return contact, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return contact, nil
+}
+
+func (c *contact) upsertCacheList(ctx context.Context, param entity.ContactParam, contacts []entity.Contact, pg entity.Pagination, ttl time.Duration) error {
+	keyValue, err := c.json.Marshal(param)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// set contact to cache
+	marshalledContact, err := c.json.Marshal(contacts)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = c.redis.SetEX(ctx, fmt.Sprintf(getContactByQueryKey, string(keyValue)), string(marshalledContact), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	// set pagination to cache
+	marshalledPagination, err := c.json.Marshal(pg)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = c.redis.SetEX(ctx, fmt.Sprintf(getContactByPaginationKey, string(keyValue)), string(marshalledPagination), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (c *contact) getCacheList(ctx context.Context, param entity.ContactParam) ([]entity.Contact, entity.Pagination, error) {
+	var (
+		contacts = []entity.Contact{}
+		pg       = entity.Pagination{}
+	)
+
+	keyValue, err := c.json.Marshal(param)
+	if err != nil {
+		return contacts, pg, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// get contact from redis
+	marshalledContact, err := c.redis.Get(ctx, fmt.Sprintf(getContactByQueryKey, string(keyValue)))
+	if err != nil {
+		return contacts, pg, err
+	}
+
+	err = c.json.Unmarshal([]byte(marshalledContact), &contacts)
+	if err != nil {
+		return contacts, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	// get pagination from redis
+	marshalledPagination, err := c.redis.Get(ctx, fmt.Sprintf(getContactByPaginationKey, string(keyValue)))
+	if err != nil {
+		return contacts, pg, err
+	}
+
+	err = c.json.Unmarshal([]byte(marshalledPagination), &pg)
+	if err != nil {
+		return contacts, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return contacts, pg, nil
+}
+
+func (c *contact) deleteCache(ctx context.Context) error {
+	err := c.redis.Del(ctx, deleteContactKeysPattern)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package contact
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (c *contact) createSQL(ctx context.Context, inputParam entity.ContactInputParam) (entity.Contact, error) {
+	contact := entity.Contact{}
+
+	c.log.Debug(ctx, fmt.Sprintf("create contact request from user %v to user %v", inputParam.UserID, inputParam.ContactID))
+
+	tx, err := c.db.Leader().BeginTx(ctx, "txContact", sql.TxOptions{})
+	if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewContact", insertContact, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return contact, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return contact, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no contact created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	c.log.Debug(ctx, fmt.Sprintf("success create contact request from user %v to user %v", inputParam.UserID, inputParam.ContactID))
+
+	contact = entity.Contact{
+		ID:            lastID,
+		UserID:        inputParam.UserID,
+		ContactID:     inputParam.ContactID,
+		ContactStatus: inputParam.ContactStatus,
+		Status:        1,
+		CreatedAt:     inputParam.CreatedAt,
+		CreatedBy:     inputParam.CreatedBy,
+	}
+
+	return contact, nil
+}
+
+func (c *contact) getSQL(ctx context.Context, param entity.ContactParam) (entity.Contact, error) {
+	contact := entity.Contact{}
+
+	c.log.Debug(ctx, fmt.Sprintf("get contact with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := c.db.Follower().QueryRow(ctx, "rContact", readContact+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return contact, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&contact); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return contact, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return contact, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	c.log.Debug(ctx, fmt.Sprintf("success get contact with body: %v", param))
+
+	return contact, nil
+}
+
+func (c *contact) getListSQL(ctx context.Context, param entity.ContactParam) ([]entity.Contact, *entity.Pagination, error) {
+	contacts := []entity.Contact{}
+
+	c.log.Debug(ctx, fmt.Sprintf("get contact list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return contacts, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := c.db.Follower().Query(ctx, "rContactList", readContact+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return contacts, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		contact := entity.Contact{}
+		err := rows.StructScan(&contact)
+		if err != nil {
+			return contacts, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(contacts)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(contacts) > 0 && param.IncludePagination {
+		err := c.db.Follower().Get(ctx, "cContactList", countContact+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return contacts, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	c.log.Debug(ctx, fmt.Sprintf("success get contact list with body: %v", param))
+
+	return contacts, &pg, nil
+}
+
+func (c *contact) updateSQL(ctx context.Context, updateParam entity.ContactUpdateParam, selectParam entity.ContactParam) error {
+	c.log.Debug(ctx, fmt.Sprintf("update contact %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := c.db.Leader().BeginTx(ctx, "txContact", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uContact", updateContact+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no contact updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	c.log.Debug(ctx, fmt.Sprintf("success update contact %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (c *contact) getUserListSQL(ctx context.Context, param entity.ContactUserParam) ([]entity.ContactUser, *entity.Pagination, error) {
+	contactUsers := []entity.ContactUser{}
+
+	c.log.Debug(ctx, fmt.Sprintf("get contact user list with body: %v", param))
+
+	if param.Limit < 1 {
+		param.Limit = 10
+	}
+
+	if param.Page < 1 {
+		param.Page = 1
+	}
+
+	exclude := ""
+	args := []interface{}{param.UserID, param.UserID, param.UserID}
+	if len(param.ExcludeUserIDs) > 0 {
+		exclude = "AND u.id NOT IN (?" + strings.Repeat(", ?", len(param.ExcludeUserIDs)-1) + ")"
+		for _, userID := range param.ExcludeUserIDs {
+			args = append(args, userID)
+		}
+	}
+
+	rows, err := c.db.Follower().Query(ctx, "rContactUserList", fmt.Sprintf(readContactUser, exclude), append(args, param.Limit, (param.Page-1)*param.Limit)...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return contactUsers, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		contactUser := entity.ContactUser{}
+		err := rows.StructScan(&contactUser)
+		if err != nil {
+			return contactUsers, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		contactUsers = append(contactUsers, contactUser)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.Page,
+		CurrentElements: int64(len(contactUsers)),
+		SortBy:          param.SortBy,
+	}
+
+	if len(contactUsers) > 0 {
+		err := c.db.Follower().Get(ctx, "cContactUserList", fmt.Sprintf(countContactUser, exclude), &pg.TotalElements, args...)
+		if err != nil {
+			return contactUsers, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	c.log.Debug(ctx, fmt.Sprintf("success get contact user list with body: %v", param))
+
+	return contactUsers, &pg, nil
+}
+
+func (c *contact) eraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	c.log.Debug(ctx, fmt.Sprintf("erase contacts of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserContact", eraseUserContact, erasedAt, erasedBy, erasedAt, erasedBy, userID, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	c.log.Debug(ctx, fmt.Sprintf("success erase contacts of user %v", userID))
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package devicekey
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	// Upsert publishes the device keys and appends its new one-time prekeys in one transaction
+	Upsert(ctx context.Context, inputParam entity.DeviceKeyInputParam) error
+	Get(ctx context.Context, param entity.DeviceKeyParam) (entity.DeviceKey, error)
+	GetList(ctx context.Context, param entity.DeviceKeyParam) ([]entity.DeviceKey, *entity.Pagination, error)
+	// CountOneTimePrekey returns how many one-time prekeys of the device are left to claim
+	CountOneTimePrekey(ctx context.Context, deviceID int64) (int64, error)
+	// ClaimOneTimePrekey hands out the oldest unclaimed prekey of the device, each prekey is claimed once
+	ClaimOneTimePrekey(ctx context.Context, param entity.OneTimePrekeyClaimParam) (entity.OneTimePrekey, error)
+	// EraseTx deactivates every published key and unclaimed prekey of the user inside tx
+	EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+}
+
+type devicekey struct {
+	db  sql.Interface
+	log log.Interface
+}
+
+type InitParam struct {
+	Db  sql.Interface
+	Log log.Interface
+}
+
+// Init creates the device key domain, prekeys are claimed under row locks so they are not cached
+func Init(param InitParam) Interface {
+	return &devicekey{
+		db:  param.Db,
+		log: param.Log,
+	}
+}
+
+func (d *devicekey) Upsert(ctx context.Context, inputParam entity.DeviceKeyInputParam) error {
+	return d.upsertSQL(ctx, inputParam)
+}
+
+func (d *devicekey) Get(ctx context.Context, param entity.DeviceKeyParam) (entity.DeviceKey, error) {
+	return d.getSQL(ctx, param)
+}
+
+func (d *devicekey) GetList(ctx context.Context, param entity.DeviceKeyParam) ([]entity.DeviceKey, *entity.Pagination, error) {
+	return d.getListSQL(ctx, param)
+}
+
+func (d *devicekey) CountOneTimePrekey(ctx context.Context, deviceID int64) (int64, error) {
+	return d.countOneTimePrekeySQL(ctx, deviceID)
+}
+
+func (d *devicekey) ClaimOneTimePrekey(ctx context.Context, param entity.OneTimePrekeyClaimParam) (entity.OneTimePrekey, error) {
+	return d.claimOneTimePrekeySQL(ctx, param)
+}
+
+func (d *devicekey) EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return d.eraseTx(ctx, tx, userID, erasedAt, erasedBy)
+}
@@ -0,0 +1,174 @@
+package devicekey
+
+const (
+	upsertDeviceKey = `
+		INSERT INTO device_key
+		(
+			fk_user_id,
+			fk_device_id,
+			identity_key,
+			signed_prekey_id,
+			signed_prekey,
+			signed_prekey_signature,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:fk_device_id,
+			:identity_key,
+			:signed_prekey_id,
+			:signed_prekey,
+			:signed_prekey_signature,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			fk_user_id = VALUES(fk_user_id),
+			identity_key = VALUES(identity_key),
+			signed_prekey_id = VALUES(signed_prekey_id),
+			signed_prekey = VALUES(signed_prekey),
+			signed_prekey_signature = VALUES(signed_prekey_signature),
+			status = 1,
+			updated_at = VALUES(created_at),
+			updated_by = VALUES(created_by)
+	`
+
+	readDeviceKey = `
+		SELECT
+			id,
+			fk_user_id,
+			fk_device_id,
+			identity_key,
+			signed_prekey_id,
+			signed_prekey,
+			signed_prekey_signature,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			device_key
+	`
+
+	countDeviceKey = `
+		SELECT
+			COUNT(*)
+		FROM
+			device_key
+	`
+
+	// a key id the device already uploaded keeps its original row, claimed or not
+	insertOneTimePrekey = `
+		INSERT INTO one_time_prekey
+		(
+			fk_user_id,
+			fk_device_id,
+			key_id,
+			public_key,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:fk_device_id,
+			:key_id,
+			:public_key,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			id = id
+	`
+
+	resetOneTimePrekey = `
+		UPDATE
+			one_time_prekey
+		SET
+			status = 0,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_device_id = ?
+			AND status = 1
+	`
+
+	countOneTimePrekey = `
+		SELECT
+			COUNT(*)
+		FROM
+			one_time_prekey
+		WHERE
+			fk_device_id = ?
+			AND status = 1
+	`
+
+	readUnclaimedOneTimePrekey = `
+		SELECT
+			id,
+			fk_user_id,
+			fk_device_id,
+			key_id,
+			public_key,
+			fk_claimed_by,
+			claimed_at,
+			status,
+			created_at,
+			created_by
+		FROM
+			one_time_prekey
+		WHERE
+			fk_device_id = ?
+			AND status = 1
+		ORDER BY
+			id ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	markOneTimePrekeyClaimed = `
+		UPDATE
+			one_time_prekey
+		SET
+			status = 0,
+			fk_claimed_by = ?,
+			claimed_at = ?
+		WHERE
+			id = ?
+	`
+
+	eraseUserDeviceKey = `
+		UPDATE
+			device_key
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+
+	eraseUserOneTimePrekey = `
+		UPDATE
+			one_time_prekey
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+)
@@ -0,0 +1,203 @@
+package devicekey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (d *devicekey) upsertSQL(ctx context.Context, inputParam entity.DeviceKeyInputParam) error {
+	d.log.Debug(ctx, fmt.Sprintf("upsert device key for device %v", inputParam.DeviceID))
+
+	tx, err := d.db.Leader().BeginTx(ctx, "txDeviceKey", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iuDeviceKey", upsertDeviceKey, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if inputParam.ResetPrekeys {
+		_, err = tx.Exec("uResetOneTimePrekey", resetOneTimePrekey, inputParam.CreatedAt, inputParam.CreatedBy, inputParam.DeviceID)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+		}
+	}
+
+	for _, prekey := range inputParam.OneTimePrekeys {
+		_, err = tx.NamedExec("iNewOneTimePrekey", insertOneTimePrekey, prekey)
+		if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+			return errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+		} else if err != nil {
+			return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	d.log.Debug(ctx, fmt.Sprintf("success upsert device key for device %v with %v one-time prekeys", inputParam.DeviceID, len(inputParam.OneTimePrekeys)))
+
+	return nil
+}
+
+func (d *devicekey) getSQL(ctx context.Context, param entity.DeviceKeyParam) (entity.DeviceKey, error) {
+	deviceKey := entity.DeviceKey{}
+
+	d.log.Debug(ctx, fmt.Sprintf("get device key with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return deviceKey, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := d.db.Follower().QueryRow(ctx, "rDeviceKey", readDeviceKey+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return deviceKey, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&deviceKey); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return deviceKey, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return deviceKey, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	d.log.Debug(ctx, fmt.Sprintf("success get device key with body: %v", param))
+
+	return deviceKey, nil
+}
+
+func (d *devicekey) getListSQL(ctx context.Context, param entity.DeviceKeyParam) ([]entity.DeviceKey, *entity.Pagination, error) {
+	deviceKeys := []entity.DeviceKey{}
+
+	d.log.Debug(ctx, fmt.Sprintf("get device key list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return deviceKeys, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := d.db.Follower().Query(ctx, "rDeviceKeyList", readDeviceKey+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return deviceKeys, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		deviceKey := entity.DeviceKey{}
+		err := rows.StructScan(&deviceKey)
+		if err != nil {
+			return deviceKeys, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		deviceKeys = append(deviceKeys, deviceKey)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(deviceKeys)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(deviceKeys) > 0 && param.IncludePagination {
+		err := d.db.Follower().Get(ctx, "cDeviceKeyList", countDeviceKey+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return deviceKeys, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	d.log.Debug(ctx, fmt.Sprintf("success get device key list with body: %v", param))
+
+	return deviceKeys, &pg, nil
+}
+
+func (d *devicekey) countOneTimePrekeySQL(ctx context.Context, deviceID int64) (int64, error) {
+	var count int64
+
+	err := d.db.Follower().Get(ctx, "cOneTimePrekey", countOneTimePrekey, &count, deviceID)
+	if err != nil {
+		return count, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	return count, nil
+}
+
+// claimOneTimePrekeySQL skips rows locked by concurrent claims, so two peers never get the same prekey
+func (d *devicekey) claimOneTimePrekeySQL(ctx context.Context, param entity.OneTimePrekeyClaimParam) (entity.OneTimePrekey, error) {
+	prekey := entity.OneTimePrekey{}
+
+	tx, err := d.db.Leader().BeginTx(ctx, "txOneTimePrekey", sql.TxOptions{})
+	if err != nil {
+		return prekey, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("rUnclaimedOneTimePrekey", readUnclaimedOneTimePrekey, param.DeviceID)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return prekey, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	found := false
+	for rows.Next() {
+		err := rows.StructScan(&prekey)
+		if err != nil {
+			rows.Close()
+			return prekey, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		found = true
+	}
+	rows.Close()
+
+	if !found {
+		return prekey, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, "no one-time prekey left for device %v", param.DeviceID)
+	}
+
+	_, err = tx.Exec("uOneTimePrekeyClaimed", markOneTimePrekeyClaimed, param.ClaimedBy, param.ClaimedAt, prekey.ID)
+	if err != nil {
+		return prekey, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return prekey, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	d.log.Debug(ctx, fmt.Sprintf("success claim one-time prekey %v of device %v", prekey.KeyID, param.DeviceID))
+
+	return prekey, nil
+}
+
+func (d *devicekey) eraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	d.log.Debug(ctx, fmt.Sprintf("erase device keys of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserDeviceKey", eraseUserDeviceKey, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	_, err = tx.Exec("uEraseUserOneTimePrekey", eraseUserOneTimePrekey, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	d.log.Debug(ctx, fmt.Sprintf("success erase device keys of user %v", userID))
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/redis"
+)
+
+type Interface interface {
+	// GetLastSent returns an invalid time when no digest run has been recorded yet
+	GetLastSent(ctx context.Context) (null.Time, error)
+	// SetLastSent records the end of the window covered by the latest digest run
+	SetLastSent(ctx context.Context, lastSent time.Time, ttl time.Duration) error
+	// GetUserLastSent returns an invalid time when no digest run has covered the user yet
+	GetUserLastSent(ctx context.Context, userID int64) (null.Time, error)
+	// SetUserLastSent records the end of the window the user has been handled for
+	SetUserLastSent(ctx context.Context, userID int64, lastSent time.Time, ttl time.Duration) error
+}
+
+type digest struct {
+	log   log.Interface
+	redis redis.Interface
+}
+
+type InitParam struct {
+	Log   log.Interface
+	Redis redis.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &digest{
+		log:   param.Log,
+		redis: param.Redis,
+	}
+}
+
+func (d *digest) GetLastSent(ctx context.Context) (null.Time, error) {
+	return d.getCache(ctx, getDigestLastSentKey)
+}
+
+func (d *digest) SetLastSent(ctx context.Context, lastSent time.Time, ttl time.Duration) error {
+	return d.upsertCache(ctx, getDigestLastSentKey, lastSent, ttl)
+}
+
+func (d *digest) GetUserLastSent(ctx context.Context, userID int64) (null.Time, error) {
+	return d.getCache(ctx, fmt.Sprintf(getDigestUserLastSentKey, userID))
+}
+
+func (d *digest) SetUserLastSent(ctx context.Context, userID int64, lastSent time.Time, ttl time.Duration) error {
+	return d.upsertCache(ctx, fmt.Sprintf(getDigestUserLastSentKey, userID), lastSent, ttl)
+}
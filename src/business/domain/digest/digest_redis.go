@@ -0,0 +1,41 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/redis"
+)
+
+const (
+	getDigestLastSentKey     = "boilerplate:digest:get:last_sent"
+	getDigestUserLastSentKey = "boilerplate:digest:user:%d:get:last_sent"
+)
+
+func (d *digest) upsertCache(ctx context.Context, key string, lastSent time.Time, ttl time.Duration) error {
+	err := d.redis.SetEX(ctx, key, lastSent.UTC().Format(time.RFC3339), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (d *digest) getCache(ctx context.Context, key string) (null.Time, error) {
+	value, err := d.redis.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return null.Time{}, nil
+	} else if err != nil {
+		return null.Time{}, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	lastSent, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return null.Time{}, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return null.TimeFrom(lastSent), nil
+}
@@ -1,27 +1,103 @@
 package domain
 
 import (
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/reyhanmichiels/go-pkg/log"
 	"github.com/reyhanmichiels/go-pkg/parser"
 	"github.com/reyhanmichiels/go-pkg/redis"
 	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/contact"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/devicekey"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/digest"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/event"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/export"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/idempotency"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/legalhold"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/presence"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/report"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/retention"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/webhook"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
 )
 
 type Domains struct {
-	User user.Interface
+	User         user.Interface
+	Webhook      webhook.Interface
+	Outbox       outbox.Interface
+	Audit        audit.Interface
+	Relation     relation.Interface
+	Contact      contact.Interface
+	Presence     presence.Interface
+	Workspace    workspace.Interface
+	Notification notification.Interface
+	Preference   preference.Interface
+	Digest       digest.Interface
+	Event        event.Interface
+	Idempotency  idempotency.Interface
+	DeviceKey    devicekey.Interface
+	Retention    retention.Interface
+	LegalHold    legalhold.Interface
+	Export       export.Interface
+	Report       report.Interface
 }
 
 type InitParam struct {
-	Log   log.Interface
-	Db    sql.Interface
-	Redis redis.Interface
-	Json  parser.JSONInterface
-	// TODO: add audit
+	Log        log.Interface
+	Db         sql.Interface
+	Redis      redis.Interface
+	Json       parser.JSONInterface
+	Stream     goredis.UniversalClient
+	StreamWait goredis.UniversalClient
 }
 
 func Init(param InitParam) *Domains {
+	outbox := outbox.Init(outbox.InitParam{Db: param.Db, Log: param.Log, Json: param.Json})
+	notification := notification.Init(notification.InitParam{Db: param.Db, Log: param.Log, Outbox: outbox})
+	deviceKey := devicekey.Init(devicekey.InitParam{Db: param.Db, Log: param.Log})
+	webhook := webhook.Init(webhook.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json})
+	relation := relation.Init(relation.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json})
+	contact := contact.Init(contact.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json})
+	workspace := workspace.Init(workspace.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json, Outbox: outbox})
+	preference := preference.Init(preference.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json})
+	user := user.Init(user.InitParam{
+		Db:           param.Db,
+		Log:          param.Log,
+		Redis:        param.Redis,
+		Json:         param.Json,
+		Outbox:       outbox,
+		Notification: notification,
+		DeviceKey:    deviceKey,
+		Workspace:    workspace,
+		Webhook:      webhook,
+		Contact:      contact,
+		Relation:     relation,
+		Preference:   preference,
+	})
+
 	return &Domains{
-		User: user.Init(user.InitParam{Db: param.Db, Log: param.Log, Redis: param.Redis, Json: param.Json}),
+		User:         user,
+		Webhook:      webhook,
+		Outbox:       outbox,
+		Audit:        audit.Init(audit.InitParam{Db: param.Db, Log: param.Log, Json: param.Json}),
+		Relation:     relation,
+		Contact:      contact,
+		Presence:     presence.Init(presence.InitParam{Log: param.Log, Redis: param.Redis}),
+		Workspace:    workspace,
+		Notification: notification,
+		Preference:   preference,
+		Digest:       digest.Init(digest.InitParam{Log: param.Log, Redis: param.Redis}),
+		Event:        event.Init(event.InitParam{Log: param.Log, Stream: param.Stream, StreamWait: param.StreamWait, Json: param.Json}),
+		Idempotency:  idempotency.Init(idempotency.InitParam{Log: param.Log, Redis: param.Redis, Json: param.Json}),
+		DeviceKey:    deviceKey,
+		Retention:    retention.Init(retention.InitParam{Db: param.Db, Log: param.Log}),
+		LegalHold:    legalhold.Init(legalhold.InitParam{Db: param.Db, Log: param.Log}),
+		Export:       export.Init(export.InitParam{Db: param.Db, Log: param.Log}),
+		Report:       report.Init(report.InitParam{Db: param.Db, Log: param.Log, User: user}),
 	}
 }
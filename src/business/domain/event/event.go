@@ -0,0 +1,69 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	// Append adds the event to the log of the user, keeping at most maxLen events for ttl. An event id that
+	// is already in the log is not added again and an empty position is returned.
+	Append(ctx context.Context, userID int64, event entity.Event, maxLen int64, ttl time.Duration) (string, error)
+	// GetList returns up to count events logged after afterID
+	GetList(ctx context.Context, userID int64, afterID string, count int64) ([]entity.StreamEvent, error)
+	// Wait blocks up to block for events logged after afterID, an empty list means none arrived
+	Wait(ctx context.Context, userID int64, afterID string, count int64, block time.Duration) ([]entity.StreamEvent, error)
+	// GetOldestID and GetLatestID return an empty id when the log is empty or expired
+	GetOldestID(ctx context.Context, userID int64) (string, error)
+	GetLatestID(ctx context.Context, userID int64) (string, error)
+}
+
+type event struct {
+	log    log.Interface
+	stream redis.UniversalClient
+	// streamWait only serves blocking reads so they cannot exhaust the pool of stream
+	streamWait redis.UniversalClient
+	json       parser.JSONInterface
+}
+
+type InitParam struct {
+	Log        log.Interface
+	Stream     redis.UniversalClient
+	StreamWait redis.UniversalClient
+	Json       parser.JSONInterface
+}
+
+// Init creates the per-user event log, it is kept in redis streams which the key value cache client does not cover
+func Init(param InitParam) Interface {
+	return &event{
+		log:        param.Log,
+		stream:     param.Stream,
+		streamWait: param.StreamWait,
+		json:       param.Json,
+	}
+}
+
+func (e *event) Append(ctx context.Context, userID int64, event entity.Event, maxLen int64, ttl time.Duration) (string, error) {
+	return e.appendStream(ctx, userID, event, maxLen, ttl)
+}
+
+func (e *event) GetList(ctx context.Context, userID int64, afterID string, count int64) ([]entity.StreamEvent, error) {
+	return e.getListStream(ctx, userID, afterID, count)
+}
+
+func (e *event) Wait(ctx context.Context, userID int64, afterID string, count int64, block time.Duration) ([]entity.StreamEvent, error) {
+	return e.waitStream(ctx, userID, afterID, count, block)
+}
+
+func (e *event) GetOldestID(ctx context.Context, userID int64) (string, error) {
+	return e.getEdgeIDStream(ctx, userID, false)
+}
+
+func (e *event) GetLatestID(ctx context.Context, userID int64) (string, error) {
+	return e.getEdgeIDStream(ctx, userID, true)
+}
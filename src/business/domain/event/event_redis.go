@@ -0,0 +1,126 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	eventStreamByUserKey = "boilerplate:event:user:%d"
+	eventAppendedKey     = "boilerplate:event:user:%d:appended:%s"
+	eventField           = "event"
+)
+
+func (e *event) appendStream(ctx context.Context, userID int64, event entity.Event, maxLen int64, ttl time.Duration) (string, error) {
+	key := fmt.Sprintf(eventStreamByUserKey, userID)
+
+	raw, err := e.json.Marshal(event)
+	if err != nil {
+		return "", errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// the outbox can relay an event more than once, the marker lives as long as the log so it is appended once
+	appendedKey := fmt.Sprintf(eventAppendedKey, userID, event.ID)
+	isNew, err := e.stream.SetNX(ctx, appendedKey, 1, ttl).Result()
+	if err != nil {
+		return "", errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	} else if !isNew {
+		return "", nil
+	}
+
+	id, err := e.stream.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]interface{}{eventField: string(raw)},
+	}).Result()
+	if err != nil {
+		e.stream.Del(ctx, appendedKey)
+		return "", errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	if err := e.stream.Expire(ctx, key, ttl).Err(); err != nil {
+		return id, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return id, nil
+}
+
+func (e *event) getListStream(ctx context.Context, userID int64, afterID string, count int64) ([]entity.StreamEvent, error) {
+	// "(" makes the start of the range exclusive
+	messages, err := e.stream.XRangeN(ctx, fmt.Sprintf(eventStreamByUserKey, userID), "("+afterID, "+", count).Result()
+	if err != nil {
+		return nil, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return e.decode(messages)
+}
+
+func (e *event) waitStream(ctx context.Context, userID int64, afterID string, count int64, block time.Duration) ([]entity.StreamEvent, error) {
+	streams, err := e.streamWait.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{fmt.Sprintf(eventStreamByUserKey, userID), afterID},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	events := []entity.StreamEvent{}
+	for _, stream := range streams {
+		decoded, err := e.decode(stream.Messages)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded...)
+	}
+
+	return events, nil
+}
+
+func (e *event) getEdgeIDStream(ctx context.Context, userID int64, latest bool) (string, error) {
+	key := fmt.Sprintf(eventStreamByUserKey, userID)
+
+	var cmd *redis.XMessageSliceCmd
+	if latest {
+		cmd = e.stream.XRevRangeN(ctx, key, "+", "-", 1)
+	} else {
+		cmd = e.stream.XRangeN(ctx, key, "-", "+", 1)
+	}
+
+	messages, err := cmd.Result()
+	if err != nil {
+		return "", errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	if len(messages) == 0 {
+		return "", nil
+	}
+
+	return messages[0].ID, nil
+}
+
+func (e *event) decode(messages []redis.XMessage) ([]entity.StreamEvent, error) {
+	events := make([]entity.StreamEvent, 0, len(messages))
+
+	for _, message := range messages {
+		raw, _ := message.Values[eventField].(string)
+
+		event := entity.Event{}
+		if err := e.json.Unmarshal([]byte(raw), &event); err != nil {
+			return nil, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+		}
+
+		events = append(events, entity.StreamEvent{ID: message.ID, Event: event})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.ExportInputParam) (entity.Export, error)
+	Get(ctx context.Context, param entity.ExportParam) (entity.Export, error)
+	GetList(ctx context.Context, param entity.ExportParam) ([]entity.Export, *entity.Pagination, error)
+	Update(ctx context.Context, updateParam entity.ExportUpdateParam, selectParam entity.ExportParam) error
+	// ClaimPending marks the oldest pending export as running and returns it, each export is claimed once
+	// unless it is still running past staleBefore
+	ClaimPending(ctx context.Context, startedAt, staleBefore time.Time) (entity.Export, error)
+	CreateChunk(ctx context.Context, chunk entity.ExportChunk) error
+	GetChunk(ctx context.Context, exportID, chunkIndex int64) (entity.ExportChunk, error)
+	// DeleteChunks removes the archive of an export, including the leftovers of an unfinished build
+	DeleteChunks(ctx context.Context, exportID int64) error
+	// DeleteChunksBefore removes at most limit chunks stored before the given time and returns how many were removed
+	DeleteChunksBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	// GetNotificationList returns a page of the notifications received by the subject within the range
+	GetNotificationList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Notification, error)
+	// GetAuditList returns a page of the audit entries of actions performed by the subject within the range
+	GetAuditList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Audit, error)
+}
+
+type export struct {
+	db  sql.Interface
+	log log.Interface
+}
+
+type InitParam struct {
+	Db  sql.Interface
+	Log log.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &export{
+		db:  param.Db,
+		log: param.Log,
+	}
+}
+
+func (e *export) Create(ctx context.Context, inputParam entity.ExportInputParam) (entity.Export, error) {
+	return e.createSQL(ctx, inputParam)
+}
+
+func (e *export) Get(ctx context.Context, param entity.ExportParam) (entity.Export, error) {
+	return e.getSQL(ctx, param)
+}
+
+func (e *export) GetList(ctx context.Context, param entity.ExportParam) ([]entity.Export, *entity.Pagination, error) {
+	return e.getListSQL(ctx, param)
+}
+
+func (e *export) Update(ctx context.Context, updateParam entity.ExportUpdateParam, selectParam entity.ExportParam) error {
+	return e.updateSQL(ctx, updateParam, selectParam)
+}
+
+func (e *export) ClaimPending(ctx context.Context, startedAt, staleBefore time.Time) (entity.Export, error) {
+	return e.claimPendingSQL(ctx, startedAt, staleBefore)
+}
+
+func (e *export) CreateChunk(ctx context.Context, chunk entity.ExportChunk) error {
+	return e.createChunkSQL(ctx, chunk)
+}
+
+func (e *export) GetChunk(ctx context.Context, exportID, chunkIndex int64) (entity.ExportChunk, error) {
+	return e.getChunkSQL(ctx, exportID, chunkIndex)
+}
+
+func (e *export) DeleteChunks(ctx context.Context, exportID int64) error {
+	return e.deleteChunksSQL(ctx, exportID)
+}
+
+func (e *export) DeleteChunksBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return e.deleteChunksBeforeSQL(ctx, before, limit)
+}
+
+func (e *export) GetNotificationList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Notification, error) {
+	return e.getNotificationListSQL(ctx, param)
+}
+
+func (e *export) GetAuditList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Audit, error) {
+	return e.getAuditListSQL(ctx, param)
+}
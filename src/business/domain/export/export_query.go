@@ -0,0 +1,190 @@
+package export
+
+const (
+	insertExport = `
+		INSERT INTO compliance_export
+		(
+			fk_requested_by,
+			subject_type,
+			subject_id,
+			range_start,
+			range_end,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_requested_by,
+			:subject_type,
+			:subject_id,
+			:range_start,
+			:range_end,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readExport = `
+		SELECT
+			id,
+			fk_requested_by,
+			subject_type,
+			subject_id,
+			range_start,
+			range_end,
+			export_status,
+			file_name,
+			file_size,
+			error_message,
+			started_at,
+			completed_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			compliance_export
+	`
+
+	countExport = `
+		SELECT
+			COUNT(*)
+		FROM
+			compliance_export
+	`
+
+	updateExport = `
+		UPDATE
+			compliance_export
+	`
+
+	// a running export that outlived the run timeout was left behind by a replica that died mid build
+	readPendingExport = readExport + `
+		WHERE
+			(export_status = 'pending' OR (export_status = 'running' AND started_at < ?))
+			AND status = 1
+		ORDER BY
+			id ASC
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	markExportRunning = `
+		UPDATE
+			compliance_export
+		SET
+			export_status = 'running',
+			started_at = ?
+		WHERE
+			id = ?
+	`
+
+	insertExportChunk = `
+		INSERT INTO compliance_export_chunk
+		(
+			fk_export_id,
+			chunk_index,
+			content,
+			created_at
+		)
+		VALUES
+		(
+			:fk_export_id,
+			:chunk_index,
+			:content,
+			:created_at
+		)
+	`
+
+	readExportChunk = `
+		SELECT
+			fk_export_id,
+			chunk_index,
+			content,
+			created_at
+		FROM
+			compliance_export_chunk
+		WHERE
+			fk_export_id = ?
+			AND chunk_index = ?
+	`
+
+	deleteExportChunk = `
+		DELETE FROM
+			compliance_export_chunk
+		WHERE
+			fk_export_id = ?
+	`
+
+	deleteExportChunkBefore = `
+		DELETE FROM
+			compliance_export_chunk
+		WHERE
+			created_at < ?
+		LIMIT ?
+	`
+
+	readExportNotification = `
+		SELECT
+			id,
+			fk_user_id,
+			fk_actor_id,
+			notification_type,
+			scope_type,
+			scope_id,
+			title,
+			body,
+			read_status,
+			read_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			notification
+		WHERE
+			fk_user_id = ?
+			AND created_at >= ?
+			AND created_at < ?
+			AND id > ?
+		ORDER BY
+			id ASC
+		LIMIT ?
+	`
+
+	readExportAudit = `
+		SELECT
+			id,
+			fk_actor_id,
+			action,
+			target_type,
+			target_id,
+			request_id,
+			ip_address,
+			user_agent,
+			before_value,
+			after_value,
+			status,
+			created_at
+		FROM
+			audit_log
+		WHERE
+			fk_actor_id = ?
+			AND created_at >= ?
+			AND created_at < ?
+			AND id > ?
+		ORDER BY
+			id ASC
+		LIMIT ?
+	`
+)
@@ -0,0 +1,369 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (e *export) createSQL(ctx context.Context, inputParam entity.ExportInputParam) (entity.Export, error) {
+	export := entity.Export{}
+
+	e.log.Debug(ctx, fmt.Sprintf("create export of %s %v", inputParam.SubjectType, inputParam.SubjectID))
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExport", sql.TxOptions{})
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewExport", insertExport, inputParam)
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return export, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no export created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success create export of %s %v", inputParam.SubjectType, inputParam.SubjectID))
+
+	export = entity.Export{
+		ID:           lastID,
+		RequestedBy:  inputParam.RequestedBy,
+		SubjectType:  inputParam.SubjectType,
+		SubjectID:    inputParam.SubjectID,
+		RangeStart:   null.TimeFrom(inputParam.RangeStart),
+		RangeEnd:     null.TimeFrom(inputParam.RangeEnd),
+		ExportStatus: entity.ExportStatusPending,
+		Status:       1,
+		CreatedAt:    inputParam.CreatedAt,
+		CreatedBy:    inputParam.CreatedBy,
+	}
+
+	return export, nil
+}
+
+func (e *export) getSQL(ctx context.Context, param entity.ExportParam) (entity.Export, error) {
+	export := entity.Export{}
+
+	e.log.Debug(ctx, fmt.Sprintf("get export with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := e.db.Follower().QueryRow(ctx, "rExport", readExport+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return export, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&export); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return export, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success get export with body: %v", param))
+
+	return export, nil
+}
+
+func (e *export) getListSQL(ctx context.Context, param entity.ExportParam) ([]entity.Export, *entity.Pagination, error) {
+	exports := []entity.Export{}
+
+	e.log.Debug(ctx, fmt.Sprintf("get export list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return exports, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := e.db.Follower().Query(ctx, "rExportList", readExport+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return exports, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		export := entity.Export{}
+		err := rows.StructScan(&export)
+		if err != nil {
+			return exports, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		exports = append(exports, export)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(exports)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(exports) > 0 && param.IncludePagination {
+		err := e.db.Follower().Get(ctx, "cExportList", countExport+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return exports, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	e.log.Debug(ctx, fmt.Sprintf("success get export list with body: %v", param))
+
+	return exports, &pg, nil
+}
+
+func (e *export) updateSQL(ctx context.Context, updateParam entity.ExportUpdateParam, selectParam entity.ExportParam) error {
+	e.log.Debug(ctx, fmt.Sprintf("update export %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExport", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uExport", updateExport+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no export updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success update export %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+// claimPendingSQL skips rows locked by other replicas, so an export is built by a single worker
+func (e *export) claimPendingSQL(ctx context.Context, startedAt, staleBefore time.Time) (entity.Export, error) {
+	export := entity.Export{}
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExportClaim", sql.TxOptions{})
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("rPendingExport", readPendingExport, staleBefore)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return export, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	found := false
+	for rows.Next() {
+		err := rows.StructScan(&export)
+		if err != nil {
+			rows.Close()
+			return export, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		found = true
+	}
+	rows.Close()
+
+	if !found {
+		return export, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, "no pending export")
+	}
+
+	_, err = tx.Exec("uExportRunning", markExportRunning, startedAt, export.ID)
+	if err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return export, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	export.ExportStatus = entity.ExportStatusRunning
+	export.StartedAt = null.TimeFrom(startedAt)
+
+	e.log.Debug(ctx, fmt.Sprintf("success claim export %v", export.ID))
+
+	return export, nil
+}
+
+func (e *export) createChunkSQL(ctx context.Context, chunk entity.ExportChunk) error {
+	e.log.Debug(ctx, fmt.Sprintf("create chunk %v of export %v", chunk.ChunkIndex, chunk.ExportID))
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExportChunk", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewExportChunk", insertExportChunk, chunk)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no export chunk created")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success create chunk %v of export %v", chunk.ChunkIndex, chunk.ExportID))
+
+	return nil
+}
+
+// getChunkSQL reads from the leader, a follower may not have replicated an archive that was just finished
+func (e *export) getChunkSQL(ctx context.Context, exportID, chunkIndex int64) (entity.ExportChunk, error) {
+	chunk := entity.ExportChunk{}
+
+	row, err := e.db.Leader().QueryRow(ctx, "rExportChunk", readExportChunk, exportID, chunkIndex)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return chunk, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&chunk); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return chunk, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return chunk, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	return chunk, nil
+}
+
+func (e *export) deleteChunksSQL(ctx context.Context, exportID int64) error {
+	e.log.Debug(ctx, fmt.Sprintf("delete chunks of export %v", exportID))
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExportChunk", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("dExportChunk", deleteExportChunk, exportID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success delete chunks of export %v", exportID))
+
+	return nil
+}
+
+func (e *export) deleteChunksBeforeSQL(ctx context.Context, before time.Time, limit int) (int64, error) {
+	e.log.Debug(ctx, fmt.Sprintf("delete export chunks created before %v", before))
+
+	tx, err := e.db.Leader().BeginTx(ctx, "txExportChunk", sql.TxOptions{})
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("dExportChunkBefore", deleteExportChunkBefore, before, limit)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	e.log.Debug(ctx, fmt.Sprintf("success delete %v export chunks created before %v", rowCount, before))
+
+	return rowCount, nil
+}
+
+func (e *export) getNotificationListSQL(ctx context.Context, param entity.ExportRecordParam) ([]entity.Notification, error) {
+	notifications := []entity.Notification{}
+
+	rows, err := e.db.Follower().Query(ctx, "rExportNotification", readExportNotification, param.SubjectID, param.RangeStart, param.RangeEnd, param.AfterID, param.Limit)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return notifications, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := entity.Notification{}
+		err := rows.StructScan(&notification)
+		if err != nil {
+			return notifications, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (e *export) getAuditListSQL(ctx context.Context, param entity.ExportRecordParam) ([]entity.Audit, error) {
+	audits := []entity.Audit{}
+
+	rows, err := e.db.Follower().Query(ctx, "rExportAudit", readExportAudit, param.SubjectID, param.RangeStart, param.RangeEnd, param.AfterID, param.Limit)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return audits, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		audit := entity.Audit{}
+		err := rows.StructScan(&audit)
+		if err != nil {
+			return audits, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		audits = append(audits, audit)
+	}
+
+	return audits, nil
+}
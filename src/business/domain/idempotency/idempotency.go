@@ -0,0 +1,45 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	// Get returns redis.Nil when no response has been stored for key
+	Get(ctx context.Context, key string) (entity.IdempotentResponse, error)
+	Set(ctx context.Context, key string, response entity.IdempotentResponse, ttl time.Duration) error
+}
+
+type idempotency struct {
+	log   log.Interface
+	redis redis.Interface
+	json  parser.JSONInterface
+}
+
+type InitParam struct {
+	Log   log.Interface
+	Redis redis.Interface
+	Json  parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &idempotency{
+		log:   param.Log,
+		redis: param.Redis,
+		json:  param.Json,
+	}
+}
+
+func (i *idempotency) Get(ctx context.Context, key string) (entity.IdempotentResponse, error) {
+	return i.getCache(ctx, key)
+}
+
+func (i *idempotency) Set(ctx context.Context, key string, response entity.IdempotentResponse, ttl time.Duration) error {
+	return i.upsertCache(ctx, key, response, ttl)
+}
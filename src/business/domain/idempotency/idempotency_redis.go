@@ -0,0 +1,45 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	getIdempotentResponseKey = "boilerplate:idempotency:get:%s"
+)
+
+func (i *idempotency) upsertCache(ctx context.Context, key string, response entity.IdempotentResponse, ttl time.Duration) error {
+	marshalledResponse, err := i.json.Marshal(response)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = i.redis.SetEX(ctx, fmt.Sprintf(getIdempotentResponseKey, key), string(marshalledResponse), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (i *idempotency) getCache(ctx context.Context, key string) (entity.IdempotentResponse, error) {
+	response := entity.IdempotentResponse{}
+
+	marshalledResponse, err := i.redis.Get(ctx, fmt.Sprintf(getIdempotentResponseKey, key))
+	if err != nil {
+		return response, err
+	}
+
+	err = i.json.Unmarshal([]byte(marshalledResponse), &response)
+	if err != nil {
+		return response, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return response, nil
+}
@@ -0,0 +1,50 @@
+package legalhold
+
+import (
+	"context"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error)
+	Get(ctx context.Context, param entity.LegalHoldParam) (entity.LegalHold, error)
+	GetList(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error)
+	Update(ctx context.Context, updateParam entity.LegalHoldUpdateParam, selectParam entity.LegalHoldParam) error
+}
+
+type legalhold struct {
+	db  sql.Interface
+	log log.Interface
+}
+
+type InitParam struct {
+	Db  sql.Interface
+	Log log.Interface
+}
+
+// Init creates the legal hold domain, holds guard deletes so they are always read from the database
+func Init(param InitParam) Interface {
+	return &legalhold{
+		db:  param.Db,
+		log: param.Log,
+	}
+}
+
+func (l *legalhold) Create(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error) {
+	return l.createSQL(ctx, inputParam)
+}
+
+func (l *legalhold) Get(ctx context.Context, param entity.LegalHoldParam) (entity.LegalHold, error) {
+	return l.getSQL(ctx, param)
+}
+
+func (l *legalhold) GetList(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error) {
+	return l.getListSQL(ctx, param)
+}
+
+func (l *legalhold) Update(ctx context.Context, updateParam entity.LegalHoldUpdateParam, selectParam entity.LegalHoldParam) error {
+	return l.updateSQL(ctx, updateParam, selectParam)
+}
@@ -0,0 +1,53 @@
+package legalhold
+
+const (
+	insertLegalHold = `
+		INSERT INTO legal_hold
+		(
+			scope_type,
+			scope_id,
+			reason,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:scope_type,
+			:scope_id,
+			:reason,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readLegalHold = `
+		SELECT
+			id,
+			scope_type,
+			scope_id,
+			reason,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			legal_hold
+	`
+
+	countLegalHold = `
+		SELECT
+			COUNT(*)
+		FROM
+			legal_hold
+	`
+
+	updateLegalHold = `
+		UPDATE
+			legal_hold
+	`
+)
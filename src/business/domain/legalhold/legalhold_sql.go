@@ -0,0 +1,171 @@
+package legalhold
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (l *legalhold) createSQL(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error) {
+	legalHold := entity.LegalHold{}
+
+	l.log.Debug(ctx, fmt.Sprintf("create legal hold on %s %v", inputParam.ScopeType, inputParam.ScopeID))
+
+	tx, err := l.db.Leader().BeginTx(ctx, "txLegalHold", sql.TxOptions{})
+	if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewLegalHold", insertLegalHold, inputParam)
+	if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return legalHold, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no legal hold created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	l.log.Debug(ctx, fmt.Sprintf("success create legal hold on %s %v", inputParam.ScopeType, inputParam.ScopeID))
+
+	legalHold = entity.LegalHold{
+		ID:        lastID,
+		ScopeType: inputParam.ScopeType,
+		ScopeID:   inputParam.ScopeID,
+		Reason:    inputParam.Reason,
+		Status:    1,
+		CreatedAt: inputParam.CreatedAt,
+		CreatedBy: inputParam.CreatedBy,
+	}
+
+	return legalHold, nil
+}
+
+func (l *legalhold) getSQL(ctx context.Context, param entity.LegalHoldParam) (entity.LegalHold, error) {
+	legalHold := entity.LegalHold{}
+
+	l.log.Debug(ctx, fmt.Sprintf("get legal hold with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := l.db.Follower().QueryRow(ctx, "rLegalHold", readLegalHold+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return legalHold, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&legalHold); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return legalHold, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return legalHold, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	l.log.Debug(ctx, fmt.Sprintf("success get legal hold with body: %v", param))
+
+	return legalHold, nil
+}
+
+func (l *legalhold) getListSQL(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error) {
+	legalHolds := []entity.LegalHold{}
+
+	l.log.Debug(ctx, fmt.Sprintf("get legal hold list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return legalHolds, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := l.db.Follower().Query(ctx, "rLegalHoldList", readLegalHold+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return legalHolds, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		legalHold := entity.LegalHold{}
+		err := rows.StructScan(&legalHold)
+		if err != nil {
+			return legalHolds, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		legalHolds = append(legalHolds, legalHold)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(legalHolds)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(legalHolds) > 0 && param.IncludePagination {
+		err := l.db.Follower().Get(ctx, "cLegalHoldList", countLegalHold+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return legalHolds, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	l.log.Debug(ctx, fmt.Sprintf("success get legal hold list with body: %v", param))
+
+	return legalHolds, &pg, nil
+}
+
+func (l *legalhold) updateSQL(ctx context.Context, updateParam entity.LegalHoldUpdateParam, selectParam entity.LegalHoldParam) error {
+	l.log.Debug(ctx, fmt.Sprintf("update legal hold %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := l.db.Leader().BeginTx(ctx, "txLegalHold", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uLegalHold", updateLegalHold+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no legal hold updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	l.log.Debug(ctx, fmt.Sprintf("success update legal hold %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
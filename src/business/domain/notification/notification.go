@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	outboxDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.NotificationInputParam) (entity.Notification, error)
+	Get(ctx context.Context, param entity.NotificationParam) (entity.Notification, error)
+	GetList(ctx context.Context, param entity.NotificationParam) ([]entity.Notification, *entity.Pagination, error)
+	Update(ctx context.Context, updateParam entity.NotificationUpdateParam, selectParam entity.NotificationParam) error
+	// GetUnreadUserIDList returns the users that received unread notifications between from and to
+	GetUnreadUserIDList(ctx context.Context, from, to time.Time) ([]int64, error)
+	CreateDevice(ctx context.Context, inputParam entity.DeviceInputParam) (entity.Device, error)
+	GetDevice(ctx context.Context, param entity.DeviceParam) (entity.Device, error)
+	GetDeviceList(ctx context.Context, param entity.DeviceParam) ([]entity.Device, *entity.Pagination, error)
+	UpdateDevice(ctx context.Context, updateParam entity.DeviceUpdateParam, selectParam entity.DeviceParam) error
+	// RestoreDevice reactivates a device for device.UserID on device.Platform and clears its deletion
+	RestoreDevice(ctx context.Context, device entity.Device, restoredAt time.Time, restoredBy string) error
+	// EraseDevicesTx deactivates every active device of the user inside tx, so their push tokens stop
+	// receiving pushes together with the change that deactivated the account
+	EraseDevicesTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+	// CreateJob queues the fan-out of an event, queueing the same event again is a no-op
+	CreateJob(ctx context.Context, inputParam entity.NotificationJobInputParam) error
+	// GetDueJobList returns at most limit pending jobs whose next attempt is due before the given time
+	GetDueJobList(ctx context.Context, before time.Time, limit int) ([]entity.NotificationJob, error)
+	UpdateJob(ctx context.Context, updateParam entity.NotificationJobUpdateParam, selectParam entity.NotificationJobParam) error
+}
+
+type notification struct {
+	db     sql.Interface
+	log    log.Interface
+	outbox outboxDomain.Interface
+}
+
+type InitParam struct {
+	Db     sql.Interface
+	Log    log.Interface
+	Outbox outboxDomain.Interface
+}
+
+// Init creates the notification domain, the feed changes on every new message so it is not cached
+func Init(param InitParam) Interface {
+	return &notification{
+		db:     param.Db,
+		log:    param.Log,
+		outbox: param.Outbox,
+	}
+}
+
+func (n *notification) Create(ctx context.Context, inputParam entity.NotificationInputParam) (entity.Notification, error) {
+	return n.createSQL(ctx, inputParam)
+}
+
+func (n *notification) Get(ctx context.Context, param entity.NotificationParam) (entity.Notification, error) {
+	return n.getSQL(ctx, param)
+}
+
+func (n *notification) GetList(ctx context.Context, param entity.NotificationParam) ([]entity.Notification, *entity.Pagination, error) {
+	return n.getListSQL(ctx, param)
+}
+
+func (n *notification) Update(ctx context.Context, updateParam entity.NotificationUpdateParam, selectParam entity.NotificationParam) error {
+	return n.updateSQL(ctx, updateParam, selectParam)
+}
+
+func (n *notification) GetUnreadUserIDList(ctx context.Context, from, to time.Time) ([]int64, error) {
+	return n.getUnreadUserIDListSQL(ctx, from, to)
+}
+
+func (n *notification) CreateDevice(ctx context.Context, inputParam entity.DeviceInputParam) (entity.Device, error) {
+	return n.createDeviceSQL(ctx, inputParam)
+}
+
+func (n *notification) GetDevice(ctx context.Context, param entity.DeviceParam) (entity.Device, error) {
+	return n.getDeviceSQL(ctx, param)
+}
+
+func (n *notification) GetDeviceList(ctx context.Context, param entity.DeviceParam) ([]entity.Device, *entity.Pagination, error) {
+	return n.getDeviceListSQL(ctx, param)
+}
+
+func (n *notification) UpdateDevice(ctx context.Context, updateParam entity.DeviceUpdateParam, selectParam entity.DeviceParam) error {
+	return n.updateDeviceSQL(ctx, updateParam, selectParam)
+}
+
+func (n *notification) RestoreDevice(ctx context.Context, device entity.Device, restoredAt time.Time, restoredBy string) error {
+	return n.restoreDeviceSQL(ctx, device, restoredAt, restoredBy)
+}
+
+func (n *notification) EraseDevicesTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return n.eraseDevicesTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (n *notification) CreateJob(ctx context.Context, inputParam entity.NotificationJobInputParam) error {
+	return n.createJobSQL(ctx, inputParam)
+}
+
+func (n *notification) GetDueJobList(ctx context.Context, before time.Time, limit int) ([]entity.NotificationJob, error) {
+	return n.getDueJobListSQL(ctx, before, limit)
+}
+
+func (n *notification) UpdateJob(ctx context.Context, updateParam entity.NotificationJobUpdateParam, selectParam entity.NotificationJobParam) error {
+	return n.updateJobSQL(ctx, updateParam, selectParam)
+}
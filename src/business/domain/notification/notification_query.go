@@ -0,0 +1,217 @@
+package notification
+
+const (
+	insertNotification = `
+		INSERT INTO notification
+		(
+			fk_user_id,
+			fk_actor_id,
+			event_id,
+			notification_type,
+			scope_type,
+			scope_id,
+			title,
+			body,
+			read_status,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:fk_actor_id,
+			:event_id,
+			:notification_type,
+			:scope_type,
+			:scope_id,
+			:title,
+			:body,
+			:read_status,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			id = id
+	`
+
+	readNotification = `
+		SELECT
+			id,
+			fk_user_id,
+			fk_actor_id,
+			notification_type,
+			scope_type,
+			scope_id,
+			title,
+			body,
+			read_status,
+			read_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			notification
+	`
+
+	countNotification = `
+		SELECT
+			COUNT(*)
+		FROM
+			notification
+	`
+
+	updateNotification = `
+		UPDATE
+			notification
+	`
+
+	insertDevice = `
+		INSERT INTO device
+		(
+			fk_user_id,
+			platform,
+			token,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:platform,
+			:token,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readDevice = `
+		SELECT
+			id,
+			fk_user_id,
+			platform,
+			token,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			device
+	`
+
+	countDevice = `
+		SELECT
+			COUNT(*)
+		FROM
+			device
+	`
+
+	updateDevice = `
+		UPDATE
+			device
+	`
+
+	restoreDevice = `
+		UPDATE
+			device
+		SET
+			fk_user_id = ?,
+			platform = ?,
+			status = 1,
+			deleted_at = NULL,
+			deleted_by = NULL,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+	`
+
+	eraseUserDevice = `
+		UPDATE
+			device
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+
+	readUnreadNotificationUser = `
+		SELECT DISTINCT
+			fk_user_id
+		FROM
+			notification
+		WHERE
+			read_status = 'unread'
+			AND status = 1
+			AND created_at >= ?
+			AND created_at < ?
+	`
+
+	insertNotificationJob = `
+		INSERT INTO notification_job
+		(
+			event_id,
+			event_type,
+			payload,
+			job_status,
+			next_attempt_at,
+			created_at
+		)
+		VALUES
+		(
+			:event_id,
+			:event_type,
+			:payload,
+			:job_status,
+			:next_attempt_at,
+			:created_at
+		)
+		ON DUPLICATE KEY UPDATE
+			id = id
+	`
+
+	readDueNotificationJob = `
+		SELECT
+			id,
+			event_id,
+			event_type,
+			payload,
+			attempt,
+			job_status,
+			next_attempt_at,
+			error_message,
+			status,
+			created_at,
+			updated_at
+		FROM
+			notification_job
+		WHERE
+			job_status = 'pending'
+			AND next_attempt_at <= ?
+			AND status = 1
+		ORDER BY
+			next_attempt_at ASC,
+			id ASC
+		LIMIT ?
+	`
+
+	updateNotificationJob = `
+		UPDATE
+			notification_job
+	`
+)
@@ -0,0 +1,524 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (n *notification) createSQL(ctx context.Context, inputParam entity.NotificationInputParam) (entity.Notification, error) {
+	notification := entity.Notification{}
+
+	n.log.Debug(ctx, fmt.Sprintf("create notification for user %v", inputParam.UserID))
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txNotification", sql.TxOptions{})
+	if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewNotification", insertNotification, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return notification, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	// an event is fanned out at least once, a recipient already notified of it keeps the first row
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return notification, errors.NewWithCode(codes.CodeSQLUniqueConstraint, "user %v was already notified of event %s", inputParam.UserID, inputParam.EventID.String)
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	notification = entity.Notification{
+		ID:               lastID,
+		UserID:           inputParam.UserID,
+		ActorID:          inputParam.ActorID,
+		NotificationType: inputParam.NotificationType,
+		ScopeType:        inputParam.ScopeType,
+		ScopeID:          inputParam.ScopeID,
+		Title:            inputParam.Title,
+		Body:             inputParam.Body,
+		ReadStatus:       inputParam.ReadStatus,
+		Status:           1,
+		CreatedAt:        inputParam.CreatedAt,
+		CreatedBy:        inputParam.CreatedBy,
+	}
+
+	err = n.outbox.CreateTx(ctx, tx, entity.Event{
+		ID:        uuid.New().String(),
+		Type:      entity.EventNotificationCreated,
+		ScopeType: entity.EventScopeUser,
+		ScopeID:   inputParam.UserID,
+		Data:      notification,
+		CreatedAt: inputParam.CreatedAt.Time,
+	})
+	if err != nil {
+		return entity.Notification{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.Notification{}, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success create notification for user %v", inputParam.UserID))
+
+	return notification, nil
+}
+
+func (n *notification) getSQL(ctx context.Context, param entity.NotificationParam) (entity.Notification, error) {
+	notification := entity.Notification{}
+
+	n.log.Debug(ctx, fmt.Sprintf("get notification with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := n.db.Follower().QueryRow(ctx, "rNotification", readNotification+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return notification, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&notification); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return notification, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return notification, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success get notification with body: %v", param))
+
+	return notification, nil
+}
+
+func (n *notification) getListSQL(ctx context.Context, param entity.NotificationParam) ([]entity.Notification, *entity.Pagination, error) {
+	notifications := []entity.Notification{}
+
+	n.log.Debug(ctx, fmt.Sprintf("get notification list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return notifications, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := n.db.Follower().Query(ctx, "rNotificationList", readNotification+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return notifications, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := entity.Notification{}
+		err := rows.StructScan(&notification)
+		if err != nil {
+			return notifications, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(notifications)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(notifications) > 0 && param.IncludePagination {
+		err := n.db.Follower().Get(ctx, "cNotificationList", countNotification+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return notifications, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	n.log.Debug(ctx, fmt.Sprintf("success get notification list with body: %v", param))
+
+	return notifications, &pg, nil
+}
+
+func (n *notification) updateSQL(ctx context.Context, updateParam entity.NotificationUpdateParam, selectParam entity.NotificationParam) error {
+	n.log.Debug(ctx, fmt.Sprintf("update notification %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txNotification", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uNotification", updateNotification+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no notification updated")
+	}
+
+	if updateParam.ReadStatus.String == entity.NotificationReadStatusRead {
+		err = n.outbox.CreateTx(ctx, tx, entity.Event{
+			ID:        uuid.New().String(),
+			Type:      entity.EventNotificationRead,
+			ScopeType: entity.EventScopeUser,
+			ScopeID:   selectParam.UserID,
+			Data: entity.NotificationEventData{
+				ID:     selectParam.ID,
+				UserID: selectParam.UserID,
+			},
+			CreatedAt: updateParam.UpdatedAt.Time,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success update notification %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (n *notification) createDeviceSQL(ctx context.Context, inputParam entity.DeviceInputParam) (entity.Device, error) {
+	device := entity.Device{}
+
+	n.log.Debug(ctx, fmt.Sprintf("create device for user %v", inputParam.UserID))
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txDevice", sql.TxOptions{})
+	if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewDevice", insertDevice, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return device, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return device, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no device created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success create device for user %v", inputParam.UserID))
+
+	device = entity.Device{
+		ID:        lastID,
+		UserID:    inputParam.UserID,
+		Platform:  inputParam.Platform,
+		Token:     inputParam.Token,
+		Status:    1,
+		CreatedAt: inputParam.CreatedAt,
+		CreatedBy: inputParam.CreatedBy,
+	}
+
+	return device, nil
+}
+
+func (n *notification) getDeviceSQL(ctx context.Context, param entity.DeviceParam) (entity.Device, error) {
+	device := entity.Device{}
+
+	n.log.Debug(ctx, fmt.Sprintf("get device with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := n.db.Follower().QueryRow(ctx, "rDevice", readDevice+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return device, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&device); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return device, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return device, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success get device with body: %v", param))
+
+	return device, nil
+}
+
+func (n *notification) getDeviceListSQL(ctx context.Context, param entity.DeviceParam) ([]entity.Device, *entity.Pagination, error) {
+	devices := []entity.Device{}
+
+	n.log.Debug(ctx, fmt.Sprintf("get device list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return devices, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := n.db.Follower().Query(ctx, "rDeviceList", readDevice+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return devices, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		device := entity.Device{}
+		err := rows.StructScan(&device)
+		if err != nil {
+			return devices, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		devices = append(devices, device)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(devices)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(devices) > 0 && param.IncludePagination {
+		err := n.db.Follower().Get(ctx, "cDeviceList", countDevice+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return devices, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	n.log.Debug(ctx, fmt.Sprintf("success get device list with body: %v", param))
+
+	return devices, &pg, nil
+}
+
+func (n *notification) updateDeviceSQL(ctx context.Context, updateParam entity.DeviceUpdateParam, selectParam entity.DeviceParam) error {
+	n.log.Debug(ctx, fmt.Sprintf("update device %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txDevice", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uDevice", updateDevice+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no device updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success update device %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (n *notification) restoreDeviceSQL(ctx context.Context, device entity.Device, restoredAt time.Time, restoredBy string) error {
+	n.log.Debug(ctx, fmt.Sprintf("restore device %v for user %v", device.ID, device.UserID))
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txDevice", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uRestoreDevice", restoreDevice, device.UserID, device.Platform, restoredAt, restoredBy, device.ID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no device restored")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success restore device %v for user %v", device.ID, device.UserID))
+
+	return nil
+}
+
+func (n *notification) eraseDevicesTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	n.log.Debug(ctx, fmt.Sprintf("erase devices of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserDevice", eraseUserDevice, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success erase devices of user %v", userID))
+
+	return nil
+}
+
+func (n *notification) getUnreadUserIDListSQL(ctx context.Context, from, to time.Time) ([]int64, error) {
+	userIDs := []int64{}
+
+	n.log.Debug(ctx, fmt.Sprintf("get users with unread notification between %v and %v", from, to))
+
+	rows, err := n.db.Follower().Query(ctx, "rUnreadNotificationUser", readUnreadNotificationUser, from, to)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return userIDs, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := entity.Notification{}
+		err := rows.StructScan(&notification)
+		if err != nil {
+			return userIDs, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		userIDs = append(userIDs, notification.UserID)
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success get %v users with unread notification", len(userIDs)))
+
+	return userIDs, nil
+}
+
+func (n *notification) createJobSQL(ctx context.Context, inputParam entity.NotificationJobInputParam) error {
+	n.log.Debug(ctx, fmt.Sprintf("queue notification job of event %s", inputParam.EventID))
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txNotificationJob", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iNewNotificationJob", insertNotificationJob, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success queue notification job of event %s", inputParam.EventID))
+
+	return nil
+}
+
+func (n *notification) getDueJobListSQL(ctx context.Context, before time.Time, limit int) ([]entity.NotificationJob, error) {
+	jobs := []entity.NotificationJob{}
+
+	rows, err := n.db.Leader().Query(ctx, "rDueNotificationJob", readDueNotificationJob, before, limit)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return jobs, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		job := entity.NotificationJob{}
+		err := rows.StructScan(&job)
+		if err != nil {
+			return jobs, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (n *notification) updateJobSQL(ctx context.Context, updateParam entity.NotificationJobUpdateParam, selectParam entity.NotificationJobParam) error {
+	n.log.Debug(ctx, fmt.Sprintf("update notification job %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := n.db.Leader().BeginTx(ctx, "txNotificationJob", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uNotificationJob", updateNotificationJob+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no notification job updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	n.log.Debug(ctx, fmt.Sprintf("success update notification job %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
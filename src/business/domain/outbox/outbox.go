@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// PublishFunc publishes a single event, a non-nil error retries the row on a later run. Rows can be
+// published more than once, so subscribers must be idempotent on the event id.
+type PublishFunc func(ctx context.Context, event entity.Event) error
+
+type Interface interface {
+	// CreateTx records the event inside tx, so it is only visible once the domain change it describes is committed
+	CreateTx(ctx context.Context, tx sql.CommandTx, event entity.Event) error
+	// PublishPending claims up to param.Limit due rows, sequences the ones claimed for the first time and hands
+	// them to publish in order. A failing row is rescheduled or dead lettered without holding back the rows after
+	// it. It returns how many rows were claimed.
+	PublishPending(ctx context.Context, param entity.OutboxPublishParam, publish PublishFunc) (int, error)
+	// GetEventList returns the sequenced rows recorded against the given scopes in seq order, a row is
+	// sequenced by the relay in commit order so paging on seq never skips a row committed later
+	GetEventList(ctx context.Context, param entity.OutboxEventParam) ([]entity.Outbox, error)
+	// GetLatestSeq returns the seq of the newest sequenced row, or 0 when none is sequenced yet
+	GetLatestSeq(ctx context.Context) (int64, error)
+	// GetPurgedSeq returns the highest seq retention purged from the given scopes, or 0 when none was purged
+	GetPurgedSeq(ctx context.Context, scopes []entity.EventScope) (int64, error)
+}
+
+type outbox struct {
+	db   sql.Interface
+	log  log.Interface
+	json parser.JSONInterface
+}
+
+type InitParam struct {
+	Db   sql.Interface
+	Log  log.Interface
+	Json parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &outbox{
+		db:   param.Db,
+		log:  param.Log,
+		json: param.Json,
+	}
+}
+
+func (o *outbox) CreateTx(ctx context.Context, tx sql.CommandTx, event entity.Event) error {
+	return o.createTxSQL(ctx, tx, event)
+}
+
+func (o *outbox) PublishPending(ctx context.Context, param entity.OutboxPublishParam, publish PublishFunc) (int, error) {
+	return o.publishPendingSQL(ctx, param, publish)
+}
+
+func (o *outbox) GetEventList(ctx context.Context, param entity.OutboxEventParam) ([]entity.Outbox, error) {
+	return o.getEventListSQL(ctx, param)
+}
+
+func (o *outbox) GetLatestSeq(ctx context.Context) (int64, error) {
+	return o.getLatestSeqSQL(ctx)
+}
+
+func (o *outbox) GetPurgedSeq(ctx context.Context, scopes []entity.EventScope) (int64, error) {
+	return o.getPurgedSeqSQL(ctx, scopes)
+}
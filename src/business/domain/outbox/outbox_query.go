@@ -0,0 +1,166 @@
+package outbox
+
+const (
+	insertOutbox = `
+		INSERT INTO outbox
+		(
+			event_id,
+			event_type,
+			scope_type,
+			scope_id,
+			actor_id,
+			payload,
+			created_at
+		)
+		VALUES
+		(
+			:event_id,
+			:event_type,
+			:scope_type,
+			:scope_id,
+			:actor_id,
+			:payload,
+			:created_at
+		)
+	`
+
+	// rows are claimed with SKIP LOCKED so concurrent relays never pick up the same row
+	readPendingOutbox = `
+		SELECT
+			id,
+			event_id,
+			event_type,
+			scope_type,
+			scope_id,
+			actor_id,
+			seq,
+			payload,
+			published_at,
+			attempts,
+			last_error,
+			next_attempt_at,
+			dead_at,
+			status,
+			created_at
+		FROM
+			outbox
+		WHERE
+			published_at IS NULL
+			AND dead_at IS NULL
+			AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
+			AND status = 1
+		ORDER BY
+			id ASC
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	// the scope conditions are joined with OR into the %s placeholder
+	readOutboxEvent = `
+		SELECT
+			id,
+			event_id,
+			event_type,
+			scope_type,
+			scope_id,
+			actor_id,
+			seq,
+			payload,
+			published_at,
+			attempts,
+			last_error,
+			next_attempt_at,
+			dead_at,
+			status,
+			created_at
+		FROM
+			outbox
+		WHERE
+			seq > ?
+			AND status = 1
+			AND (%s)
+		ORDER BY
+			seq ASC
+		LIMIT ?
+	`
+
+	scopeCondition = "(scope_type = ? AND scope_id = ?)"
+
+	readOutboxPurgedSeq = `
+		SELECT
+			COALESCE(MAX(purged_seq), 0)
+		FROM
+			outbox_purge
+		WHERE
+			%s
+	`
+
+	readOutboxLatestSeq = `
+		SELECT
+			last_seq
+		FROM
+			outbox_sequence
+		WHERE
+			id = 1
+	`
+
+	// the lock is held until the relay commits, so a later seq is never visible before an earlier one
+	readOutboxSequenceForUpdate = `
+		SELECT
+			last_seq
+		FROM
+			outbox_sequence
+		WHERE
+			id = 1
+		FOR UPDATE
+	`
+
+	updateOutboxSequence = `
+		UPDATE
+			outbox_sequence
+		SET
+			last_seq = ?
+		WHERE
+			id = 1
+	`
+
+	markOutboxSeq = `
+		UPDATE
+			outbox
+		SET
+			seq = ?
+		WHERE
+			id = ?
+	`
+
+	markOutboxPublished = `
+		UPDATE
+			outbox
+		SET
+			published_at = ?
+		WHERE
+			id = ?
+	`
+
+	markOutboxFailed = `
+		UPDATE
+			outbox
+		SET
+			attempts = ?,
+			last_error = ?,
+			next_attempt_at = ?
+		WHERE
+			id = ?
+	`
+
+	markOutboxDead = `
+		UPDATE
+			outbox
+		SET
+			attempts = ?,
+			last_error = ?,
+			dead_at = ?
+		WHERE
+			id = ?
+	`
+)
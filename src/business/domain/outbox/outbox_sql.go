@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (o *outbox) createTxSQL(ctx context.Context, tx sql.CommandTx, event entity.Event) error {
+	o.log.Debug(ctx, fmt.Sprintf("create outbox %s with id: %s", event.Type, event.ID))
+
+	payload, err := o.json.Marshal(event.Data)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	inputParam := entity.OutboxInputParam{
+		EventID:   event.ID,
+		EventType: event.Type,
+		ScopeType: event.ScopeType,
+		ScopeID:   event.ScopeID,
+		ActorID:   event.ActorID,
+		Payload:   string(payload),
+		CreatedAt: null.TimeFrom(event.CreatedAt),
+	}
+
+	res, err := tx.NamedExec("iNewOutbox", insertOutbox, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no outbox created")
+	}
+
+	o.log.Debug(ctx, fmt.Sprintf("success create outbox %s with id: %s", event.Type, event.ID))
+
+	return nil
+}
+
+// publishPendingSQL publishes while holding the row locks, so each row is marked once. A failed commit
+// leaves the batch pending and it is published again, subscribers dedupe on the event id.
+func (o *outbox) publishPendingSQL(ctx context.Context, param entity.OutboxPublishParam, publish PublishFunc) (int, error) {
+	tx, err := o.db.Leader().BeginTx(ctx, "txOutbox", sql.TxOptions{})
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	rows, err := tx.Query("rPendingOutbox", readPendingOutbox, now, param.Limit)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return 0, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	pending := []entity.Outbox{}
+	for rows.Next() {
+		row := entity.Outbox{}
+		err := rows.StructScan(&row)
+		if err != nil {
+			rows.Close()
+			return 0, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		pending = append(pending, row)
+	}
+	rows.Close()
+
+	err = o.sequenceTx(ctx, tx, pending)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, row := range pending {
+		perr := publish(ctx, row.ToEvent())
+		attempts := row.Attempts + 1
+
+		switch {
+		case perr == nil:
+			_, err = tx.Exec("uOutboxPublished", markOutboxPublished, now, row.ID)
+			published++
+		case attempts >= param.MaxAttempt:
+			o.log.Error(ctx, fmt.Sprintf("outbox %v dead lettered after %v attempts: %s", row.ID, attempts, perr.Error()))
+			_, err = tx.Exec("uOutboxDead", markOutboxDead, attempts, perr.Error(), now, row.ID)
+		default:
+			o.log.Warn(ctx, fmt.Sprintf("failed to publish outbox %v: %s", row.ID, perr.Error()))
+			nextAttemptAt := now.Add(param.BackoffInterval * time.Duration(1<<(attempts-1)))
+			_, err = tx.Exec("uOutboxFailed", markOutboxFailed, attempts, perr.Error(), nextAttemptAt, row.ID)
+		}
+		if err != nil {
+			return 0, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	if published > 0 {
+		o.log.Debug(ctx, fmt.Sprintf("success publish %v outbox events", published))
+	}
+
+	return len(pending), nil
+}
+
+// outboxSequence is a row of readOutboxSequenceForUpdate
+type outboxSequence struct {
+	LastSeq int64 `db:"last_seq"`
+}
+
+// sequenceTx numbers the rows claimed for the first time in id order, the sequence row stays locked
+// until tx commits so relays running side by side publish their seqs in order
+func (o *outbox) sequenceTx(ctx context.Context, tx sql.CommandTx, pending []entity.Outbox) error {
+	unsequenced := 0
+	for _, row := range pending {
+		if !row.Seq.Valid {
+			unsequenced++
+		}
+	}
+
+	if unsequenced == 0 {
+		return nil
+	}
+
+	row, err := tx.QueryRow("rOutboxSequence", readOutboxSequenceForUpdate)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	sequence := outboxSequence{}
+	if err := row.StructScan(&sequence); err != nil {
+		return errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+	lastSeq := sequence.LastSeq
+
+	for i := range pending {
+		if pending[i].Seq.Valid {
+			continue
+		}
+
+		lastSeq++
+		_, err := tx.Exec("uOutboxSeq", markOutboxSeq, lastSeq, pending[i].ID)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+		}
+		pending[i].Seq = null.Int64From(lastSeq)
+	}
+
+	_, err = tx.Exec("uOutboxSequence", updateOutboxSequence, lastSeq)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	o.log.Debug(ctx, fmt.Sprintf("sequenced %v outbox rows up to %v", unsequenced, lastSeq))
+
+	return nil
+}
+
+func (o *outbox) getEventListSQL(ctx context.Context, param entity.OutboxEventParam) ([]entity.Outbox, error) {
+	events := []entity.Outbox{}
+
+	if len(param.Scopes) == 0 {
+		return events, nil
+	}
+
+	o.log.Debug(ctx, fmt.Sprintf("get outbox event list with body: %v", param))
+
+	scopes := make([]string, 0, len(param.Scopes))
+	args := []interface{}{param.AfterSeq}
+	for _, scope := range param.Scopes {
+		scopes = append(scopes, scopeCondition)
+		args = append(args, scope.Type, scope.ID)
+	}
+	args = append(args, param.Limit)
+
+	rows, err := o.db.Follower().Query(ctx, "rOutboxEventList", fmt.Sprintf(readOutboxEvent, strings.Join(scopes, " OR ")), args...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return events, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := entity.Outbox{}
+		err := rows.StructScan(&row)
+		if err != nil {
+			return events, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		events = append(events, row)
+	}
+
+	o.log.Debug(ctx, fmt.Sprintf("success get %v outbox events", len(events)))
+
+	return events, nil
+}
+
+func (o *outbox) getLatestSeqSQL(ctx context.Context) (int64, error) {
+	var latestSeq int64
+
+	err := o.db.Follower().Get(ctx, "rOutboxLatestSeq", readOutboxLatestSeq, &latestSeq)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	return latestSeq, nil
+}
+
+func (o *outbox) getPurgedSeqSQL(ctx context.Context, scopes []entity.EventScope) (int64, error) {
+	var purgedSeq int64
+
+	if len(scopes) == 0 {
+		return purgedSeq, nil
+	}
+
+	conditions := make([]string, 0, len(scopes))
+	args := []interface{}{}
+	for _, scope := range scopes {
+		conditions = append(conditions, scopeCondition)
+		args = append(args, scope.Type, scope.ID)
+	}
+
+	err := o.db.Follower().Get(ctx, "rOutboxPurgedSeq", fmt.Sprintf(readOutboxPurgedSeq, strings.Join(conditions, " OR ")), &purgedSeq, args...)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	return purgedSeq, nil
+}
@@ -0,0 +1,170 @@
+package preference
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	Get(ctx context.Context, param entity.PreferenceParam) (entity.Preference, error)
+	Upsert(ctx context.Context, inputParam entity.PreferenceInputParam) error
+	UpsertDigest(ctx context.Context, inputParam entity.PreferenceDigestInputParam) error
+	GetScope(ctx context.Context, param entity.ScopePreferenceParam) (entity.ScopePreference, error)
+	GetScopeList(ctx context.Context, param entity.ScopePreferenceParam) ([]entity.ScopePreference, *entity.Pagination, error)
+	UpsertScope(ctx context.Context, inputParam entity.ScopePreferenceInputParam) error
+	// EraseTx deactivates the preferences of the user inside tx, DeleteCache must be called once tx is committed
+	EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+	// DeleteCache drops the cached preferences after a change committed through another domain's transaction
+	DeleteCache(ctx context.Context) error
+}
+
+type preference struct {
+	db    sql.Interface
+	log   log.Interface
+	redis redis.Interface
+	json  parser.JSONInterface
+}
+
+type InitParam struct {
+	Db    sql.Interface
+	Log   log.Interface
+	Redis redis.Interface
+	Json  parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &preference{
+		db:    param.Db,
+		log:   param.Log,
+		redis: param.Redis,
+		json:  param.Json,
+	}
+}
+
+func (p *preference) Get(ctx context.Context, param entity.PreferenceParam) (entity.Preference, error) {
+	preference := entity.Preference{}
+
+	marshalledParam, err := p.json.Marshal(param)
+	if err != nil {
+		return preference, err
+	}
+
+	if !param.BypassCache {
+		err = p.getCache(ctx, fmt.Sprintf(getPreferenceByKey, string(marshalledParam)), &preference)
+		switch {
+		case errors.Is(err, redis.Nil):
+			p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return preference, nil
+		}
+	}
+
+	preference, err = p.getSQL(ctx, param)
+	if err != nil {
+		return preference, err
+	}
+
+	err = p.upsertCache(ctx, fmt.Sprintf(getPreferenceByKey, string(marshalledParam)), preference, p.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return preference, nil
+}
+
+func (p *preference) Upsert(ctx context.Context, inputParam entity.PreferenceInputParam) error {
+	err := p.upsertSQL(ctx, inputParam)
+	if err != nil {
+		return err
+	}
+
+	err = p.deleteCache(ctx)
+	if err != nil {
+		p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (p *preference) UpsertDigest(ctx context.Context, inputParam entity.PreferenceDigestInputParam) error {
+	err := p.upsertDigestSQL(ctx, inputParam)
+	if err != nil {
+		return err
+	}
+
+	err = p.deleteCache(ctx)
+	if err != nil {
+		p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (p *preference) GetScope(ctx context.Context, param entity.ScopePreferenceParam) (entity.ScopePreference, error) {
+	scope := entity.ScopePreference{}
+
+	marshalledParam, err := p.json.Marshal(param)
+	if err != nil {
+		return scope, err
+	}
+
+	if !param.BypassCache {
+		err = p.getCache(ctx, fmt.Sprintf(getScopePreferenceByKey, string(marshalledParam)), &scope)
+		switch {
+		case errors.Is(err, redis.Nil):
+			p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return scope, nil
+		}
+	}
+
+	scope, err = p.getScopeSQL(ctx, param)
+	if err != nil {
+		return scope, err
+	}
+
+	err = p.upsertCache(ctx, fmt.Sprintf(getScopePreferenceByKey, string(marshalledParam)), scope, p.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return scope, nil
+}
+
+func (p *preference) GetScopeList(ctx context.Context, param entity.ScopePreferenceParam) ([]entity.ScopePreference, *entity.Pagination, error) {
+	return p.getScopeListSQL(ctx, param)
+}
+
+func (p *preference) UpsertScope(ctx context.Context, inputParam entity.ScopePreferenceInputParam) error {
+	err := p.upsertScopeSQL(ctx, inputParam)
+	if err != nil {
+		return err
+	}
+
+	err = p.deleteCache(ctx)
+	if err != nil {
+		p.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (p *preference) EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return p.eraseTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (p *preference) DeleteCache(ctx context.Context) error {
+	return p.deleteCache(ctx)
+}
@@ -0,0 +1,148 @@
+package preference
+
+const (
+	upsertPreference = `
+		INSERT INTO user_preference
+		(
+			fk_user_id,
+			do_not_disturb,
+			quiet_hours_start,
+			quiet_hours_end,
+			time_zone,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:do_not_disturb,
+			:quiet_hours_start,
+			:quiet_hours_end,
+			:time_zone,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			do_not_disturb = VALUES(do_not_disturb),
+			quiet_hours_start = VALUES(quiet_hours_start),
+			quiet_hours_end = VALUES(quiet_hours_end),
+			time_zone = VALUES(time_zone),
+			updated_at = VALUES(created_at),
+			updated_by = VALUES(created_by)
+	`
+
+	readPreference = `
+		SELECT
+			id,
+			fk_user_id,
+			do_not_disturb,
+			quiet_hours_start,
+			quiet_hours_end,
+			time_zone,
+			email_digest,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by
+		FROM
+			user_preference
+	`
+
+	upsertPreferenceDigest = `
+		INSERT INTO user_preference
+		(
+			fk_user_id,
+			email_digest,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:email_digest,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			email_digest = VALUES(email_digest),
+			updated_at = VALUES(created_at),
+			updated_by = VALUES(created_by)
+	`
+
+	upsertScopePreference = `
+		INSERT INTO scope_preference
+		(
+			fk_user_id,
+			scope_type,
+			scope_id,
+			notification_level,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:scope_type,
+			:scope_id,
+			:notification_level,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			notification_level = VALUES(notification_level),
+			updated_at = VALUES(created_at),
+			updated_by = VALUES(created_by)
+	`
+
+	readScopePreference = `
+		SELECT
+			id,
+			fk_user_id,
+			scope_type,
+			scope_id,
+			notification_level,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by
+		FROM
+			scope_preference
+	`
+
+	countScopePreference = `
+		SELECT
+			COUNT(*)
+		FROM
+			scope_preference
+	`
+
+	eraseUserPreference = `
+		UPDATE
+			user_preference
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+
+	eraseUserScopePreference = `
+		UPDATE
+			scope_preference
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+)
@@ -0,0 +1,52 @@
+package preference
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+)
+
+const (
+	getPreferenceByKey          = "boilerplate:preference:get:%s"
+	getScopePreferenceByKey     = "boilerplate:preference:scope:get:%s"
+	deletePreferenceKeysPattern = "boilerplate:preference*"
+)
+
+func (p *preference) upsertCache(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	marshalledValue, err := p.json.Marshal(value)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = p.redis.SetEX(ctx, key, string(marshalledValue), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (p *preference) getCache(ctx context.Context, key string, dest interface{}) error {
+	marshalledValue, err := p.redis.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	err = p.json.Unmarshal([]byte(marshalledValue), dest)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return nil
+}
+
+func (p *preference) deleteCache(ctx context.Context) error {
+	err := p.redis.Del(ctx, deletePreferenceKeysPattern)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
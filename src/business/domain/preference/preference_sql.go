@@ -0,0 +1,204 @@
+package preference
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (p *preference) getSQL(ctx context.Context, param entity.PreferenceParam) (entity.Preference, error) {
+	preference := entity.Preference{}
+
+	p.log.Debug(ctx, fmt.Sprintf("get preference with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return preference, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := p.db.Follower().QueryRow(ctx, "rPreference", readPreference+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return preference, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&preference); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return preference, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return preference, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success get preference with body: %v", param))
+
+	return preference, nil
+}
+
+func (p *preference) upsertSQL(ctx context.Context, inputParam entity.PreferenceInputParam) error {
+	p.log.Debug(ctx, fmt.Sprintf("upsert preference with body: %v", inputParam))
+
+	tx, err := p.db.Leader().BeginTx(ctx, "txPreference", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iuPreference", upsertPreference, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success upsert preference with body: %v", inputParam))
+
+	return nil
+}
+
+func (p *preference) upsertDigestSQL(ctx context.Context, inputParam entity.PreferenceDigestInputParam) error {
+	p.log.Debug(ctx, fmt.Sprintf("upsert preference digest with body: %v", inputParam))
+
+	tx, err := p.db.Leader().BeginTx(ctx, "txPreferenceDigest", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iuPreferenceDigest", upsertPreferenceDigest, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success upsert preference digest with body: %v", inputParam))
+
+	return nil
+}
+
+func (p *preference) getScopeSQL(ctx context.Context, param entity.ScopePreferenceParam) (entity.ScopePreference, error) {
+	scope := entity.ScopePreference{}
+
+	p.log.Debug(ctx, fmt.Sprintf("get scope preference with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return scope, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := p.db.Follower().QueryRow(ctx, "rScopePreference", readScopePreference+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return scope, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&scope); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return scope, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return scope, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success get scope preference with body: %v", param))
+
+	return scope, nil
+}
+
+func (p *preference) getScopeListSQL(ctx context.Context, param entity.ScopePreferenceParam) ([]entity.ScopePreference, *entity.Pagination, error) {
+	scopes := []entity.ScopePreference{}
+
+	p.log.Debug(ctx, fmt.Sprintf("get scope preference list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return scopes, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := p.db.Follower().Query(ctx, "rScopePreferenceList", readScopePreference+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return scopes, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		scope := entity.ScopePreference{}
+		err := rows.StructScan(&scope)
+		if err != nil {
+			return scopes, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(scopes)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(scopes) > 0 && param.IncludePagination {
+		err := p.db.Follower().Get(ctx, "cScopePreferenceList", countScopePreference+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return scopes, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	p.log.Debug(ctx, fmt.Sprintf("success get scope preference list with body: %v", param))
+
+	return scopes, &pg, nil
+}
+
+func (p *preference) upsertScopeSQL(ctx context.Context, inputParam entity.ScopePreferenceInputParam) error {
+	p.log.Debug(ctx, fmt.Sprintf("upsert scope preference with body: %v", inputParam))
+
+	tx, err := p.db.Leader().BeginTx(ctx, "txScopePreference", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iuScopePreference", upsertScopePreference, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success upsert scope preference with body: %v", inputParam))
+
+	return nil
+}
+
+func (p *preference) eraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	p.log.Debug(ctx, fmt.Sprintf("erase preferences of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserPreference", eraseUserPreference, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	_, err = tx.Exec("uEraseUserScopePreference", eraseUserScopePreference, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	p.log.Debug(ctx, fmt.Sprintf("success erase preferences of user %v", userID))
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package presence
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/redis"
+)
+
+type Interface interface {
+	// Touch records lastSeen as the latest activity of the user, the record expires after ttl
+	Touch(ctx context.Context, userID int64, lastSeen time.Time, ttl time.Duration) error
+	// GetLastSeen returns an invalid time when the user has no recorded activity
+	GetLastSeen(ctx context.Context, userID int64) (null.Time, error)
+}
+
+type presence struct {
+	log   log.Interface
+	redis redis.Interface
+}
+
+type InitParam struct {
+	Log   log.Interface
+	Redis redis.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &presence{
+		log:   param.Log,
+		redis: param.Redis,
+	}
+}
+
+func (p *presence) Touch(ctx context.Context, userID int64, lastSeen time.Time, ttl time.Duration) error {
+	return p.upsertCache(ctx, userID, lastSeen, ttl)
+}
+
+func (p *presence) GetLastSeen(ctx context.Context, userID int64) (null.Time, error) {
+	return p.getCache(ctx, userID)
+}
@@ -0,0 +1,41 @@
+package presence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/redis"
+)
+
+const (
+	getPresenceByUserKey = "boilerplate:presence:get:%d"
+)
+
+func (p *presence) upsertCache(ctx context.Context, userID int64, lastSeen time.Time, ttl time.Duration) error {
+	err := p.redis.SetEX(ctx, fmt.Sprintf(getPresenceByUserKey, userID), lastSeen.UTC().Format(time.RFC3339), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (p *presence) getCache(ctx context.Context, userID int64) (null.Time, error) {
+	value, err := p.redis.Get(ctx, fmt.Sprintf(getPresenceByUserKey, userID))
+	if errors.Is(err, redis.Nil) {
+		return null.Time{}, nil
+	} else if err != nil {
+		return null.Time{}, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	lastSeen, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return null.Time{}, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return null.TimeFrom(lastSeen), nil
+}
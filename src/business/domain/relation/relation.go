@@ -0,0 +1,194 @@
+package relation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	GetList(ctx context.Context, param entity.RelationParam) ([]entity.Relation, *entity.Pagination, error)
+	Get(ctx context.Context, param entity.RelationParam) (entity.Relation, error)
+	Create(ctx context.Context, inputParam entity.RelationInputParam) (entity.Relation, error)
+	Update(ctx context.Context, updateParam entity.RelationUpdateParam, selectParam entity.RelationParam) error
+	// Restore reactivates a removed relation and clears its deletion
+	Restore(ctx context.Context, id int64, restoredAt time.Time, restoredBy string) error
+	// IsBlocked reports whether either user has blocked the other
+	IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, error)
+	// GetBlockedUserIDs returns every user who blocked or was blocked by userID
+	GetBlockedUserIDs(ctx context.Context, userID int64) ([]int64, error)
+	// IsMuted reports whether the user has an active mute on the target
+	IsMuted(ctx context.Context, userID int64, targetType string, targetID int64) (bool, error)
+	// EraseTx deactivates the relations of the user and the ones targeting them inside tx, DeleteCache must be called once tx is committed
+	EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+	// DeleteCache drops the cached relations after a change committed through another domain's transaction
+	DeleteCache(ctx context.Context) error
+}
+
+type relation struct {
+	db    sql.Interface
+	log   log.Interface
+	redis redis.Interface
+	json  parser.JSONInterface
+}
+
+type InitParam struct {
+	Db    sql.Interface
+	Log   log.Interface
+	Redis redis.Interface
+	Json  parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &relation{
+		db:    param.Db,
+		log:   param.Log,
+		redis: param.Redis,
+		json:  param.Json,
+	}
+}
+
+func (r *relation) GetList(ctx context.Context, param entity.RelationParam) ([]entity.Relation, *entity.Pagination, error) {
+	if !param.BypassCache {
+		relations, pg, err := r.getCacheList(ctx, param)
+		switch {
+		case errors.Is(err, redis.Nil):
+			r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return relations, &pg, nil
+		}
+	}
+
+	relations, pg, err := r.getListSQL(ctx, param)
+	if err != nil {
+		return relations, pg, err
+	}
+
+	err = r.upsertCacheList(ctx, param, relations, *pg, r.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return relations, pg, nil
+}
+
+func (r *relation) Get(ctx context.Context, param entity.RelationParam) (entity.Relation, error) {
+	relation := entity.Relation{}
+
+	marshalledParam, err := r.json.Marshal(param)
+	if err != nil {
+		return relation, err
+	}
+
+	if !param.BypassCache {
+		relation, err = r.getCache(ctx, fmt.Sprintf(getRelationByKey, string(marshalledParam)))
+		switch {
+		case errors.Is(err, redis.Nil):
+			r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return relation, nil
+		}
+	}
+
+	relation, err = r.getSQL(ctx, param)
+	if err != nil {
+		return relation, err
+	}
+
+	err = r.upsertCache(ctx, fmt.Sprintf(getRelationByKey, string(marshalledParam)), relation, r.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return relation, nil
+}
+
+func (r *relation) Create(ctx context.Context, inputParam entity.RelationInputParam) (entity.Relation, error) {
+	relation, err := r.createSQL(ctx, inputParam)
+	if err != nil {
+		return relation, err
+	}
+
+	err = r.deleteCache(ctx)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return relation, nil
+}
+
+func (r *relation) Update(ctx context.Context, updateParam entity.RelationUpdateParam, selectParam entity.RelationParam) error {
+	err := r.updateSQL(ctx, updateParam, selectParam)
+	if err != nil {
+		return err
+	}
+
+	err = r.deleteCache(ctx)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (r *relation) Restore(ctx context.Context, id int64, restoredAt time.Time, restoredBy string) error {
+	err := r.restoreSQL(ctx, id, restoredAt, restoredBy)
+	if err != nil {
+		return err
+	}
+
+	err = r.deleteCache(ctx)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (r *relation) IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, error) {
+	return r.isBlockedSQL(ctx, userID, otherUserID)
+}
+
+func (r *relation) GetBlockedUserIDs(ctx context.Context, userID int64) ([]int64, error) {
+	return r.getBlockedUserIDsSQL(ctx, userID)
+}
+
+func (r *relation) IsMuted(ctx context.Context, userID int64, targetType string, targetID int64) (bool, error) {
+	_, err := r.Get(ctx, entity.RelationParam{
+		UserID:       userID,
+		RelationType: entity.RelationTypeMute,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *relation) EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return r.eraseTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (r *relation) DeleteCache(ctx context.Context) error {
+	return r.deleteCache(ctx)
+}
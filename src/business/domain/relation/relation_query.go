@@ -0,0 +1,120 @@
+package relation
+
+const (
+	insertRelation = `
+		INSERT INTO relation
+		(
+			fk_user_id,
+			relation_type,
+			target_type,
+			target_id,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:relation_type,
+			:target_type,
+			:target_id,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readRelation = `
+		SELECT
+			id,
+			fk_user_id,
+			relation_type,
+			target_type,
+			target_id,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			relation
+	`
+
+	countRelation = `
+		SELECT
+			COUNT(*)
+		FROM
+			relation
+	`
+
+	updateRelation = `
+		UPDATE
+			relation
+	`
+
+	// the query builder skips null values, so clearing the deletion needs its own statement
+	restoreRelation = `
+		UPDATE
+			relation
+		SET
+			status = 1,
+			deleted_at = NULL,
+			deleted_by = NULL,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+	`
+
+	// a block hides both users from each other whoever created it
+	readBlockedUserID = `
+		SELECT
+			target_id AS user_id
+		FROM
+			relation
+		WHERE
+			fk_user_id = ?
+			AND relation_type = 'block'
+			AND target_type = 'user'
+			AND status = 1
+		UNION
+		SELECT
+			fk_user_id AS user_id
+		FROM
+			relation
+		WHERE
+			target_id = ?
+			AND relation_type = 'block'
+			AND target_type = 'user'
+			AND status = 1
+	`
+
+	countBlock = `
+		SELECT
+			COUNT(*)
+		FROM
+			relation
+		WHERE
+			relation_type = 'block'
+			AND target_type = 'user'
+			AND status = 1
+			AND ((fk_user_id = ? AND target_id = ?) OR (fk_user_id = ? AND target_id = ?))
+	`
+
+	// the relations the user holds and the ones other users hold on them
+	eraseUserRelation = `
+		UPDATE
+			relation
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			(fk_user_id = ? OR (target_type = 'user' AND target_id = ?))
+			AND status = 1
+	`
+)
@@ -0,0 +1,124 @@
+package relation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	getRelationByKey           = "boilerplate:relation:get:%s"
+	getRelationByQueryKey      = "boilerplate:relation:get:q:%s"
+	getRelationByPaginationKey = "boilerplate:relation:get:p:%s"
+	deleteRelationKeysPattern  = "boilerplate:relation*"
+)
+
+func (r *relation) upsertCache(ctx context.Context, key string, relation entity.Relation, ttl time.Duration) error {
+	marshalledRelation, err := r.json.Marshal(relation)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = r.redis.SetEX(ctx, key, string(marshalledRelation), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (r *relation) getCache(ctx context.Context, key string) (entity.Relation, error) {
+	relation := entity.Relation{}
+
+	marshalledRelation, err := r.redis.Get(ctx, key)
+	if err != nil {
+		return relation, err
+	}
+
+	err = r.json.Unmarshal([]byte(marshalledRelation), &relation)
+	if err != nil {
+		return relation, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return relation, nil
+}
+
+func (r *relation) upsertCacheList(ctx context.Context, param entity.RelationParam, relations []entity.Relation, pg entity.Pagination, ttl time.Duration) error {
+	keyValue, err := r.json.Marshal(param)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// set relation to cache
+	marshalledRelation, err := r.json.Marshal(relations)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = r.redis.SetEX(ctx, fmt.Sprintf(getRelationByQueryKey, string(keyValue)), string(marshalledRelation), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	// set pagination to cache
+	marshalledPagination, err := r.json.Marshal(pg)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = r.redis.SetEX(ctx, fmt.Sprintf(getRelationByPaginationKey, string(keyValue)), string(marshalledPagination), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (r *relation) getCacheList(ctx context.Context, param entity.RelationParam) ([]entity.Relation, entity.Pagination, error) {
+	var (
+		relations = []entity.Relation{}
+		pg        = entity.Pagination{}
+	)
+
+	keyValue, err := r.json.Marshal(param)
+	if err != nil {
+		return relations, pg, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// get relation from redis
+	marshalledRelation, err := r.redis.Get(ctx, fmt.Sprintf(getRelationByQueryKey, string(keyValue)))
+	if err != nil {
+		return relations, pg, err
+	}
+
+	err = r.json.Unmarshal([]byte(marshalledRelation), &relations)
+	if err != nil {
+		return relations, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	// get pagination from redis
+	marshalledPagination, err := r.redis.Get(ctx, fmt.Sprintf(getRelationByPaginationKey, string(keyValue)))
+	if err != nil {
+		return relations, pg, err
+	}
+
+	err = r.json.Unmarshal([]byte(marshalledPagination), &pg)
+	if err != nil {
+		return relations, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return relations, pg, nil
+}
+
+func (r *relation) deleteCache(ctx context.Context) error {
+	err := r.redis.Del(ctx, deleteRelationKeysPattern)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
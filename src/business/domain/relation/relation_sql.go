@@ -0,0 +1,258 @@
+package relation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (r *relation) createSQL(ctx context.Context, inputParam entity.RelationInputParam) (entity.Relation, error) {
+	relation := entity.Relation{}
+
+	r.log.Debug(ctx, fmt.Sprintf("create %s relation for user %v", inputParam.RelationType, inputParam.UserID))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRelation", sql.TxOptions{})
+	if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewRelation", insertRelation, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return relation, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return relation, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no relation created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success create %s relation for user %v", inputParam.RelationType, inputParam.UserID))
+
+	relation = entity.Relation{
+		ID:           lastID,
+		UserID:       inputParam.UserID,
+		RelationType: inputParam.RelationType,
+		TargetType:   inputParam.TargetType,
+		TargetID:     inputParam.TargetID,
+		Status:       1,
+		CreatedAt:    inputParam.CreatedAt,
+		CreatedBy:    inputParam.CreatedBy,
+	}
+
+	return relation, nil
+}
+
+func (r *relation) getSQL(ctx context.Context, param entity.RelationParam) (entity.Relation, error) {
+	relation := entity.Relation{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get relation with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := r.db.Follower().QueryRow(ctx, "rRelation", readRelation+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return relation, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&relation); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return relation, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return relation, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success get relation with body: %v", param))
+
+	return relation, nil
+}
+
+func (r *relation) getListSQL(ctx context.Context, param entity.RelationParam) ([]entity.Relation, *entity.Pagination, error) {
+	relations := []entity.Relation{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get relation list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return relations, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := r.db.Follower().Query(ctx, "rRelationList", readRelation+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return relations, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		relation := entity.Relation{}
+		err := rows.StructScan(&relation)
+		if err != nil {
+			return relations, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		relations = append(relations, relation)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(relations)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(relations) > 0 && param.IncludePagination {
+		err := r.db.Follower().Get(ctx, "cRelationList", countRelation+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return relations, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	r.log.Debug(ctx, fmt.Sprintf("success get relation list with body: %v", param))
+
+	return relations, &pg, nil
+}
+
+func (r *relation) updateSQL(ctx context.Context, updateParam entity.RelationUpdateParam, selectParam entity.RelationParam) error {
+	r.log.Debug(ctx, fmt.Sprintf("update relation %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRelation", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uRelation", updateRelation+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no relation updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success update relation %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (r *relation) restoreSQL(ctx context.Context, id int64, restoredAt time.Time, restoredBy string) error {
+	r.log.Debug(ctx, fmt.Sprintf("restore relation %v", id))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRelation", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uRestoreRelation", restoreRelation, restoredAt, restoredBy, id)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no relation restored")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success restore relation %v", id))
+
+	return nil
+}
+
+// blockedUser is a row of readBlockedUserID
+type blockedUser struct {
+	UserID int64 `db:"user_id"`
+}
+
+func (r *relation) getBlockedUserIDsSQL(ctx context.Context, userID int64) ([]int64, error) {
+	userIDs := []int64{}
+
+	rows, err := r.db.Follower().Query(ctx, "rBlockedUserID", readBlockedUserID, userID, userID)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return userIDs, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := blockedUser{}
+		err := rows.StructScan(&row)
+		if err != nil {
+			return userIDs, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		userIDs = append(userIDs, row.UserID)
+	}
+
+	return userIDs, nil
+}
+
+func (r *relation) isBlockedSQL(ctx context.Context, userID, otherUserID int64) (bool, error) {
+	var count int64
+
+	err := r.db.Follower().Get(ctx, "cBlock", countBlock, &count, userID, otherUserID, otherUserID, userID)
+	if err != nil {
+		return false, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	return count > 0, nil
+}
+
+func (r *relation) eraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	r.log.Debug(ctx, fmt.Sprintf("erase relations of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserRelation", eraseUserRelation, erasedAt, erasedBy, erasedAt, erasedBy, userID, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success erase relations of user %v", userID))
+
+	return nil
+}
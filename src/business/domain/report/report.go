@@ -0,0 +1,100 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	// Create files the report under the open case of its target, opening one when there is none
+	Create(ctx context.Context, inputParam entity.ReportInputParam) (entity.Report, error)
+	GetList(ctx context.Context, param entity.ReportParam) ([]entity.Report, *entity.Pagination, error)
+	GetCase(ctx context.Context, param entity.ReportCaseParam) (entity.ReportCase, error)
+	GetCaseList(ctx context.Context, param entity.ReportCaseParam) ([]entity.ReportCase, *entity.Pagination, error)
+	UpdateCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, selectParam entity.ReportCaseParam) error
+	// CloseCase records the resolution of an open case, new reports about its target open a new case
+	CloseCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64) error
+	// CloseCaseSuspend closes an open case and suspends the user it is about in one transaction, so a failure
+	// leaves neither the suspension nor the resolution behind
+	CloseCaseSuspend(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error
+	// UnsuspendCase records on a case that suspended its user that the suspension was lifted and reactivates
+	// the user in the same transaction
+	UnsuspendCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error
+}
+
+type report struct {
+	db   sql.Interface
+	log  log.Interface
+	user userDomain.Interface
+}
+
+type InitParam struct {
+	Db   sql.Interface
+	Log  log.Interface
+	User userDomain.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &report{
+		db:   param.Db,
+		log:  param.Log,
+		user: param.User,
+	}
+}
+
+func (r *report) Create(ctx context.Context, inputParam entity.ReportInputParam) (entity.Report, error) {
+	return r.createSQL(ctx, inputParam)
+}
+
+func (r *report) GetList(ctx context.Context, param entity.ReportParam) ([]entity.Report, *entity.Pagination, error) {
+	return r.getListSQL(ctx, param)
+}
+
+func (r *report) GetCase(ctx context.Context, param entity.ReportCaseParam) (entity.ReportCase, error) {
+	return r.getCaseSQL(ctx, param)
+}
+
+func (r *report) GetCaseList(ctx context.Context, param entity.ReportCaseParam) ([]entity.ReportCase, *entity.Pagination, error) {
+	return r.getCaseListSQL(ctx, param)
+}
+
+func (r *report) UpdateCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, selectParam entity.ReportCaseParam) error {
+	return r.updateCaseSQL(ctx, updateParam, selectParam)
+}
+
+func (r *report) CloseCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64) error {
+	return r.closeCaseSQL(ctx, updateParam, caseID)
+}
+
+func (r *report) CloseCaseSuspend(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error {
+	err := r.closeCaseSuspendSQL(ctx, updateParam, caseID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = r.user.DeleteCache(ctx)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (r *report) UnsuspendCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error {
+	err := r.unsuspendCaseSQL(ctx, updateParam, caseID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = r.user.DeleteCache(ctx)
+	if err != nil {
+		r.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package report
+
+const (
+	// the unique key on the open target makes concurrent reports land on the same case
+	upsertReportCase = `
+		INSERT INTO report_case
+		(
+			target_type,
+			target_id,
+			report_count,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			?,
+			?,
+			1,
+			?,
+			?
+		)
+		ON DUPLICATE KEY UPDATE
+			report_count = report_count + 1,
+			id = LAST_INSERT_ID(id)
+	`
+
+	insertReport = `
+		INSERT INTO report
+		(
+			fk_case_id,
+			fk_reporter_id,
+			target_type,
+			target_id,
+			reason,
+			details,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_case_id,
+			:fk_reporter_id,
+			:target_type,
+			:target_id,
+			:reason,
+			:details,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readReport = `
+		SELECT
+			id,
+			fk_case_id,
+			fk_reporter_id,
+			target_type,
+			target_id,
+			reason,
+			details,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			report
+	`
+
+	countReport = `
+		SELECT
+			COUNT(*)
+		FROM
+			report
+	`
+
+	readReportCase = `
+		SELECT
+			id,
+			target_type,
+			target_id,
+			case_status,
+			report_count,
+			fk_assignee_id,
+			resolution,
+			resolution_note,
+			fk_resolved_by,
+			resolved_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			report_case
+	`
+
+	countReportCase = `
+		SELECT
+			COUNT(*)
+		FROM
+			report_case
+	`
+
+	updateReportCase = `
+		UPDATE
+			report_case
+	`
+
+	closeReportCase = `
+		UPDATE
+			report_case
+		SET
+			case_status = ?,
+			resolution = ?,
+			resolution_note = ?,
+			fk_resolved_by = ?,
+			resolved_at = ?,
+			updated_at = ?,
+			updated_by = ?,
+			is_open = NULL
+		WHERE
+			id = ?
+			AND is_open = 1
+	`
+
+	// only a case that suspended its user can lift the suspension, and only once
+	unsuspendReportCase = `
+		UPDATE
+			report_case
+		SET
+			resolution = ?,
+			resolution_note = COALESCE(?, resolution_note),
+			fk_resolved_by = ?,
+			resolved_at = ?,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+			AND case_status = 'resolved'
+			AND resolution = 'suspend_user'
+	`
+)
@@ -0,0 +1,355 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (r *report) createSQL(ctx context.Context, inputParam entity.ReportInputParam) (entity.Report, error) {
+	report := entity.Report{}
+
+	r.log.Debug(ctx, fmt.Sprintf("create report on %s %v by %v", inputParam.TargetType, inputParam.TargetID, inputParam.ReporterID))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txReport", sql.TxOptions{})
+	if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("iUpsertReportCase", upsertReportCase, inputParam.TargetType, inputParam.TargetID, inputParam.CreatedAt, inputParam.CreatedBy)
+	if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	caseID, err := res.LastInsertId()
+	if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+	inputParam.CaseID = caseID
+
+	res, err = tx.NamedExec("iNewReport", insertReport, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return report, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return report, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no report created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return report, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success create report on %s %v by %v", inputParam.TargetType, inputParam.TargetID, inputParam.ReporterID))
+
+	report = entity.Report{
+		ID:         lastID,
+		CaseID:     caseID,
+		ReporterID: inputParam.ReporterID,
+		TargetType: inputParam.TargetType,
+		TargetID:   inputParam.TargetID,
+		Reason:     inputParam.Reason,
+		Details:    inputParam.Details,
+		Status:     1,
+		CreatedAt:  inputParam.CreatedAt,
+		CreatedBy:  inputParam.CreatedBy,
+	}
+
+	return report, nil
+}
+
+func (r *report) getListSQL(ctx context.Context, param entity.ReportParam) ([]entity.Report, *entity.Pagination, error) {
+	reports := []entity.Report{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get report list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return reports, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := r.db.Follower().Query(ctx, "rReportList", readReport+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return reports, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		report := entity.Report{}
+		err := rows.StructScan(&report)
+		if err != nil {
+			return reports, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		reports = append(reports, report)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(reports)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(reports) > 0 && param.IncludePagination {
+		err := r.db.Follower().Get(ctx, "cReportList", countReport+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return reports, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	r.log.Debug(ctx, fmt.Sprintf("success get report list with body: %v", param))
+
+	return reports, &pg, nil
+}
+
+func (r *report) getCaseSQL(ctx context.Context, param entity.ReportCaseParam) (entity.ReportCase, error) {
+	reportCase := entity.ReportCase{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get report case with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return reportCase, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := r.db.Follower().QueryRow(ctx, "rReportCase", readReportCase+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return reportCase, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&reportCase); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return reportCase, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return reportCase, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success get report case with body: %v", param))
+
+	return reportCase, nil
+}
+
+func (r *report) getCaseListSQL(ctx context.Context, param entity.ReportCaseParam) ([]entity.ReportCase, *entity.Pagination, error) {
+	reportCases := []entity.ReportCase{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get report case list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return reportCases, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := r.db.Follower().Query(ctx, "rReportCaseList", readReportCase+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return reportCases, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		reportCase := entity.ReportCase{}
+		err := rows.StructScan(&reportCase)
+		if err != nil {
+			return reportCases, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		reportCases = append(reportCases, reportCase)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(reportCases)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(reportCases) > 0 && param.IncludePagination {
+		err := r.db.Follower().Get(ctx, "cReportCaseList", countReportCase+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return reportCases, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	r.log.Debug(ctx, fmt.Sprintf("success get report case list with body: %v", param))
+
+	return reportCases, &pg, nil
+}
+
+func (r *report) updateCaseSQL(ctx context.Context, updateParam entity.ReportCaseUpdateParam, selectParam entity.ReportCaseParam) error {
+	r.log.Debug(ctx, fmt.Sprintf("update report case %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txReportCase", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uReportCase", updateReportCase+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no report case updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success update report case %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (r *report) closeCaseSQL(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64) error {
+	r.log.Debug(ctx, fmt.Sprintf("close report case %v with body: %v", caseID, updateParam))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txReportCase", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	err = r.closeCaseTx(tx, updateParam, caseID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success close report case %v with body: %v", caseID, updateParam))
+
+	return nil
+}
+
+func (r *report) closeCaseSuspendSQL(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error {
+	r.log.Debug(ctx, fmt.Sprintf("close report case %v and suspend user %v", caseID, userID))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txReportCase", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	err = r.closeCaseTx(tx, updateParam, caseID)
+	if err != nil {
+		return err
+	}
+
+	err = r.user.SuspendTx(ctx, tx, userID, updateParam.ResolvedBy.Int64, updateParam.ResolvedAt.Time)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success close report case %v and suspend user %v", caseID, userID))
+
+	return nil
+}
+
+func (r *report) unsuspendCaseSQL(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64, userID int64) error {
+	r.log.Debug(ctx, fmt.Sprintf("lift suspension of report case %v on user %v", caseID, userID))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txReportCase", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uUnsuspendReportCase", unsuspendReportCase,
+		updateParam.Resolution,
+		updateParam.ResolutionNote,
+		updateParam.ResolvedBy,
+		updateParam.ResolvedAt,
+		updateParam.UpdatedAt,
+		updateParam.UpdatedBy,
+		caseID,
+	)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no report case unsuspended")
+	}
+
+	err = r.user.UnsuspendTx(ctx, tx, userID, updateParam.ResolvedBy.Int64, updateParam.ResolvedAt.Time)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success lift suspension of report case %v on user %v", caseID, userID))
+
+	return nil
+}
+
+func (r *report) closeCaseTx(tx sql.CommandTx, updateParam entity.ReportCaseUpdateParam, caseID int64) error {
+	res, err := tx.Exec("uCloseReportCase", closeReportCase,
+		updateParam.CaseStatus,
+		updateParam.Resolution,
+		updateParam.ResolutionNote,
+		updateParam.ResolvedBy,
+		updateParam.ResolvedAt,
+		updateParam.UpdatedAt,
+		updateParam.UpdatedBy,
+		caseID,
+	)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no report case closed")
+	}
+
+	return nil
+}
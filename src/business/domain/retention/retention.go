@@ -0,0 +1,61 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	Upsert(ctx context.Context, inputParam entity.RetentionPolicyInputParam) error
+	Get(ctx context.Context, param entity.RetentionPolicyParam) (entity.RetentionPolicy, error)
+	GetList(ctx context.Context, param entity.RetentionPolicyParam) ([]entity.RetentionPolicy, *entity.Pagination, error)
+	Update(ctx context.Context, updateParam entity.RetentionPolicyUpdateParam, selectParam entity.RetentionPolicyParam) error
+	// Count returns how many rows of the target a purge would delete
+	Count(ctx context.Context, param entity.RetentionPurgeParam) (int64, error)
+	// Purge hard deletes at most param.Limit rows of the target and returns how many were deleted
+	Purge(ctx context.Context, param entity.RetentionPurgeParam) (int64, error)
+}
+
+type retention struct {
+	db  sql.Interface
+	log log.Interface
+}
+
+type InitParam struct {
+	Db  sql.Interface
+	Log log.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &retention{
+		db:  param.Db,
+		log: param.Log,
+	}
+}
+
+func (r *retention) Upsert(ctx context.Context, inputParam entity.RetentionPolicyInputParam) error {
+	return r.upsertSQL(ctx, inputParam)
+}
+
+func (r *retention) Get(ctx context.Context, param entity.RetentionPolicyParam) (entity.RetentionPolicy, error) {
+	return r.getSQL(ctx, param)
+}
+
+func (r *retention) GetList(ctx context.Context, param entity.RetentionPolicyParam) ([]entity.RetentionPolicy, *entity.Pagination, error) {
+	return r.getListSQL(ctx, param)
+}
+
+func (r *retention) Update(ctx context.Context, updateParam entity.RetentionPolicyUpdateParam, selectParam entity.RetentionPolicyParam) error {
+	return r.updateSQL(ctx, updateParam, selectParam)
+}
+
+func (r *retention) Count(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	return r.countSQL(ctx, param)
+}
+
+func (r *retention) Purge(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	return r.purgeSQL(ctx, param)
+}
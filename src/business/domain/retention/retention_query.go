@@ -0,0 +1,170 @@
+package retention
+
+import "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+
+const (
+	upsertRetentionPolicy = `
+		INSERT INTO retention_policy
+		(
+			scope_type,
+			scope_id,
+			retention_days,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:scope_type,
+			:scope_id,
+			:retention_days,
+			:created_at,
+			:created_by
+		)
+		ON DUPLICATE KEY UPDATE
+			retention_days = VALUES(retention_days),
+			status = 1,
+			deleted_at = NULL,
+			deleted_by = NULL,
+			updated_at = VALUES(created_at),
+			updated_by = VALUES(created_by)
+	`
+
+	readRetentionPolicy = `
+		SELECT
+			id,
+			scope_type,
+			scope_id,
+			retention_days,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			retention_policy
+	`
+
+	countRetentionPolicy = `
+		SELECT
+			COUNT(*)
+		FROM
+			retention_policy
+	`
+
+	updateRetentionPolicy = `
+		UPDATE
+			retention_policy
+	`
+
+	// notifications of users on legal hold are kept
+	countNotificationBefore = `
+		SELECT
+			COUNT(*)
+		FROM
+			notification
+		WHERE
+			scope_type = ?
+			AND scope_id = ?
+			AND created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_hold h WHERE h.scope_type = 'user' AND h.scope_id = notification.fk_user_id AND h.status = 1
+			)
+	`
+
+	deleteNotificationBefore = `
+		DELETE FROM
+			notification
+		WHERE
+			scope_type = ?
+			AND scope_id = ?
+			AND created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_hold h WHERE h.scope_type = 'user' AND h.scope_id = notification.fk_user_id AND h.status = 1
+			)
+		LIMIT ?
+	`
+
+	// pending events are left to the relay, they are purged once published. Events of actors on
+	// legal hold are kept like their audit logs
+	countEventBefore = `
+		SELECT
+			COUNT(*)
+		FROM
+			outbox
+		WHERE
+			scope_type = ?
+			AND scope_id = ?
+			AND created_at < ?
+			AND published_at IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_hold h WHERE h.scope_type = 'user' AND h.scope_id = outbox.actor_id AND h.status = 1
+			)
+	`
+
+	// markEventPurged raises the purged seq of the scope to the last event deleteEventBefore deletes
+	// with the same arguments, so sync can tell tokens that are behind the purge
+	markEventPurged = `
+		INSERT INTO outbox_purge
+		(
+			scope_type,
+			scope_id,
+			purged_seq
+		)
+		SELECT
+			scope_type,
+			scope_id,
+			MAX(seq)
+		FROM
+			(
+				SELECT
+					scope_type,
+					scope_id,
+					seq
+				FROM
+					outbox
+				WHERE
+					scope_type = ?
+					AND scope_id = ?
+					AND created_at < ?
+					AND published_at IS NOT NULL
+					AND NOT EXISTS (
+						SELECT 1 FROM legal_hold h WHERE h.scope_type = 'user' AND h.scope_id = outbox.actor_id AND h.status = 1
+					)
+				ORDER BY
+					seq ASC
+				LIMIT ?
+			) purged
+		GROUP BY
+			scope_type,
+			scope_id
+		ON DUPLICATE KEY UPDATE
+			purged_seq = GREATEST(outbox_purge.purged_seq, VALUES(purged_seq))
+	`
+
+	deleteEventBefore = `
+		DELETE FROM
+			outbox
+		WHERE
+			scope_type = ?
+			AND scope_id = ?
+			AND created_at < ?
+			AND published_at IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_hold h WHERE h.scope_type = 'user' AND h.scope_id = outbox.actor_id AND h.status = 1
+			)
+		ORDER BY
+			seq ASC
+		LIMIT ?
+	`
+)
+
+// purgeQueries maps every retention target to its count and delete queries, mark runs before delete
+// in the same transaction when the target keeps track of what was purged
+var purgeQueries = map[string]struct{ count, mark, delete string }{
+	entity.RetentionTargetNotification: {count: countNotificationBefore, delete: deleteNotificationBefore},
+	entity.RetentionTargetEvent:        {count: countEventBefore, mark: markEventPurged, delete: deleteEventBefore},
+}
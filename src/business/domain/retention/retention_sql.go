@@ -0,0 +1,203 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (r *retention) upsertSQL(ctx context.Context, inputParam entity.RetentionPolicyInputParam) error {
+	r.log.Debug(ctx, fmt.Sprintf("upsert retention policy with body: %v", inputParam))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRetentionPolicy", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iuRetentionPolicy", upsertRetentionPolicy, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success upsert retention policy with body: %v", inputParam))
+
+	return nil
+}
+
+func (r *retention) getSQL(ctx context.Context, param entity.RetentionPolicyParam) (entity.RetentionPolicy, error) {
+	policy := entity.RetentionPolicy{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get retention policy with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return policy, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := r.db.Follower().QueryRow(ctx, "rRetentionPolicy", readRetentionPolicy+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return policy, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&policy); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return policy, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return policy, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success get retention policy with body: %v", param))
+
+	return policy, nil
+}
+
+func (r *retention) getListSQL(ctx context.Context, param entity.RetentionPolicyParam) ([]entity.RetentionPolicy, *entity.Pagination, error) {
+	policies := []entity.RetentionPolicy{}
+
+	r.log.Debug(ctx, fmt.Sprintf("get retention policy list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return policies, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := r.db.Follower().Query(ctx, "rRetentionPolicyList", readRetentionPolicy+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return policies, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		policy := entity.RetentionPolicy{}
+		err := rows.StructScan(&policy)
+		if err != nil {
+			return policies, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		policies = append(policies, policy)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(policies)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(policies) > 0 && param.IncludePagination {
+		err := r.db.Follower().Get(ctx, "cRetentionPolicyList", countRetentionPolicy+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return policies, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	r.log.Debug(ctx, fmt.Sprintf("success get retention policy list with body: %v", param))
+
+	return policies, &pg, nil
+}
+
+func (r *retention) updateSQL(ctx context.Context, updateParam entity.RetentionPolicyUpdateParam, selectParam entity.RetentionPolicyParam) error {
+	r.log.Debug(ctx, fmt.Sprintf("update retention policy %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRetentionPolicy", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uRetentionPolicy", updateRetentionPolicy+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no retention policy updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success update retention policy %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (r *retention) countSQL(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	var count int64
+
+	queries, ok := purgeQueries[param.Target]
+	if !ok {
+		return count, errors.NewWithCode(codes.CodeInvalidValue, "unknown retention target %s", param.Target)
+	}
+
+	err := r.db.Follower().Get(ctx, "cRetention"+param.Target, queries.count, &count, param.ScopeType, param.ScopeID, param.Before)
+	if err != nil {
+		return count, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	return count, nil
+}
+
+func (r *retention) purgeSQL(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	queries, ok := purgeQueries[param.Target]
+	if !ok {
+		return 0, errors.NewWithCode(codes.CodeInvalidValue, "unknown retention target %s", param.Target)
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("purge %s of %s %v created before %v", param.Target, param.ScopeType, param.ScopeID, param.Before))
+
+	tx, err := r.db.Leader().BeginTx(ctx, "txRetentionPurge", sql.TxOptions{})
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	if queries.mark != "" {
+		_, err = tx.Exec("iuRetentionMark"+param.Target, queries.mark, param.ScopeType, param.ScopeID, param.Before, param.Limit)
+		if err != nil {
+			return 0, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+		}
+	}
+
+	res, err := tx.Exec("dRetention"+param.Target, queries.delete, param.ScopeType, param.ScopeID, param.Before, param.Limit)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	r.log.Debug(ctx, fmt.Sprintf("success purge %v %s of %s %v", rowCount, param.Target, param.ScopeType, param.ScopeID))
+
+	return rowCount, nil
+}
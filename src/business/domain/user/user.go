@@ -3,12 +3,21 @@ package user
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/reyhanmichiels/go-pkg/errors"
 	"github.com/reyhanmichiels/go-pkg/log"
 	"github.com/reyhanmichiels/go-pkg/parser"
 	"github.com/reyhanmichiels/go-pkg/redis"
 	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/contact"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/devicekey"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/webhook"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
 )
 
@@ -17,28 +26,63 @@ type Interface interface {
 	Get(ctx context.Context, param entity.UserParam) (entity.User, error)
 	Create(ctx context.Context, inputParam entity.UserInputParam) (entity.User, error)
 	Update(ctx context.Context, updateParam entity.UserUpdateParam, selectParam entity.UserParam) error
+	// Erase removes the personal data of an active user and deactivates the account with its devices, keys,
+	// workspace memberships, webhooks, contacts, relations and preferences
+	Erase(ctx context.Context, userID int64, erasedAt time.Time) error
+	// SuspendTx suspends an active user inside tx together with their devices and clears the refresh token
+	// so the session cannot be renewed, DeleteCache must be called once tx is committed
+	SuspendTx(ctx context.Context, tx sql.CommandTx, userID int64, suspendedBy int64, suspendedAt time.Time) error
+	// UnsuspendTx reactivates a suspended user inside tx, they sign in again to get a new session and
+	// register their devices again, DeleteCache must be called once tx is committed
+	UnsuspendTx(ctx context.Context, tx sql.CommandTx, userID int64, unsuspendedBy int64, unsuspendedAt time.Time) error
+	// DeleteCache drops the cached users after a change committed through another domain's transaction
+	DeleteCache(ctx context.Context) error
 }
 
 type user struct {
-	db    sql.Interface
-	log   log.Interface
-	redis redis.Interface
-	json  parser.JSONInterface
+	db           sql.Interface
+	log          log.Interface
+	redis        redis.Interface
+	json         parser.JSONInterface
+	outbox       outbox.Interface
+	notification notification.Interface
+	deviceKey    devicekey.Interface
+	workspace    workspace.Interface
+	webhook      webhook.Interface
+	contact      contact.Interface
+	relation     relation.Interface
+	preference   preference.Interface
 }
 
 type InitParam struct {
-	Db    sql.Interface
-	Log   log.Interface
-	Redis redis.Interface
-	Json  parser.JSONInterface
+	Db           sql.Interface
+	Log          log.Interface
+	Redis        redis.Interface
+	Json         parser.JSONInterface
+	Outbox       outbox.Interface
+	Notification notification.Interface
+	DeviceKey    devicekey.Interface
+	Workspace    workspace.Interface
+	Webhook      webhook.Interface
+	Contact      contact.Interface
+	Relation     relation.Interface
+	Preference   preference.Interface
 }
 
 func Init(param InitParam) Interface {
 	return &user{
-		db:    param.Db,
-		log:   param.Log,
-		redis: param.Redis,
-		json:  param.Json,
+		db:           param.Db,
+		log:          param.Log,
+		redis:        param.Redis,
+		json:         param.Json,
+		outbox:       param.Outbox,
+		notification: param.Notification,
+		deviceKey:    param.DeviceKey,
+		workspace:    param.Workspace,
+		webhook:      param.Webhook,
+		contact:      param.Contact,
+		relation:     param.Relation,
+		preference:   param.Preference,
 	}
 }
 
@@ -128,3 +172,41 @@ func (u *user) Update(ctx context.Context, updateParam entity.UserUpdateParam, s
 
 	return nil
 }
+
+func (u *user) Erase(ctx context.Context, userID int64, erasedAt time.Time) error {
+	workspaceIDs, err := u.eraseSQL(ctx, userID, erasedAt)
+	if err != nil {
+		return err
+	}
+
+	cacheErrs := []error{
+		u.deleteCache(ctx),
+		u.webhook.DeleteCache(ctx),
+		u.contact.DeleteCache(ctx),
+		u.relation.DeleteCache(ctx),
+		u.preference.DeleteCache(ctx),
+	}
+	for _, workspaceID := range workspaceIDs {
+		cacheErrs = append(cacheErrs, u.workspace.DeleteMemberCache(ctx, workspaceID))
+	}
+
+	for _, err := range cacheErrs {
+		if err != nil {
+			u.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+func (u *user) SuspendTx(ctx context.Context, tx sql.CommandTx, userID int64, suspendedBy int64, suspendedAt time.Time) error {
+	return u.suspendTx(ctx, tx, userID, suspendedBy, suspendedAt)
+}
+
+func (u *user) UnsuspendTx(ctx context.Context, tx sql.CommandTx, userID int64, unsuspendedBy int64, unsuspendedAt time.Time) error {
+	return u.unsuspendTx(ctx, tx, userID, unsuspendedBy, unsuspendedAt)
+}
+
+func (u *user) DeleteCache(ctx context.Context) error {
+	return u.deleteCache(ctx)
+}
@@ -53,4 +53,50 @@ const (
 		UPDATE
 			user
 	`
+
+	// the email keeps its unique constraint satisfied and frees the address for a new account
+	eraseUser = `
+		UPDATE
+			user
+		SET
+			name = 'Deleted User',
+			email = CONCAT('deleted-', id, '@deleted.invalid'),
+			password = '',
+			refresh_token = NULL,
+			meta = NULL,
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			id = ?
+			AND status = 1
+	`
+
+	// status 2 keeps a suspended user apart from a deleted one
+	suspendUser = `
+		UPDATE
+			user
+		SET
+			refresh_token = NULL,
+			status = 2,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+			AND status = 1
+	`
+
+	unsuspendUser = `
+		UPDATE
+			user
+		SET
+			status = 1,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+			AND status = 2
+	`
 )
@@ -3,8 +3,11 @@ package user
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/reyhanmichiels/go-pkg/codes"
 	"github.com/reyhanmichiels/go-pkg/errors"
 	"github.com/reyhanmichiels/go-pkg/query"
@@ -42,6 +45,22 @@ func (u *user) createSQL(ctx context.Context, inputParam entity.UserInputParam)
 		return user, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
 	}
 
+	err = u.outbox.CreateTx(ctx, tx, entity.Event{
+		ID:        uuid.New().String(),
+		Type:      entity.EventUserCreated,
+		ScopeType: entity.EventScopeUser,
+		ScopeID:   lastID,
+		Data: entity.UserEventData{
+			ID:    lastID,
+			Name:  inputParam.Name,
+			Email: inputParam.Email,
+		},
+		CreatedAt: inputParam.CreatedAt.Time,
+	})
+	if err != nil {
+		return user, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return user, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
 	}
@@ -166,6 +185,24 @@ func (u *user) updateSQL(ctx context.Context, updateParam entity.UserUpdateParam
 		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no user updated")
 	}
 
+	// token and role writes are not profile changes, subscribers only hear about the name
+	if updateParam.Name != "" {
+		err = u.outbox.CreateTx(ctx, tx, entity.Event{
+			ID:        uuid.New().String(),
+			Type:      entity.EventUserUpdated,
+			ScopeType: entity.EventScopeUser,
+			ScopeID:   selectParam.ID,
+			Data: entity.UserEventData{
+				ID:   selectParam.ID,
+				Name: updateParam.Name,
+			},
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
 	}
@@ -174,3 +211,148 @@ func (u *user) updateSQL(ctx context.Context, updateParam entity.UserUpdateParam
 
 	return nil
 }
+
+func (u *user) eraseSQL(ctx context.Context, userID int64, erasedAt time.Time) ([]int64, error) {
+	u.log.Debug(ctx, fmt.Sprintf("erase user %v", userID))
+
+	erasedBy := strconv.FormatInt(userID, 10)
+
+	tx, err := u.db.Leader().BeginTx(ctx, "txEraseUser", sql.TxOptions{})
+	if err != nil {
+		return nil, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uEraseUser", eraseUser, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return nil, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return nil, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return nil, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no user erased")
+	}
+
+	// push tokens and e2ee keys would otherwise keep the erased account reachable
+	err = u.notification.EraseDevicesTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deviceKey.EraseTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rest of the account goes with it, nothing the user set up stays active after the erasure
+	workspaceIDs, err := u.workspace.EraseMembersTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.webhook.EraseTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.contact.EraseTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.relation.EraseTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.preference.EraseTx(ctx, tx, userID, erasedAt, erasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.outbox.CreateTx(ctx, tx, entity.Event{
+		ID:        uuid.New().String(),
+		Type:      entity.EventUserDeleted,
+		ScopeType: entity.EventScopeUser,
+		ScopeID:   userID,
+		Data: entity.UserEventData{
+			ID: userID,
+		},
+		CreatedAt: erasedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	u.log.Debug(ctx, fmt.Sprintf("success erase user %v", userID))
+
+	return workspaceIDs, nil
+}
+
+func (u *user) suspendTx(ctx context.Context, tx sql.CommandTx, userID int64, suspendedBy int64, suspendedAt time.Time) error {
+	u.log.Debug(ctx, fmt.Sprintf("suspend user %v", userID))
+
+	updatedBy := strconv.FormatInt(suspendedBy, 10)
+
+	res, err := tx.Exec("uSuspendUser", suspendUser, suspendedAt, updatedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no user suspended")
+	}
+
+	// push tokens would otherwise keep reaching the suspended account
+	err = u.notification.EraseDevicesTx(ctx, tx, userID, suspendedAt, updatedBy)
+	if err != nil {
+		return err
+	}
+
+	err = u.outbox.CreateTx(ctx, tx, entity.Event{
+		ID:        uuid.New().String(),
+		Type:      entity.EventUserSuspended,
+		ScopeType: entity.EventScopeUser,
+		ScopeID:   userID,
+		Data: entity.UserEventData{
+			ID: userID,
+		},
+		CreatedAt: suspendedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	u.log.Debug(ctx, fmt.Sprintf("success suspend user %v", userID))
+
+	return nil
+}
+
+func (u *user) unsuspendTx(ctx context.Context, tx sql.CommandTx, userID int64, unsuspendedBy int64, unsuspendedAt time.Time) error {
+	u.log.Debug(ctx, fmt.Sprintf("unsuspend user %v", userID))
+
+	res, err := tx.Exec("uUnsuspendUser", unsuspendUser, unsuspendedAt, strconv.FormatInt(unsuspendedBy, 10), userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no user unsuspended")
+	}
+
+	u.log.Debug(ctx, fmt.Sprintf("success unsuspend user %v", userID))
+
+	return nil
+}
@@ -19,6 +19,14 @@ import (
 	mock_log "github.com/reyhanmichiels/go-pkg/tests/mock/log"
 	mock_parser "github.com/reyhanmichiels/go-pkg/tests/mock/parser"
 	mock_redis "github.com/reyhanmichiels/go-pkg/tests/mock/redis"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/contact"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/devicekey"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/webhook"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -71,6 +79,14 @@ func Test_user_Create(t *testing.T) {
 		CreatedBy: mockArgsInputParam.CreatedBy,
 	}
 
+	mockEventData := entity.UserEventData{
+		ID:    1,
+		Name:  mockArgsInputParam.Name,
+		Email: mockArgsInputParam.Email,
+	}
+
+	outboxQuery := "INSERT INTO outbox"
+
 	query := regexp.QuoteMeta(`
 	INSERT INTO user
 		(
@@ -207,6 +223,26 @@ func Test_user_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "failed insert outbox",
+			args: args{
+				ctx:        context.Background(),
+				inputParam: mockArgsInputParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context, param entity.UserInputParam) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed to commit",
 			args: args{
@@ -218,11 +254,13 @@ func Test_user_Create(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit().WillReturnError(assert.AnError)
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context, param entity.UserInputParam) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 			},
 			wantErr: true,
 		},
@@ -237,11 +275,13 @@ func Test_user_Create(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit()
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context, param entity.UserInputParam) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 				mock.redis.EXPECT().Del(ctx, deleteUserKeysPattern).Return(assert.AnError)
 			},
 			wantErr: false,
@@ -258,11 +298,13 @@ func Test_user_Create(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit()
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context, param entity.UserInputParam) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 				mock.redis.EXPECT().Del(ctx, deleteUserKeysPattern).Return(nil)
 			},
 			wantErr: false,
@@ -288,7 +330,8 @@ func Test_user_Create(t *testing.T) {
 				},
 			}, logger)
 
-			d := Init(InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson})
+			outboxDom := outbox.Init(outbox.InitParam{Db: sqlClient, Log: logger, Json: mockJson})
+			d := Init(InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson, Outbox: outboxDom})
 			got, err := d.Create(tt.args.ctx, tt.args.inputParam)
 			if (err != nil) && !tt.wantErr {
 				t.Errorf("User.Create() err %v, wantErr %v", err, tt.wantErr)
@@ -340,6 +383,13 @@ func Test_user_Update(t *testing.T) {
 		ID: 1,
 	}
 
+	mockEventData := entity.UserEventData{
+		ID:   mockSelectParam.ID,
+		Name: mockUpdateParam.Name,
+	}
+
+	outboxQuery := "INSERT INTO outbox"
+
 	updateQuery := " SET name=?, refresh_token=?, updated_at=?, updated_by=?"
 	query := regexp.QuoteMeta(updateUser + updateQuery)
 
@@ -444,6 +494,27 @@ func Test_user_Update(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "failed insert outbox",
+			args: args{
+				ctx:         context.Background(),
+				updateParam: mockUpdateParam,
+				selectParam: mockSelectParam,
+			},
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "failed to commit",
 			args: args{
@@ -456,11 +527,13 @@ func Test_user_Update(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit().WillReturnError(errors.NewWithCode(codes.CodeSQLTxCommit, "failed to commit"))
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 			},
 			wantErr: true,
 		},
@@ -476,11 +549,13 @@ func Test_user_Update(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit()
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 				mock.redis.EXPECT().Del(ctx, deleteUserKeysPattern).Return(assert.AnError)
 			},
 			wantErr: false,
@@ -497,11 +572,13 @@ func Test_user_Update(t *testing.T) {
 
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(query).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
 				sqlMock.ExpectCommit()
 
 				return sqlServer, err
 			},
 			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
 				mock.redis.EXPECT().Del(ctx, deleteUserKeysPattern).Return(nil)
 			},
 			wantErr: false,
@@ -526,7 +603,8 @@ func Test_user_Update(t *testing.T) {
 				},
 			}, logger)
 
-			d := Init(InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson})
+			outboxDom := outbox.Init(outbox.InitParam{Db: sqlClient, Log: logger, Json: mockJson})
+			d := Init(InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson, Outbox: outboxDom})
 			err = d.Update(tt.args.ctx, tt.args.updateParam, tt.args.selectParam)
 			if (err != nil) && !tt.wantErr {
 				t.Errorf("User.Update() err %v, wantErr %v", err, tt.wantErr)
@@ -1295,3 +1373,613 @@ func Test_user_GetList(t *testing.T) {
 		})
 	}
 }
+
+func Test_user_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_log.NewMockInterface(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	mockRedis := mock_redis.NewMockInterface(ctrl)
+	mockJson := mock_parser.NewMockJSONInterface(ctrl)
+
+	type mockFields struct {
+		redis *mock_redis.MockInterface
+		json  *mock_parser.MockJSONInterface
+	}
+
+	mockField := mockFields{
+		redis: mockRedis,
+		json:  mockJson,
+	}
+
+	type args struct {
+		ctx      context.Context
+		userID   int64
+		erasedAt time.Time
+	}
+
+	mockArgs := args{
+		ctx:      context.Background(),
+		userID:   1,
+		erasedAt: time.Now(),
+	}
+
+	mockMemberEventData := entity.MemberEventData{
+		WorkspaceID: 5,
+		UserID:      mockArgs.userID,
+		Role:        entity.WorkspaceRoleOwner,
+	}
+
+	mockEventData := entity.UserEventData{
+		ID: mockArgs.userID,
+	}
+
+	eraseQuery := regexp.QuoteMeta(eraseUser)
+	deviceQuery := "UPDATE device SET"
+	deviceKeyQuery := "UPDATE device_key SET"
+	prekeyQuery := "UPDATE one_time_prekey SET"
+	memberReadQuery := "FROM workspace_member WHERE fk_user_id = \\? AND status = 1 FOR UPDATE"
+	memberQuery := "UPDATE workspace_member SET"
+	webhookQuery := "UPDATE webhook SET"
+	contactQuery := "UPDATE contact SET"
+	relationQuery := "UPDATE relation SET"
+	preferenceQuery := "UPDATE user_preference SET"
+	scopePreferenceQuery := "UPDATE scope_preference SET"
+	outboxQuery := "INSERT INTO outbox"
+
+	memberColumn := []string{"id", "fk_workspace_id", "fk_user_id", "member_role", "status"}
+
+	// everything the user set up is deactivated in the same transaction that erases the user
+	expectEraseAccount := func(sqlMock sqlmock.Sqlmock) {
+		sqlMock.ExpectExec(eraseQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.erasedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(deviceKeyQuery).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(prekeyQuery).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectQuery(memberReadQuery).WithArgs(mockArgs.userID).WillReturnRows(
+			sqlMock.NewRows(memberColumn).AddRow(9, mockMemberEventData.WorkspaceID, mockArgs.userID, entity.WorkspaceRoleOwner, 1))
+		sqlMock.ExpectExec(memberQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.erasedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+		sqlMock.ExpectExec(webhookQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.erasedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(contactQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.erasedAt, "1", mockArgs.userID, mockArgs.userID).WillReturnResult(driver.RowsAffected(2))
+		sqlMock.ExpectExec(relationQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.erasedAt, "1", mockArgs.userID, mockArgs.userID).WillReturnResult(driver.RowsAffected(2))
+		sqlMock.ExpectExec(preferenceQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(scopePreferenceQuery).WithArgs(mockArgs.erasedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+		sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+	}
+
+	expectEventMarshal := func(mock mockFields) {
+		mock.json.EXPECT().Marshal(mockMemberEventData).Return([]byte(`{}`), nil).Times(2)
+		mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		mockFunc    func(mock mockFields, ctx context.Context)
+		wantErr     bool
+	}{
+		{
+			name: "failed begin tx",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin().WillReturnError(errors.NewWithCode(codes.CodeSQLTxBegin, "failed to begin tx"))
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to exec",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnError(errors.NewWithCode(codes.CodeSQLTxExec, "failed to exec"))
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "no rows affected",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnResult(driver.RowsAffected(0))
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed erase devices",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed read workspace memberships",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceKeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(prekeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(memberReadQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed erase webhooks",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceKeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(prekeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(memberReadQuery).WillReturnRows(sqlMock.NewRows(memberColumn))
+				sqlMock.ExpectExec(webhookQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed erase preferences",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(eraseQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceKeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(prekeyQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectQuery(memberReadQuery).WillReturnRows(sqlMock.NewRows(memberColumn))
+				sqlMock.ExpectExec(webhookQuery).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectExec(contactQuery).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectExec(relationQuery).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectExec(preferenceQuery).WillReturnError(assert.AnError)
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to commit",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				expectEraseAccount(sqlMock)
+				sqlMock.ExpectCommit().WillReturnError(errors.NewWithCode(codes.CodeSQLTxCommit, "failed to commit"))
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				expectEventMarshal(mock)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success - but failed to delete cache",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				expectEraseAccount(sqlMock)
+				sqlMock.ExpectCommit()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				expectEventMarshal(mock)
+				mock.redis.EXPECT().Del(ctx, gomock.Any()).Return(assert.AnError).Times(6)
+			},
+			wantErr: false,
+		},
+		{
+			name: "success",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				expectEraseAccount(sqlMock)
+				sqlMock.ExpectCommit()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				expectEventMarshal(mock)
+				mock.redis.EXPECT().Del(ctx, deleteUserKeysPattern).Return(nil)
+				mock.redis.EXPECT().Del(ctx, "boilerplate:webhook*").Return(nil)
+				mock.redis.EXPECT().Del(ctx, "boilerplate:contact*").Return(nil)
+				mock.redis.EXPECT().Del(ctx, "boilerplate:relation*").Return(nil)
+				mock.redis.EXPECT().Del(ctx, "boilerplate:preference*").Return(nil)
+				mock.redis.EXPECT().Del(ctx, "boilerplate:workspace:5:member*").Return(nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mockField, tt.args.ctx)
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient := libsql.Init(libsql.Config{
+				Driver: "sqlmock",
+				Leader: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+				Follower: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+			}, logger)
+
+			outboxDom := outbox.Init(outbox.InitParam{Db: sqlClient, Log: logger, Json: mockJson})
+			d := Init(InitParam{
+				Db:           sqlClient,
+				Log:          logger,
+				Redis:        mockRedis,
+				Json:         mockJson,
+				Outbox:       outboxDom,
+				Notification: notification.Init(notification.InitParam{Db: sqlClient, Log: logger, Outbox: outboxDom}),
+				DeviceKey:    devicekey.Init(devicekey.InitParam{Db: sqlClient, Log: logger}),
+				Workspace:    workspace.Init(workspace.InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson, Outbox: outboxDom}),
+				Webhook:      webhook.Init(webhook.InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson}),
+				Contact:      contact.Init(contact.InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson}),
+				Relation:     relation.Init(relation.InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson}),
+				Preference:   preference.Init(preference.InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson}),
+			})
+			err = d.Erase(tt.args.ctx, tt.args.userID, tt.args.erasedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("User.Erase() err %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_SuspendTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_log.NewMockInterface(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	mockRedis := mock_redis.NewMockInterface(ctrl)
+	mockJson := mock_parser.NewMockJSONInterface(ctrl)
+
+	type mockFields struct {
+		redis *mock_redis.MockInterface
+		json  *mock_parser.MockJSONInterface
+	}
+
+	mockField := mockFields{
+		redis: mockRedis,
+		json:  mockJson,
+	}
+
+	type args struct {
+		ctx         context.Context
+		userID      int64
+		suspendedBy int64
+		suspendedAt time.Time
+	}
+
+	mockArgs := args{
+		ctx:         context.Background(),
+		userID:      2,
+		suspendedBy: 1,
+		suspendedAt: time.Now(),
+	}
+
+	mockEventData := entity.UserEventData{
+		ID: mockArgs.userID,
+	}
+
+	suspendQuery := regexp.QuoteMeta(suspendUser)
+
+	// a suspended user must stay apart from a deleted one, whose status is 0
+	assert.Contains(t, suspendUser, fmt.Sprintf("status = %d,", entity.UserStatusSuspended))
+	deviceQuery := "UPDATE device SET"
+	outboxQuery := "INSERT INTO outbox"
+
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		mockFunc    func(mock mockFields, ctx context.Context)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(suspendQuery).WillReturnError(errors.NewWithCode(codes.CodeSQLTxExec, "failed to exec"))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "no rows affected",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(suspendQuery).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed erase devices",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(suspendQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed insert outbox",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(suspendQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnError(assert.AnError)
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(suspendQuery).WithArgs(mockArgs.suspendedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(deviceQuery).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			mockFunc: func(mock mockFields, ctx context.Context) {
+				mock.json.EXPECT().Marshal(mockEventData).Return([]byte(`{}`), nil)
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc(mockField, tt.args.ctx)
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient := libsql.Init(libsql.Config{
+				Driver: "sqlmock",
+				Leader: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+				Follower: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+			}, logger)
+
+			outboxDom := outbox.Init(outbox.InitParam{Db: sqlClient, Log: logger, Json: mockJson})
+			d := Init(InitParam{
+				Db:           sqlClient,
+				Log:          logger,
+				Redis:        mockRedis,
+				Json:         mockJson,
+				Outbox:       outboxDom,
+				Notification: notification.Init(notification.InitParam{Db: sqlClient, Log: logger, Outbox: outboxDom}),
+			})
+
+			// the caller owns the transaction, SuspendTx neither commits nor drops the cache
+			tx, err := sqlClient.Leader().BeginTx(tt.args.ctx, "txSuspendUser", libsql.TxOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+
+			err = d.SuspendTx(tt.args.ctx, tx, tt.args.userID, tt.args.suspendedBy, tt.args.suspendedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("User.SuspendTx() err %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_UnsuspendTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_log.NewMockInterface(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	mockRedis := mock_redis.NewMockInterface(ctrl)
+	mockJson := mock_parser.NewMockJSONInterface(ctrl)
+
+	type args struct {
+		ctx           context.Context
+		userID        int64
+		unsuspendedBy int64
+		unsuspendedAt time.Time
+	}
+
+	mockArgs := args{
+		ctx:           context.Background(),
+		userID:        2,
+		unsuspendedBy: 1,
+		unsuspendedAt: time.Now(),
+	}
+
+	unsuspendQuery := regexp.QuoteMeta(unsuspendUser)
+
+	tests := []struct {
+		name        string
+		args        args
+		prepSqlMock func() (*sql.DB, error)
+		wantErr     bool
+	}{
+		{
+			name: "failed to exec",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(unsuspendQuery).WillReturnError(errors.NewWithCode(codes.CodeSQLTxExec, "failed to exec"))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "user is not suspended",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(unsuspendQuery).WillReturnResult(driver.RowsAffected(0))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			args: mockArgs,
+			prepSqlMock: func() (*sql.DB, error) {
+				sqlServer, sqlMock, err := sqlmock.New()
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(unsuspendQuery).WithArgs(mockArgs.unsuspendedAt, "1", mockArgs.userID).WillReturnResult(driver.RowsAffected(1))
+				sqlMock.ExpectRollback()
+
+				return sqlServer, err
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlServer, err := tt.prepSqlMock()
+			if err != nil {
+				t.Error(err)
+			}
+			defer sqlServer.Close()
+
+			sqlClient := libsql.Init(libsql.Config{
+				Driver: "sqlmock",
+				Leader: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+				Follower: libsql.ConnConfig{
+					MockDB: sqlServer,
+				},
+			}, logger)
+
+			d := Init(InitParam{Db: sqlClient, Log: logger, Redis: mockRedis, Json: mockJson})
+
+			tx, err := sqlClient.Leader().BeginTx(tt.args.ctx, "txUnsuspendUser", libsql.TxOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+
+			err = d.UnsuspendTx(tt.args.ctx, tx, tt.args.userID, tt.args.unsuspendedBy, tt.args.unsuspendedAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("User.UnsuspendTx() err %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
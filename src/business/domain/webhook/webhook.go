@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	GetList(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, *entity.Pagination, error)
+	Get(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error)
+	Create(ctx context.Context, inputParam entity.WebhookInputParam) (entity.Webhook, error)
+	Update(ctx context.Context, updateParam entity.WebhookUpdateParam, selectParam entity.WebhookParam) error
+	// CreateDelivery queues the delivery of an event, queueing the same event for a webhook again is a no-op
+	CreateDelivery(ctx context.Context, inputParam entity.WebhookDeliveryInputParam) error
+	GetDeliveryList(ctx context.Context, param entity.WebhookDeliveryParam) ([]entity.WebhookDelivery, *entity.Pagination, error)
+	// GetDueDeliveryList returns at most limit pending deliveries whose next attempt is due before the given time
+	GetDueDeliveryList(ctx context.Context, before time.Time, limit int) ([]entity.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, updateParam entity.WebhookDeliveryUpdateParam, selectParam entity.WebhookDeliveryParam) error
+	// EraseTx deactivates every webhook the user registered inside tx, DeleteCache must be called once tx is committed
+	EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error
+	// DeleteCache drops the cached webhooks after a change committed through another domain's transaction
+	DeleteCache(ctx context.Context) error
+}
+
+type webhook struct {
+	db    sql.Interface
+	log   log.Interface
+	redis redis.Interface
+	json  parser.JSONInterface
+}
+
+type InitParam struct {
+	Db    sql.Interface
+	Log   log.Interface
+	Redis redis.Interface
+	Json  parser.JSONInterface
+}
+
+func Init(param InitParam) Interface {
+	return &webhook{
+		db:    param.Db,
+		log:   param.Log,
+		redis: param.Redis,
+		json:  param.Json,
+	}
+}
+
+func (w *webhook) GetList(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, *entity.Pagination, error) {
+	if !param.BypassCache {
+		webhooks, pg, err := w.getCacheList(ctx, param)
+		switch {
+		case errors.Is(err, redis.Nil):
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return webhooks, &pg, nil
+		}
+	}
+
+	webhooks, pg, err := w.getListSQL(ctx, param)
+	if err != nil {
+		return webhooks, pg, err
+	}
+
+	err = w.upsertCacheList(ctx, param, webhooks, *pg, w.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return webhooks, pg, nil
+}
+
+func (w *webhook) Get(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error) {
+	webhook := entity.Webhook{}
+
+	marshalledParam, err := w.json.Marshal(param)
+	if err != nil {
+		return webhook, err
+	}
+
+	if !param.BypassCache {
+		webhook, err = w.getCache(ctx, fmt.Sprintf(getWebhookByKey, string(marshalledParam)))
+		switch {
+		case errors.Is(err, redis.Nil):
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return webhook, nil
+		}
+	}
+
+	webhook, err = w.getSQL(ctx, param)
+	if err != nil {
+		return webhook, err
+	}
+
+	err = w.upsertCache(ctx, fmt.Sprintf(getWebhookByKey, string(marshalledParam)), webhook, w.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return webhook, nil
+}
+
+func (w *webhook) Create(ctx context.Context, inputParam entity.WebhookInputParam) (entity.Webhook, error) {
+	webhook, err := w.createSQL(ctx, inputParam)
+	if err != nil {
+		return webhook, err
+	}
+
+	err = w.deleteCache(ctx)
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return webhook, nil
+}
+
+func (w *webhook) Update(ctx context.Context, updateParam entity.WebhookUpdateParam, selectParam entity.WebhookParam) error {
+	err := w.updateSQL(ctx, updateParam, selectParam)
+	if err != nil {
+		return err
+	}
+
+	err = w.deleteCache(ctx)
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (w *webhook) CreateDelivery(ctx context.Context, inputParam entity.WebhookDeliveryInputParam) error {
+	return w.createDeliverySQL(ctx, inputParam)
+}
+
+func (w *webhook) GetDeliveryList(ctx context.Context, param entity.WebhookDeliveryParam) ([]entity.WebhookDelivery, *entity.Pagination, error) {
+	return w.getDeliveryListSQL(ctx, param)
+}
+
+func (w *webhook) GetDueDeliveryList(ctx context.Context, before time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	return w.getDueDeliveryListSQL(ctx, before, limit)
+}
+
+func (w *webhook) UpdateDelivery(ctx context.Context, updateParam entity.WebhookDeliveryUpdateParam, selectParam entity.WebhookDeliveryParam) error {
+	return w.updateDeliverySQL(ctx, updateParam, selectParam)
+}
+
+func (w *webhook) EraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	return w.eraseTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (w *webhook) DeleteCache(ctx context.Context) error {
+	return w.deleteCache(ctx)
+}
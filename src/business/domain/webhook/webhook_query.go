@@ -0,0 +1,148 @@
+package webhook
+
+const (
+	insertWebhook = `
+		INSERT INTO webhook
+		(
+			fk_user_id,
+			scope_type,
+			scope_id,
+			url,
+			secret,
+			events,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_user_id,
+			:scope_type,
+			:scope_id,
+			:url,
+			:secret,
+			:events,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readWebhook = `
+		SELECT
+			id,
+			fk_user_id,
+			scope_type,
+			scope_id,
+			url,
+			secret,
+			events,
+			failure_count,
+			is_disabled,
+			disabled_at,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			webhook
+	`
+
+	countWebhook = `
+		SELECT
+			COUNT(*)
+		FROM
+			webhook
+	`
+
+	updateWebhook = `
+		UPDATE
+			webhook
+	`
+
+	// an event is queued once per webhook, so a re-published event does not deliver twice
+	insertWebhookDelivery = `
+		INSERT INTO webhook_delivery
+		(
+			fk_webhook_id,
+			event_id,
+			event_type,
+			payload,
+			delivery_status,
+			next_attempt_at,
+			created_at
+		)
+		VALUES
+		(
+			:fk_webhook_id,
+			:event_id,
+			:event_type,
+			:payload,
+			:delivery_status,
+			:next_attempt_at,
+			:created_at
+		)
+		ON DUPLICATE KEY UPDATE
+			id = id
+	`
+
+	readWebhookDelivery = `
+		SELECT
+			id,
+			fk_webhook_id,
+			event_id,
+			event_type,
+			payload,
+			attempt,
+			delivery_status,
+			next_attempt_at,
+			response_code,
+			error_message,
+			duration_ms,
+			status,
+			created_at,
+			updated_at
+		FROM
+			webhook_delivery
+	`
+
+	readDueWebhookDelivery = readWebhookDelivery + `
+		WHERE
+			delivery_status = 'pending'
+			AND next_attempt_at <= ?
+			AND status = 1
+		ORDER BY
+			next_attempt_at ASC,
+			id ASC
+		LIMIT ?
+	`
+
+	countWebhookDelivery = `
+		SELECT
+			COUNT(*)
+		FROM
+			webhook_delivery
+	`
+
+	updateWebhookDelivery = `
+		UPDATE
+			webhook_delivery
+	`
+
+	eraseUserWebhook = `
+		UPDATE
+			webhook
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+)
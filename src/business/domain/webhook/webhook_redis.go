@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	getWebhookByKey           = "boilerplate:webhook:get:%s"
+	getWebhookByQueryKey      = "boilerplate:webhook:get:q:%s"
+	getWebhookByPaginationKey = "boilerplate:webhook:get:p:%s"
+	deleteWebhookKeysPattern  = "boilerplate:webhook*"
+)
+
+func (w *webhook) upsertCache(ctx context.Context, key string, webhook entity.Webhook, ttl time.Duration) error {
+	marshalledWebhook, err := w.json.Marshal(webhook)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, key, string(marshalledWebhook), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (w *webhook) getCache(ctx context.Context, key string) (entity.Webhook, error) {
+	webhook := entity.Webhook{}
+
+	marshalledWebhook, err := w.redis.Get(ctx, key)
+	if err != nil {
+		return webhook, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledWebhook), &webhook)
+	if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return webhook, nil
+}
+
+func (w *webhook) upsertCacheList(ctx context.Context, param entity.WebhookParam, webhooks []entity.Webhook, pg entity.Pagination, ttl time.Duration) error {
+	keyValue, err := w.json.Marshal(param)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// set webhook to cache
+	marshalledWebhook, err := w.json.Marshal(webhooks)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, fmt.Sprintf(getWebhookByQueryKey, string(keyValue)), string(marshalledWebhook), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	// set pagination to cache
+	marshalledPagination, err := w.json.Marshal(pg)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, fmt.Sprintf(getWebhookByPaginationKey, string(keyValue)), string(marshalledPagination), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (w *webhook) getCacheList(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, entity.Pagination, error) {
+	var (
+		webhooks = []entity.Webhook{}
+		pg       = entity.Pagination{}
+	)
+
+	keyValue, err := w.json.Marshal(param)
+	if err != nil {
+		return webhooks, pg, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// get webhook from redis
+	marshalledWebhook, err := w.redis.Get(ctx, fmt.Sprintf(getWebhookByQueryKey, string(keyValue)))
+	if err != nil {
+		return webhooks, pg, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledWebhook), &webhooks)
+	if err != nil {
+		return webhooks, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	// get pagination from redis
+	marshalledPagination, err := w.redis.Get(ctx, fmt.Sprintf(getWebhookByPaginationKey, string(keyValue)))
+	if err != nil {
+		return webhooks, pg, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledPagination), &pg)
+	if err != nil {
+		return webhooks, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return webhooks, pg, nil
+}
+
+func (w *webhook) deleteCache(ctx context.Context) error {
+	err := w.redis.Del(ctx, deleteWebhookKeysPattern)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (w *webhook) createSQL(ctx context.Context, inputParam entity.WebhookInputParam) (entity.Webhook, error) {
+	webhook := entity.Webhook{}
+
+	w.log.Debug(ctx, fmt.Sprintf("create webhook for %s %v", inputParam.ScopeType, inputParam.ScopeID))
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWebhook", sql.TxOptions{})
+	if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewWebhook", insertWebhook, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return webhook, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return webhook, errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no webhook created")
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success create webhook for %s %v", inputParam.ScopeType, inputParam.ScopeID))
+
+	webhook = entity.Webhook{
+		ID:        lastID,
+		UserID:    inputParam.UserID,
+		ScopeType: inputParam.ScopeType,
+		ScopeID:   inputParam.ScopeID,
+		URL:       inputParam.URL,
+		Secret:    inputParam.Secret,
+		Events:    inputParam.EventList,
+		Status:    1,
+		CreatedAt: inputParam.CreatedAt,
+		CreatedBy: inputParam.CreatedBy,
+	}
+
+	return webhook, nil
+}
+
+func (w *webhook) getSQL(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error) {
+	webhook := entity.Webhook{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get webhook with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := w.db.Follower().QueryRow(ctx, "rWebhook", readWebhook+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return webhook, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&webhook); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return webhook, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return webhook, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success get webhook with body: %v", param))
+
+	return webhook, nil
+}
+
+func (w *webhook) getListSQL(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, *entity.Pagination, error) {
+	webhooks := []entity.Webhook{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get webhook list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return webhooks, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := w.db.Follower().Query(ctx, "rWebhookList", readWebhook+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return webhooks, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook := entity.Webhook{}
+		err := rows.StructScan(&webhook)
+		if err != nil {
+			return webhooks, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(webhooks)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(webhooks) > 0 && param.IncludePagination {
+		err := w.db.Follower().Get(ctx, "cWebhookList", countWebhook+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return webhooks, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	w.log.Debug(ctx, fmt.Sprintf("success get webhook list with body: %v", param))
+
+	return webhooks, &pg, nil
+}
+
+func (w *webhook) updateSQL(ctx context.Context, updateParam entity.WebhookUpdateParam, selectParam entity.WebhookParam) error {
+	w.log.Debug(ctx, fmt.Sprintf("update webhook %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWebhook", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uWebhook", updateWebhook+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no webhook updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success update webhook %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (w *webhook) createDeliverySQL(ctx context.Context, inputParam entity.WebhookDeliveryInputParam) error {
+	w.log.Debug(ctx, fmt.Sprintf("queue webhook %v delivery of event %s", inputParam.WebhookID, inputParam.EventID))
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWebhookDelivery", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("iNewWebhookDelivery", insertWebhookDelivery, inputParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success queue webhook %v delivery of event %s", inputParam.WebhookID, inputParam.EventID))
+
+	return nil
+}
+
+func (w *webhook) getDueDeliveryListSQL(ctx context.Context, before time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries := []entity.WebhookDelivery{}
+
+	rows, err := w.db.Leader().Query(ctx, "rDueWebhookDelivery", readDueWebhookDelivery, before, limit)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return deliveries, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery := entity.WebhookDelivery{}
+		err := rows.StructScan(&delivery)
+		if err != nil {
+			return deliveries, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhook) updateDeliverySQL(ctx context.Context, updateParam entity.WebhookDeliveryUpdateParam, selectParam entity.WebhookDeliveryParam) error {
+	w.log.Debug(ctx, fmt.Sprintf("update webhook delivery %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWebhookDelivery", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uWebhookDelivery", updateWebhookDelivery+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no webhook delivery updated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success update webhook delivery %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (w *webhook) getDeliveryListSQL(ctx context.Context, param entity.WebhookDeliveryParam) ([]entity.WebhookDelivery, *entity.Pagination, error) {
+	deliveries := []entity.WebhookDelivery{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get webhook delivery list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return deliveries, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := w.db.Follower().Query(ctx, "rWebhookDeliveryList", readWebhookDelivery+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return deliveries, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery := entity.WebhookDelivery{}
+		err := rows.StructScan(&delivery)
+		if err != nil {
+			return deliveries, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(deliveries)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(deliveries) > 0 && param.IncludePagination {
+		err := w.db.Follower().Get(ctx, "cWebhookDeliveryList", countWebhookDelivery+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return deliveries, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	w.log.Debug(ctx, fmt.Sprintf("success get webhook delivery list with body: %v", param))
+
+	return deliveries, &pg, nil
+}
+
+func (w *webhook) eraseTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) error {
+	w.log.Debug(ctx, fmt.Sprintf("erase webhooks of user %v", userID))
+
+	_, err := tx.Exec("uEraseUserWebhook", eraseUserWebhook, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success erase webhooks of user %v", userID))
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	outboxDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+type Interface interface {
+	// Create inserts the workspace together with its owner membership
+	Create(ctx context.Context, inputParam entity.WorkspaceInputParam) (entity.Workspace, error)
+	Get(ctx context.Context, param entity.WorkspaceParam) (entity.Workspace, error)
+	// GetListByMember returns the active workspaces the user is an active member of
+	GetListByMember(ctx context.Context, userID int64) ([]entity.Workspace, error)
+	CreateMember(ctx context.Context, inputParam entity.WorkspaceMemberInputParam) (entity.WorkspaceMember, error)
+	GetMember(ctx context.Context, param entity.WorkspaceMemberParam) (entity.WorkspaceMember, error)
+	GetMemberList(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, *entity.Pagination, error)
+	UpdateMember(ctx context.Context, updateParam entity.WorkspaceMemberUpdateParam, selectParam entity.WorkspaceMemberParam) error
+	// RestoreMember reactivates a removed member with member.MemberRole and clears its deletion
+	RestoreMember(ctx context.Context, member entity.WorkspaceMember, restoredAt time.Time, restoredBy string) error
+	// EraseMembersTx removes the user from every workspace inside tx and returns the workspaces they left,
+	// their member caches are dropped with DeleteMemberCache once tx commits
+	EraseMembersTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) ([]int64, error)
+	// DeleteMemberCache drops the cached members of the workspace after a change committed through another domain's transaction
+	DeleteMemberCache(ctx context.Context, workspaceID int64) error
+}
+
+type workspace struct {
+	db     sql.Interface
+	log    log.Interface
+	redis  redis.Interface
+	json   parser.JSONInterface
+	outbox outboxDomain.Interface
+}
+
+type InitParam struct {
+	Db     sql.Interface
+	Log    log.Interface
+	Redis  redis.Interface
+	Json   parser.JSONInterface
+	Outbox outboxDomain.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &workspace{
+		db:     param.Db,
+		log:    param.Log,
+		redis:  param.Redis,
+		json:   param.Json,
+		outbox: param.Outbox,
+	}
+}
+
+func (w *workspace) Create(ctx context.Context, inputParam entity.WorkspaceInputParam) (entity.Workspace, error) {
+	return w.createSQL(ctx, inputParam)
+}
+
+func (w *workspace) Get(ctx context.Context, param entity.WorkspaceParam) (entity.Workspace, error) {
+	workspace := entity.Workspace{}
+
+	marshalledParam, err := w.json.Marshal(param)
+	if err != nil {
+		return workspace, err
+	}
+
+	if !param.BypassCache {
+		workspace, err = w.getCache(ctx, fmt.Sprintf(getWorkspaceByKey, string(marshalledParam)))
+		switch {
+		case errors.Is(err, redis.Nil):
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return workspace, nil
+		}
+	}
+
+	workspace, err = w.getSQL(ctx, param)
+	if err != nil {
+		return workspace, err
+	}
+
+	err = w.upsertCache(ctx, fmt.Sprintf(getWorkspaceByKey, string(marshalledParam)), workspace, w.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return workspace, nil
+}
+
+func (w *workspace) GetListByMember(ctx context.Context, userID int64) ([]entity.Workspace, error) {
+	return w.getListByMemberSQL(ctx, userID)
+}
+
+func (w *workspace) CreateMember(ctx context.Context, inputParam entity.WorkspaceMemberInputParam) (entity.WorkspaceMember, error) {
+	member, err := w.createMemberSQL(ctx, inputParam)
+	if err != nil {
+		return member, err
+	}
+
+	err = w.deleteMemberCache(ctx, inputParam.WorkspaceID)
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return member, nil
+}
+
+func (w *workspace) GetMember(ctx context.Context, param entity.WorkspaceMemberParam) (entity.WorkspaceMember, error) {
+	member := entity.WorkspaceMember{}
+
+	marshalledParam, err := w.json.Marshal(param)
+	if err != nil {
+		return member, err
+	}
+
+	key := fmt.Sprintf(getWorkspaceMemberByKey, param.WorkspaceID, string(marshalledParam))
+	if !param.BypassCache {
+		member, err = w.getMemberCache(ctx, key)
+		switch {
+		case errors.Is(err, redis.Nil):
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return member, nil
+		}
+	}
+
+	member, err = w.getMemberSQL(ctx, param)
+	if err != nil {
+		return member, err
+	}
+
+	err = w.upsertMemberCache(ctx, key, member, w.redis.GetDefaultTTL(ctx))
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return member, nil
+}
+
+func (w *workspace) GetMemberList(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, *entity.Pagination, error) {
+	// member caches are namespaced per workspace, lookups across workspaces always go to the database
+	useCache := !param.BypassCache && param.WorkspaceID > 0
+
+	if useCache {
+		members, pg, err := w.getMemberCacheList(ctx, param)
+		switch {
+		case errors.Is(err, redis.Nil):
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedisNil, err.Error()))
+		case err != nil:
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		default:
+			return members, &pg, nil
+		}
+	}
+
+	members, pg, err := w.getMemberListSQL(ctx, param)
+	if err != nil {
+		return members, pg, err
+	}
+
+	if useCache {
+		err = w.upsertMemberCacheList(ctx, param, members, *pg, w.redis.GetDefaultTTL(ctx))
+		if err != nil {
+			w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		}
+	}
+
+	return members, pg, nil
+}
+
+func (w *workspace) UpdateMember(ctx context.Context, updateParam entity.WorkspaceMemberUpdateParam, selectParam entity.WorkspaceMemberParam) error {
+	err := w.updateMemberSQL(ctx, updateParam, selectParam)
+	if err != nil {
+		return err
+	}
+
+	err = w.deleteMemberCache(ctx, selectParam.WorkspaceID)
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (w *workspace) RestoreMember(ctx context.Context, member entity.WorkspaceMember, restoredAt time.Time, restoredBy string) error {
+	err := w.restoreMemberSQL(ctx, member, restoredAt, restoredBy)
+	if err != nil {
+		return err
+	}
+
+	err = w.deleteMemberCache(ctx, member.WorkspaceID)
+	if err != nil {
+		w.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+	}
+
+	return nil
+}
+
+func (w *workspace) EraseMembersTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) ([]int64, error) {
+	return w.eraseMembersTx(ctx, tx, userID, erasedAt, erasedBy)
+}
+
+func (w *workspace) DeleteMemberCache(ctx context.Context, workspaceID int64) error {
+	return w.deleteMemberCache(ctx, workspaceID)
+}
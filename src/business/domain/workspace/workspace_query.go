@@ -0,0 +1,170 @@
+package workspace
+
+const (
+	insertWorkspace = `
+		INSERT INTO workspace
+		(
+			fk_owner_id,
+			name,
+			slug,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_owner_id,
+			:name,
+			:slug,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readWorkspace = `
+		SELECT
+			id,
+			fk_owner_id,
+			name,
+			slug,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			workspace
+	`
+
+	insertWorkspaceMember = `
+		INSERT INTO workspace_member
+		(
+			fk_workspace_id,
+			fk_user_id,
+			member_role,
+			created_at,
+			created_by
+		)
+		VALUES
+		(
+			:fk_workspace_id,
+			:fk_user_id,
+			:member_role,
+			:created_at,
+			:created_by
+		)
+	`
+
+	readWorkspaceMember = `
+		SELECT
+			id,
+			fk_workspace_id,
+			fk_user_id,
+			member_role,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			workspace_member
+	`
+
+	// the active workspaces a user is an active member of
+	readMemberWorkspace = `
+		SELECT
+			w.id,
+			w.fk_owner_id,
+			w.name,
+			w.slug,
+			w.status,
+			w.flag,
+			w.meta,
+			w.created_at,
+			w.created_by,
+			w.updated_at,
+			w.updated_by,
+			w.deleted_at,
+			w.deleted_by
+		FROM
+			workspace w
+			JOIN workspace_member m ON m.fk_workspace_id = w.id
+		WHERE
+			m.fk_user_id = ?
+			AND m.status = 1
+			AND w.status = 1
+		ORDER BY
+			w.id ASC
+	`
+
+	countWorkspaceMember = `
+		SELECT
+			COUNT(*)
+		FROM
+			workspace_member
+	`
+
+	updateWorkspaceMember = `
+		UPDATE
+			workspace_member
+	`
+
+	restoreWorkspaceMember = `
+		UPDATE
+			workspace_member
+		SET
+			member_role = ?,
+			status = 1,
+			deleted_at = NULL,
+			deleted_by = NULL,
+			updated_at = ?,
+			updated_by = ?
+		WHERE
+			id = ?
+	`
+
+	// the active memberships of a user, locked so a concurrent restore waits for the erasure
+	readUserWorkspaceMemberForUpdate = `
+		SELECT
+			id,
+			fk_workspace_id,
+			fk_user_id,
+			member_role,
+			status,
+			flag,
+			meta,
+			created_at,
+			created_by,
+			updated_at,
+			updated_by,
+			deleted_at,
+			deleted_by
+		FROM
+			workspace_member
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+		FOR UPDATE
+	`
+
+	eraseUserWorkspaceMember = `
+		UPDATE
+			workspace_member
+		SET
+			status = 0,
+			updated_at = ?,
+			updated_by = ?,
+			deleted_at = ?,
+			deleted_by = ?
+		WHERE
+			fk_user_id = ?
+			AND status = 1
+	`
+)
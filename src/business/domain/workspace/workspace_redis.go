@@ -0,0 +1,157 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	getWorkspaceByKey = "boilerplate:workspace:get:%s"
+
+	// member keys are namespaced by workspace id so invalidating one workspace leaves the others cached
+	getWorkspaceMemberByKey           = "boilerplate:workspace:%d:member:get:%s"
+	getWorkspaceMemberByQueryKey      = "boilerplate:workspace:%d:member:get:q:%s"
+	getWorkspaceMemberByPaginationKey = "boilerplate:workspace:%d:member:get:p:%s"
+	deleteWorkspaceMemberKeysPattern  = "boilerplate:workspace:%d:member*"
+)
+
+func (w *workspace) upsertCache(ctx context.Context, key string, workspace entity.Workspace, ttl time.Duration) error {
+	marshalledWorkspace, err := w.json.Marshal(workspace)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, key, string(marshalledWorkspace), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (w *workspace) getCache(ctx context.Context, key string) (entity.Workspace, error) {
+	workspace := entity.Workspace{}
+
+	marshalledWorkspace, err := w.redis.Get(ctx, key)
+	if err != nil {
+		return workspace, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledWorkspace), &workspace)
+	if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return workspace, nil
+}
+
+func (w *workspace) upsertMemberCache(ctx context.Context, key string, member entity.WorkspaceMember, ttl time.Duration) error {
+	marshalledMember, err := w.json.Marshal(member)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, key, string(marshalledMember), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (w *workspace) getMemberCache(ctx context.Context, key string) (entity.WorkspaceMember, error) {
+	member := entity.WorkspaceMember{}
+
+	marshalledMember, err := w.redis.Get(ctx, key)
+	if err != nil {
+		return member, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledMember), &member)
+	if err != nil {
+		return member, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return member, nil
+}
+
+func (w *workspace) upsertMemberCacheList(ctx context.Context, param entity.WorkspaceMemberParam, members []entity.WorkspaceMember, pg entity.Pagination, ttl time.Duration) error {
+	keyValue, err := w.json.Marshal(param)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// set member to cache
+	marshalledMember, err := w.json.Marshal(members)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, fmt.Sprintf(getWorkspaceMemberByQueryKey, param.WorkspaceID, string(keyValue)), string(marshalledMember), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	// set pagination to cache
+	marshalledPagination, err := w.json.Marshal(pg)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	err = w.redis.SetEX(ctx, fmt.Sprintf(getWorkspaceMemberByPaginationKey, param.WorkspaceID, string(keyValue)), string(marshalledPagination), ttl)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func (w *workspace) getMemberCacheList(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, entity.Pagination, error) {
+	var (
+		members = []entity.WorkspaceMember{}
+		pg      = entity.Pagination{}
+	)
+
+	keyValue, err := w.json.Marshal(param)
+	if err != nil {
+		return members, pg, errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// get member from redis
+	marshalledMember, err := w.redis.Get(ctx, fmt.Sprintf(getWorkspaceMemberByQueryKey, param.WorkspaceID, string(keyValue)))
+	if err != nil {
+		return members, pg, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledMember), &members)
+	if err != nil {
+		return members, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	// get pagination from redis
+	marshalledPagination, err := w.redis.Get(ctx, fmt.Sprintf(getWorkspaceMemberByPaginationKey, param.WorkspaceID, string(keyValue)))
+	if err != nil {
+		return members, pg, err
+	}
+
+	err = w.json.Unmarshal([]byte(marshalledPagination), &pg)
+	if err != nil {
+		return members, pg, errors.NewWithCode(codes.CodeUnmarshal, err.Error())
+	}
+
+	return members, pg, nil
+}
+
+func (w *workspace) deleteMemberCache(ctx context.Context, workspaceID int64) error {
+	err := w.redis.Del(ctx, fmt.Sprintf(deleteWorkspaceMemberKeysPattern, workspaceID))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
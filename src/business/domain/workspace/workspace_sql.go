@@ -0,0 +1,429 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	"github.com/reyhanmichiels/go-pkg/sql"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+func (w *workspace) createSQL(ctx context.Context, inputParam entity.WorkspaceInputParam) (entity.Workspace, error) {
+	workspace := entity.Workspace{}
+
+	w.log.Debug(ctx, fmt.Sprintf("create workspace with body: %v", inputParam))
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWorkspace", sql.TxOptions{})
+	if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewWorkspace", insertWorkspace, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return workspace, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	// the creator becomes the owner in the same transaction so a workspace never exists without one
+	_, err = tx.NamedExec("iNewWorkspaceOwner", insertWorkspaceMember, entity.WorkspaceMemberInputParam{
+		WorkspaceID: lastID,
+		UserID:      inputParam.OwnerID,
+		MemberRole:  entity.WorkspaceRoleOwner,
+		CreatedAt:   inputParam.CreatedAt,
+		CreatedBy:   inputParam.CreatedBy,
+	})
+	if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	err = w.createMemberEventTx(ctx, tx, entity.EventMemberJoined, entity.MemberEventData{
+		WorkspaceID: lastID,
+		UserID:      inputParam.OwnerID,
+		Role:        entity.WorkspaceRoleOwner,
+	}, inputParam.CreatedAt.Time)
+	if err != nil {
+		return workspace, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success create workspace with body: %v", inputParam))
+
+	workspace = entity.Workspace{
+		ID:        lastID,
+		OwnerID:   inputParam.OwnerID,
+		Name:      inputParam.Name,
+		Slug:      inputParam.Slug,
+		Status:    1,
+		CreatedAt: inputParam.CreatedAt,
+		CreatedBy: inputParam.CreatedBy,
+	}
+
+	return workspace, nil
+}
+
+func (w *workspace) getSQL(ctx context.Context, param entity.WorkspaceParam) (entity.Workspace, error) {
+	workspace := entity.Workspace{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get workspace with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := w.db.Follower().QueryRow(ctx, "rWorkspace", readWorkspace+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return workspace, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&workspace); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return workspace, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return workspace, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success get workspace with body: %v", param))
+
+	return workspace, nil
+}
+
+func (w *workspace) getListByMemberSQL(ctx context.Context, userID int64) ([]entity.Workspace, error) {
+	workspaces := []entity.Workspace{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get workspace list of member %v", userID))
+
+	rows, err := w.db.Follower().Query(ctx, "rMemberWorkspace", readMemberWorkspace, userID)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return workspaces, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		workspace := entity.Workspace{}
+		err := rows.StructScan(&workspace)
+		if err != nil {
+			return workspaces, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		workspaces = append(workspaces, workspace)
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success get workspace list of member %v", userID))
+
+	return workspaces, nil
+}
+
+func (w *workspace) createMemberSQL(ctx context.Context, inputParam entity.WorkspaceMemberInputParam) (entity.WorkspaceMember, error) {
+	member := entity.WorkspaceMember{}
+
+	w.log.Debug(ctx, fmt.Sprintf("create workspace member with body: %v", inputParam))
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWorkspaceMember", sql.TxOptions{})
+	if err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NamedExec("iNewWorkspaceMember", insertWorkspaceMember, inputParam)
+	if err != nil && strings.Contains(err.Error(), entity.DuplicateEntryErrMessage) {
+		return member, errors.NewWithCode(codes.CodeSQLUniqueConstraint, err.Error())
+	} else if err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	}
+
+	err = w.createMemberEventTx(ctx, tx, entity.EventMemberJoined, entity.MemberEventData{
+		WorkspaceID: inputParam.WorkspaceID,
+		UserID:      inputParam.UserID,
+		Role:        inputParam.MemberRole,
+	}, inputParam.CreatedAt.Time)
+	if err != nil {
+		return member, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success create workspace member with body: %v", inputParam))
+
+	member = entity.WorkspaceMember{
+		ID:          lastID,
+		WorkspaceID: inputParam.WorkspaceID,
+		UserID:      inputParam.UserID,
+		MemberRole:  inputParam.MemberRole,
+		Status:      1,
+		CreatedAt:   inputParam.CreatedAt,
+		CreatedBy:   inputParam.CreatedBy,
+	}
+
+	return member, nil
+}
+
+func (w *workspace) getMemberSQL(ctx context.Context, param entity.WorkspaceMemberParam) (entity.WorkspaceMember, error) {
+	member := entity.WorkspaceMember{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get workspace member with body: %v", param))
+
+	param.QueryOption.DisableLimit = true
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, _, _, err := qb.Build(&param)
+	if err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	row, err := w.db.Follower().QueryRow(ctx, "rWorkspaceMember", readWorkspaceMember+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return member, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	if err := row.StructScan(&member); err != nil && errors.Is(err, sql.ErrNotFound) {
+		return member, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, err.Error())
+	} else if err != nil {
+		return member, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success get workspace member with body: %v", param))
+
+	return member, nil
+}
+
+func (w *workspace) getMemberListSQL(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, *entity.Pagination, error) {
+	members := []entity.WorkspaceMember{}
+
+	w.log.Debug(ctx, fmt.Sprintf("get workspace member list with body: %v", param))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &param.QueryOption)
+	queryExt, queryArgs, countExt, countArgs, err := qb.Build(&param)
+	if err != nil {
+		return members, nil, errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	rows, err := w.db.Follower().Query(ctx, "rWorkspaceMemberList", readWorkspaceMember+queryExt, queryArgs...)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return members, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		member := entity.WorkspaceMember{}
+		err := rows.StructScan(&member)
+		if err != nil {
+			return members, nil, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		members = append(members, member)
+	}
+
+	pg := entity.Pagination{
+		CurrentPage:     param.PaginationParam.Page,
+		CurrentElements: int64(len(members)),
+		SortBy:          param.SortBy,
+	}
+
+	if !param.QueryOption.DisableLimit && len(members) > 0 && param.IncludePagination {
+		err := w.db.Follower().Get(ctx, "cWorkspaceMemberList", countWorkspaceMember+countExt, &pg.TotalElements, countArgs...)
+		if err != nil {
+			return members, nil, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+		}
+	}
+
+	pg.ProcessPagination(param.Limit)
+
+	w.log.Debug(ctx, fmt.Sprintf("success get workspace member list with body: %v", param))
+
+	return members, &pg, nil
+}
+
+func (w *workspace) updateMemberSQL(ctx context.Context, updateParam entity.WorkspaceMemberUpdateParam, selectParam entity.WorkspaceMemberParam) error {
+	w.log.Debug(ctx, fmt.Sprintf("update workspace member %v with body: %v", selectParam.ID, updateParam))
+
+	qb := query.NewSQLQueryBuilder("param", "db", &selectParam.QueryOption)
+	queryUpdate, args, err := qb.BuildUpdate(&updateParam, &selectParam)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLBuilder, err.Error())
+	}
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWorkspaceMember", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uWorkspaceMember", updateWorkspaceMember+queryUpdate, args...)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no workspace member updated")
+	}
+
+	// restoring or soft deleting a member is a membership change
+	if updateParam.Status.Valid {
+		eventType, at := entity.EventMemberJoined, updateParam.UpdatedAt.Time
+		if updateParam.Status.Int64 == 0 {
+			eventType, at = entity.EventMemberLeft, updateParam.DeletedAt.Time
+		}
+
+		err = w.createMemberEventTx(ctx, tx, eventType, entity.MemberEventData{
+			WorkspaceID: selectParam.WorkspaceID,
+			UserID:      selectParam.UserID,
+			Role:        updateParam.MemberRole.String,
+		}, at)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success update workspace member %v with body: %v", selectParam.ID, updateParam))
+
+	return nil
+}
+
+func (w *workspace) restoreMemberSQL(ctx context.Context, member entity.WorkspaceMember, restoredAt time.Time, restoredBy string) error {
+	w.log.Debug(ctx, fmt.Sprintf("restore workspace member %v", member.ID))
+
+	tx, err := w.db.Leader().BeginTx(ctx, "txWorkspaceMember", sql.TxOptions{})
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxBegin, err.Error())
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("uRestoreWorkspaceMember", restoreWorkspaceMember, member.MemberRole, restoredAt, restoredBy, member.ID)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	rowCount, err := res.RowsAffected()
+	if err != nil {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, err.Error())
+	} else if rowCount < 1 {
+		return errors.NewWithCode(codes.CodeSQLNoRowsAffected, "no workspace member restored")
+	}
+
+	err = w.createMemberEventTx(ctx, tx, entity.EventMemberJoined, entity.MemberEventData{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Role:        member.MemberRole,
+	}, restoredAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewWithCode(codes.CodeSQLTxCommit, err.Error())
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success restore workspace member %v", member.ID))
+
+	return nil
+}
+
+func (w *workspace) eraseMembersTx(ctx context.Context, tx sql.CommandTx, userID int64, erasedAt time.Time, erasedBy string) ([]int64, error) {
+	workspaceIDs := []int64{}
+
+	w.log.Debug(ctx, fmt.Sprintf("erase workspace memberships of user %v", userID))
+
+	rows, err := tx.Query("rUserWorkspaceMember", readUserWorkspaceMemberForUpdate, userID)
+	if err != nil && !errors.Is(err, sql.ErrNotFound) {
+		return workspaceIDs, errors.NewWithCode(codes.CodeSQLRead, err.Error())
+	}
+
+	members := []entity.WorkspaceMember{}
+	for rows.Next() {
+		member := entity.WorkspaceMember{}
+		err := rows.StructScan(&member)
+		if err != nil {
+			rows.Close()
+			return workspaceIDs, errors.NewWithCode(codes.CodeSQLRowScan, err.Error())
+		}
+
+		members = append(members, member)
+	}
+	rows.Close()
+
+	if len(members) < 1 {
+		return workspaceIDs, nil
+	}
+
+	_, err = tx.Exec("uEraseUserWorkspaceMember", eraseUserWorkspaceMember, erasedAt, erasedBy, erasedAt, erasedBy, userID)
+	if err != nil {
+		return workspaceIDs, errors.NewWithCode(codes.CodeSQLTxExec, err.Error())
+	}
+
+	// the other members see the erased user leave like any other member
+	for _, member := range members {
+		err = w.createMemberEventTx(ctx, tx, entity.EventMemberLeft, entity.MemberEventData{
+			WorkspaceID: member.WorkspaceID,
+			UserID:      member.UserID,
+			Role:        member.MemberRole,
+		}, erasedAt)
+		if err != nil {
+			return workspaceIDs, err
+		}
+
+		workspaceIDs = append(workspaceIDs, member.WorkspaceID)
+	}
+
+	w.log.Debug(ctx, fmt.Sprintf("success erase workspace memberships of user %v", userID))
+
+	return workspaceIDs, nil
+}
+
+// createMemberEventTx records a membership change for the workspace, a member who left is told
+// separately since they no longer receive workspace events
+func (w *workspace) createMemberEventTx(ctx context.Context, tx sql.CommandTx, eventType string, data entity.MemberEventData, createdAt time.Time) error {
+	scopes := []entity.EventScope{{Type: entity.WebhookScopeWorkspace, ID: data.WorkspaceID}}
+	if eventType == entity.EventMemberLeft {
+		scopes = append(scopes, entity.EventScope{Type: entity.EventScopeUser, ID: data.UserID})
+	}
+
+	for _, scope := range scopes {
+		err := w.outbox.CreateTx(ctx, tx, entity.Event{
+			ID:        uuid.New().String(),
+			Type:      eventType,
+			ScopeType: scope.Type,
+			ScopeID:   scope.ID,
+			ActorID:   data.UserID,
+			Data:      data,
+			CreatedAt: createdAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	AuditActionRegister     = "auth.register"
+	AuditActionLogin        = "auth.login"
+	AuditActionLoginFailed  = "auth.login_failed"
+	AuditActionTokenRefresh = "auth.token_refresh"
+	AuditActionRoleChange   = "user.role_change"
+	AuditActionDelete       = "user.delete"
+	AuditActionDataExport   = "user.export"
+	AuditActionAuditQuery   = "admin.audit_query"
+	AuditActionRetention    = "workspace.retention_change"
+	AuditActionPurge        = "retention.purge"
+	AuditActionLegalHold    = "admin.legal_hold"
+	AuditActionHoldRelease  = "admin.legal_hold_release"
+	AuditActionExport       = "admin.export"
+	AuditActionReport       = "user.report"
+	AuditActionCaseAssign   = "admin.case_assign"
+	AuditActionCaseResolve  = "admin.case_resolve"
+	AuditActionSuspend      = "admin.user_suspend"
+	AuditActionUnsuspend    = "admin.user_unsuspend"
+
+	AuditTargetUser       = "user"
+	AuditTargetAudit      = "audit"
+	AuditTargetWorkspace  = "workspace"
+	AuditTargetReportCase = "report_case"
+)
+
+type Audit struct {
+	ID         int64       `db:"id" json:"id"`
+	ActorID    null.Int64  `db:"fk_actor_id" json:"actorID" swaggertype:"integer"`
+	Action     string      `db:"action" json:"action"`
+	TargetType string      `db:"target_type" json:"targetType"`
+	TargetID   null.Int64  `db:"target_id" json:"targetID" swaggertype:"integer"`
+	RequestID  string      `db:"request_id" json:"requestID"`
+	IPAddress  string      `db:"ip_address" json:"ipAddress"`
+	UserAgent  string      `db:"user_agent" json:"userAgent"`
+	Before     null.String `db:"before_value" json:"before,omitempty" swaggertype:"string"`
+	After      null.String `db:"after_value" json:"after,omitempty" swaggertype:"string"`
+	Status     int64       `db:"status" json:"status"`
+	CreatedAt  null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
+
+type AuditInputParam struct {
+	ActorID     null.Int64  `db:"fk_actor_id"`
+	Action      string      `db:"action"`
+	TargetType  string      `db:"target_type"`
+	TargetID    null.Int64  `db:"target_id"`
+	RequestID   string      `db:"request_id"`
+	IPAddress   string      `db:"ip_address"`
+	UserAgent   string      `db:"user_agent"`
+	Before      null.String `db:"before_value"`
+	After       null.String `db:"after_value"`
+	CreatedAt   null.Time   `db:"created_at"`
+	BeforeValue interface{} `db:"-"`
+	AfterValue  interface{} `db:"-"`
+	// OmitClient leaves out the ip address and user agent of the request, e.g. when its user is being erased
+	OmitClient bool `db:"-"`
+}
+
+type AuditParam struct {
+	ID         int64  `db:"id" param:"id"`
+	ActorID    int64  `db:"fk_actor_id" form:"actorID" param:"fk_actor_id"`
+	Action     string `db:"action" form:"action" param:"action"`
+	TargetType string `db:"target_type" form:"targetType" param:"target_type"`
+	TargetID   int64  `db:"target_id" form:"targetID" param:"target_id"`
+	RequestID  string `db:"request_id" form:"requestID" param:"request_id"`
+	PaginationParam
+	QueryOption query.Option
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	ContactStatusPending   = "pending"
+	ContactStatusAccepted  = "accepted"
+	ContactStatusDeclined  = "declined"
+	ContactStatusCancelled = "cancelled"
+
+	ContactDirectionIncoming = "incoming"
+	ContactDirectionOutgoing = "outgoing"
+)
+
+// Contact is a friend request from UserID to ContactID, both users are contacts once it is accepted
+type Contact struct {
+	ID            int64       `db:"id" json:"id"`
+	UserID        int64       `db:"fk_user_id" json:"userID"`
+	ContactID     int64       `db:"fk_contact_id" json:"contactID"`
+	ContactStatus string      `db:"contact_status" json:"contactStatus"`
+	RespondedAt   null.Time   `db:"responded_at" json:"respondedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Status        int64       `db:"status" json:"status"`
+	Flag          int64       `db:"flag" json:"flag,omitempty"`
+	Meta          null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt     null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy     null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt     null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy     null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt     null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy     null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type ContactInputParam struct {
+	UserID        int64       `db:"fk_user_id" json:"-"`
+	ContactID     int64       `db:"fk_contact_id" json:"userID"`
+	ContactStatus string      `db:"contact_status" json:"-"`
+	CreatedAt     null.Time   `db:"created_at" json:"-"`
+	CreatedBy     null.String `db:"created_by" json:"-"`
+}
+
+type ContactUpdateParam struct {
+	ContactStatus null.String `db:"contact_status" json:"-"`
+	RespondedAt   null.Time   `db:"responded_at" json:"-"`
+	UpdatedAt     null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy     null.String `db:"updated_by" json:"-"`
+}
+
+type ContactParam struct {
+	ID            int64  `db:"id" uri:"contact_id" param:"id"`
+	UserID        int64  `db:"fk_user_id" param:"fk_user_id"`
+	ContactID     int64  `db:"fk_contact_id" param:"fk_contact_id"`
+	ContactStatus string `db:"contact_status" param:"contact_status"`
+	Direction     string `db:"-" form:"direction" param:"-"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
+
+type ContactUser struct {
+	ID         int64     `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name"`
+	IsOnline   bool      `db:"-" json:"isOnline"`
+	LastSeenAt null.Time `db:"-" json:"lastSeenAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Since      null.Time `db:"since" json:"since" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
+
+// ContactUserParam pages through the accepted contacts of UserID, users in ExcludeUserIDs are left out
+type ContactUserParam struct {
+	UserID         int64   `json:"-"`
+	ExcludeUserIDs []int64 `json:"-"`
+	PaginationParam
+}
+
+type Presence struct {
+	UserID     int64     `json:"userID"`
+	IsOnline   bool      `json:"isOnline"`
+	LastSeenAt null.Time `json:"lastSeenAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+// DeviceKey is the public identity and signed prekey a device publishes for end-to-end encryption.
+// The server only stores public keys, private keys never leave the device
+type DeviceKey struct {
+	ID                    int64       `db:"id" json:"id"`
+	UserID                int64       `db:"fk_user_id" json:"userID"`
+	DeviceID              int64       `db:"fk_device_id" json:"deviceID"`
+	IdentityKey           string      `db:"identity_key" json:"identityKey"`
+	SignedPrekeyID        int64       `db:"signed_prekey_id" json:"signedPrekeyID"`
+	SignedPrekey          string      `db:"signed_prekey" json:"signedPrekey"`
+	SignedPrekeySignature string      `db:"signed_prekey_signature" json:"signedPrekeySignature"`
+	RemainingPrekeys      int64       `db:"-" json:"remainingPrekeys"`
+	Status                int64       `db:"status" json:"status"`
+	Flag                  int64       `db:"flag" json:"flag,omitempty"`
+	Meta                  null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt             null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy             null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt             null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy             null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt             null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy             null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type DeviceKeyInputParam struct {
+	UserID                int64                     `db:"fk_user_id" json:"-"`
+	DeviceID              int64                     `db:"fk_device_id" uri:"device_id" json:"-"`
+	IdentityKey           string                    `db:"identity_key" json:"identityKey"`
+	SignedPrekeyID        int64                     `db:"signed_prekey_id" json:"signedPrekeyID"`
+	SignedPrekey          string                    `db:"signed_prekey" json:"signedPrekey"`
+	SignedPrekeySignature string                    `db:"signed_prekey_signature" json:"signedPrekeySignature"`
+	OneTimePrekeys        []OneTimePrekeyInputParam `db:"-" json:"oneTimePrekeys"`
+	// ResetPrekeys drops the unclaimed one-time prekeys, they were made for a replaced identity key
+	ResetPrekeys bool        `db:"-" json:"-"`
+	CreatedAt    null.Time   `db:"created_at" json:"-"`
+	CreatedBy    null.String `db:"created_by" json:"-"`
+}
+
+type DeviceKeyParam struct {
+	ID       int64 `db:"id" param:"id"`
+	UserID   int64 `db:"fk_user_id" param:"fk_user_id"`
+	DeviceID int64 `db:"fk_device_id" uri:"device_id" param:"fk_device_id"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+// OneTimePrekey is handed out to a single peer starting a session, then it is never served again
+type OneTimePrekey struct {
+	ID        int64       `db:"id" json:"-"`
+	UserID    int64       `db:"fk_user_id" json:"-"`
+	DeviceID  int64       `db:"fk_device_id" json:"-"`
+	KeyID     int64       `db:"key_id" json:"keyID"`
+	PublicKey string      `db:"public_key" json:"publicKey"`
+	ClaimedBy null.Int64  `db:"fk_claimed_by" json:"-"`
+	ClaimedAt null.Time   `db:"claimed_at" json:"-"`
+	Status    int64       `db:"status" json:"-"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type OneTimePrekeyInputParam struct {
+	UserID    int64       `db:"fk_user_id" json:"-"`
+	DeviceID  int64       `db:"fk_device_id" json:"-"`
+	KeyID     int64       `db:"key_id" json:"keyID"`
+	PublicKey string      `db:"public_key" json:"publicKey"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type OneTimePrekeyClaimParam struct {
+	DeviceID  int64
+	ClaimedBy int64
+	ClaimedAt null.Time
+}
+
+// KeyBundle is everything a peer needs to start an encrypted session with one device
+type KeyBundle struct {
+	UserID                int64          `json:"userID"`
+	DeviceID              int64          `json:"deviceID"`
+	IdentityKey           string         `json:"identityKey"`
+	SignedPrekeyID        int64          `json:"signedPrekeyID"`
+	SignedPrekey          string         `json:"signedPrekey"`
+	SignedPrekeySignature string         `json:"signedPrekeySignature"`
+	OneTimePrekey         *OneTimePrekey `json:"oneTimePrekey,omitempty"`
+}
+
+type KeyBundleParam struct {
+	UserID int64 `uri:"user_id" json:"-"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+// Digest is the data rendered into the email digest template
+type Digest struct {
+	Subject        string
+	Name           string
+	UnreadCount    int
+	MentionCount   int
+	Scopes         []DigestScope
+	UnsubscribeURL string
+}
+
+// DigestScope groups the unread notifications of a single conversation or workspace
+type DigestScope struct {
+	ScopeType    string
+	ScopeID      int64
+	Title        string
+	UnreadCount  int
+	MentionCount int
+	Items        []Notification
+}
+
+type DigestUnsubscribeParam struct {
+	Token string `form:"token"`
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+const (
+	EventScopeUser = "user"
+
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+	// EventUserSuspended is streamed to the suspended user, their open streams close on it
+	EventUserSuspended = "user.suspended"
+
+	EventMemberJoined = "member.joined"
+	EventMemberLeft   = "member.left"
+
+	// real-time only events, they are not offered to webhooks
+	EventNotificationCreated = "notification.created"
+	EventNotificationRead    = "notification.read"
+	EventStreamReset         = "stream.reset"
+)
+
+// EventTypes lists the events external subscribers can listen to
+var EventTypes = []string{
+	EventMemberJoined,
+	EventMemberLeft,
+}
+
+type Event struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ScopeType string `json:"scopeType"`
+	ScopeID   int64  `json:"scopeID"`
+	// ActorID is the user the event is about, it is hidden from users on either side of a block with them
+	ActorID   int64       `json:"actorID,omitempty"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// StreamEvent is an event stored in the per-user real-time log, ID is the position in the log
+// and is what clients send back as Last-Event-ID
+type StreamEvent struct {
+	ID    string
+	Event Event
+}
+
+// EventScope identifies the subject events are recorded against, e.g. a user or a workspace
+type EventScope struct {
+	Type string
+	ID   int64
+}
+
+type MemberEventData struct {
+	WorkspaceID int64  `json:"workspaceID"`
+	UserID      int64  `json:"userID"`
+	Role        string `json:"role,omitempty"`
+}
+
+type NotificationEventData struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"userID"`
+}
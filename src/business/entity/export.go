@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"io"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	ExportSubjectUser = "user"
+
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
+// Export is a ZIP archive of everything stored about a subject within a date range, built by a background job
+type Export struct {
+	ID           int64       `db:"id" json:"id"`
+	RequestedBy  int64       `db:"fk_requested_by" json:"requestedBy"`
+	SubjectType  string      `db:"subject_type" json:"subjectType"`
+	SubjectID    int64       `db:"subject_id" json:"subjectID"`
+	RangeStart   null.Time   `db:"range_start" json:"rangeStart" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	RangeEnd     null.Time   `db:"range_end" json:"rangeEnd" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	ExportStatus string      `db:"export_status" json:"exportStatus"`
+	FileName     null.String `db:"file_name" json:"-"`
+	FileSize     null.Int64  `db:"file_size" json:"-"`
+	ErrorMessage null.String `db:"error_message" json:"errorMessage,omitempty" swaggertype:"string"`
+	StartedAt    null.Time   `db:"started_at" json:"startedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CompletedAt  null.Time   `db:"completed_at" json:"completedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DownloadURL  string      `db:"-" json:"downloadURL,omitempty"`
+	Status       int64       `db:"status" json:"status"`
+	Flag         int64       `db:"flag" json:"flag,omitempty"`
+	Meta         null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt    null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy    null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt    null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy    null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt    null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy    null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type ExportInputParam struct {
+	RequestedBy int64       `db:"fk_requested_by" json:"-"`
+	SubjectType string      `db:"subject_type" json:"subjectType"`
+	SubjectID   int64       `db:"subject_id" json:"subjectID"`
+	RangeStart  time.Time   `db:"range_start" json:"rangeStart"`
+	RangeEnd    time.Time   `db:"range_end" json:"rangeEnd"`
+	CreatedAt   null.Time   `db:"created_at" json:"-"`
+	CreatedBy   null.String `db:"created_by" json:"-"`
+}
+
+type ExportUpdateParam struct {
+	ExportStatus null.String `db:"export_status" json:"-"`
+	FileName     null.String `db:"file_name" json:"-"`
+	FileSize     null.Int64  `db:"file_size" json:"-"`
+	ErrorMessage null.String `db:"error_message" json:"-"`
+	CompletedAt  null.Time   `db:"completed_at" json:"-"`
+	UpdatedAt    null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy    null.String `db:"updated_by" json:"-"`
+}
+
+type ExportParam struct {
+	ID           int64  `db:"id" uri:"export_id" param:"id"`
+	RequestedBy  int64  `db:"fk_requested_by" param:"fk_requested_by"`
+	SubjectType  string `db:"subject_type" form:"subjectType" param:"subject_type"`
+	SubjectID    int64  `db:"subject_id" form:"subjectID" param:"subject_id"`
+	ExportStatus string `db:"export_status" form:"exportStatus" param:"export_status"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+// ExportRecordParam pages through the rows of a subject created within the export range
+type ExportRecordParam struct {
+	SubjectID  int64
+	RangeStart time.Time
+	RangeEnd   time.Time
+	AfterID    int64
+	Limit      int
+}
+
+type ExportDownloadParam struct {
+	Token string `form:"token"`
+}
+
+// ExportChunk is a part of a finished archive, chunks are read back in index order
+type ExportChunk struct {
+	ExportID   int64     `db:"fk_export_id"`
+	ChunkIndex int64     `db:"chunk_index"`
+	Content    []byte    `db:"content"`
+	CreatedAt  null.Time `db:"created_at"`
+}
+
+// ExportFile is the archive of a finished export, Content streams its chunks
+type ExportFile struct {
+	Name    string
+	Size    int64
+	Content io.Reader
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// IdempotencyParam identifies a request by the client key and the route it was sent to
+type IdempotencyParam struct {
+	Key    string
+	Method string
+	Path   string
+	// Fingerprint is the hash of the request body, a key reused with another body is rejected
+	Fingerprint string
+}
+
+// IdempotentResponse is the stored response replayed to retries of the same request
+type IdempotentResponse struct {
+	Fingerprint string    `json:"fingerprint"`
+	StatusCode  int       `json:"statusCode"`
+	ContentType string    `json:"contentType"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
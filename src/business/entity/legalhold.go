@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	LegalHoldScopeUser      = "user"
+	LegalHoldScopeWorkspace = "workspace"
+)
+
+// LegalHold suspends retention purges and user initiated deletes of everything in its scope until released
+type LegalHold struct {
+	ID        int64       `db:"id" json:"id"`
+	ScopeType string      `db:"scope_type" json:"scopeType"`
+	ScopeID   int64       `db:"scope_id" json:"scopeID"`
+	Reason    string      `db:"reason" json:"reason"`
+	Status    int64       `db:"status" json:"status"`
+	Flag      int64       `db:"flag" json:"flag,omitempty"`
+	Meta      null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type LegalHoldInputParam struct {
+	ScopeType string      `db:"scope_type" json:"scopeType"`
+	ScopeID   int64       `db:"scope_id" json:"scopeID"`
+	Reason    string      `db:"reason" json:"reason"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type LegalHoldUpdateParam struct {
+	Status    null.Int64  `db:"status" json:"-"`
+	UpdatedAt null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy null.String `db:"updated_by" json:"-"`
+	DeletedAt null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy null.String `db:"deleted_by" json:"-"`
+}
+
+type LegalHoldParam struct {
+	ID        int64  `db:"id" uri:"legal_hold_id" param:"id"`
+	ScopeType string `db:"scope_type" form:"scopeType" param:"scope_type"`
+	ScopeID   int64  `db:"scope_id" form:"scopeID" param:"scope_id"`
+	PaginationParam
+	QueryOption query.Option
+}
@@ -0,0 +1,170 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	NotificationTypeMessage = "message"
+	NotificationTypeMention = "mention"
+	NotificationTypeWarning = "warning"
+	NotificationTypeMember  = "member"
+
+	NotificationReadStatusUnread = "unread"
+	NotificationReadStatusRead   = "read"
+
+	DevicePlatformAndroid = "android"
+	DevicePlatformIOS     = "ios"
+	DevicePlatformWeb     = "web"
+
+	NotificationJobStatusPending = "pending"
+	NotificationJobStatusDone    = "done"
+	NotificationJobStatusFailed  = "failed"
+)
+
+type Notification struct {
+	ID               int64       `db:"id" json:"id"`
+	UserID           int64       `db:"fk_user_id" json:"userID"`
+	ActorID          null.Int64  `db:"fk_actor_id" json:"actorID" swaggertype:"integer"`
+	NotificationType string      `db:"notification_type" json:"type"`
+	ScopeType        string      `db:"scope_type" json:"scopeType"`
+	ScopeID          int64       `db:"scope_id" json:"scopeID"`
+	Title            string      `db:"title" json:"title"`
+	Body             string      `db:"body" json:"body"`
+	ReadStatus       string      `db:"read_status" json:"readStatus"`
+	ReadAt           null.Time   `db:"read_at" json:"readAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Status           int64       `db:"status" json:"status"`
+	Flag             int64       `db:"flag" json:"flag,omitempty"`
+	Meta             null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt        null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy        null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt        null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy        null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt        null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy        null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type NotificationInputParam struct {
+	UserID           int64       `db:"fk_user_id"`
+	ActorID          null.Int64  `db:"fk_actor_id"`
+	EventID          null.String `db:"event_id"`
+	NotificationType string      `db:"notification_type"`
+	ScopeType        string      `db:"scope_type"`
+	ScopeID          int64       `db:"scope_id"`
+	Title            string      `db:"title"`
+	Body             string      `db:"body"`
+	ReadStatus       string      `db:"read_status"`
+	CreatedAt        null.Time   `db:"created_at"`
+	CreatedBy        null.String `db:"created_by"`
+}
+
+type NotificationUpdateParam struct {
+	ReadStatus null.String `db:"read_status" json:"-"`
+	ReadAt     null.Time   `db:"read_at" json:"-"`
+	UpdatedAt  null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy  null.String `db:"updated_by" json:"-"`
+}
+
+type NotificationParam struct {
+	ID         int64  `db:"id" uri:"notification_id" param:"id"`
+	UserID     int64  `db:"fk_user_id" param:"fk_user_id"`
+	ReadStatus string `db:"read_status" form:"readStatus" param:"read_status"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+// NotificationRequest describes something that happened in a scope, the notification usecase
+// decides which of the recipients should actually be notified
+type NotificationRequest struct {
+	// EventID is the outbox event the request came from, a recipient is notified once per event
+	EventID          string
+	ActorID          int64
+	NotificationType string
+	ScopeType        string
+	ScopeID          int64
+	Title            string
+	Body             string
+	RecipientIDs     []int64
+	MentionedIDs     []int64
+}
+
+// NotificationJob is an outbox event queued for fan-out, it is processed outside the relay
+// so the recipients and their pushes do not hold up the event bus
+type NotificationJob struct {
+	ID            int64       `db:"id" json:"id"`
+	EventID       string      `db:"event_id" json:"eventID"`
+	EventType     string      `db:"event_type" json:"eventType"`
+	Payload       string      `db:"payload" json:"-"`
+	Attempt       int64       `db:"attempt" json:"attempt"`
+	JobStatus     string      `db:"job_status" json:"jobStatus"`
+	NextAttemptAt null.Time   `db:"next_attempt_at" json:"nextAttemptAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	ErrorMessage  null.String `db:"error_message" json:"errorMessage,omitempty" swaggertype:"string"`
+	Status        int64       `db:"status" json:"status"`
+	CreatedAt     null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedAt     null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
+
+type NotificationJobInputParam struct {
+	EventID       string    `db:"event_id"`
+	EventType     string    `db:"event_type"`
+	Payload       string    `db:"payload"`
+	JobStatus     string    `db:"job_status"`
+	NextAttemptAt null.Time `db:"next_attempt_at"`
+	CreatedAt     null.Time `db:"created_at"`
+}
+
+type NotificationJobUpdateParam struct {
+	Attempt       null.Int64  `db:"attempt"`
+	JobStatus     null.String `db:"job_status"`
+	NextAttemptAt null.Time   `db:"next_attempt_at"`
+	ErrorMessage  null.String `db:"error_message"`
+	UpdatedAt     null.Time   `db:"updated_at"`
+}
+
+type NotificationJobParam struct {
+	ID          int64 `db:"id" param:"id"`
+	QueryOption query.Option
+}
+
+type Device struct {
+	ID        int64       `db:"id" json:"id"`
+	UserID    int64       `db:"fk_user_id" json:"userID"`
+	Platform  string      `db:"platform" json:"platform"`
+	Token     string      `db:"token" json:"-"`
+	Status    int64       `db:"status" json:"status"`
+	Flag      int64       `db:"flag" json:"flag,omitempty"`
+	Meta      null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type DeviceInputParam struct {
+	UserID    int64       `db:"fk_user_id" json:"-"`
+	Platform  string      `db:"platform" json:"platform"`
+	Token     string      `db:"token" json:"token"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type DeviceUpdateParam struct {
+	UserID    null.Int64  `db:"fk_user_id" json:"-"`
+	Platform  null.String `db:"platform" json:"-"`
+	Status    null.Int64  `db:"status" json:"-"`
+	UpdatedAt null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy null.String `db:"updated_by" json:"-"`
+	DeletedAt null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy null.String `db:"deleted_by" json:"-"`
+}
+
+type DeviceParam struct {
+	ID     int64  `db:"id" uri:"device_id" param:"id"`
+	UserID int64  `db:"fk_user_id" param:"fk_user_id"`
+	Token  string `db:"token" param:"token"`
+	PaginationParam
+	QueryOption query.Option
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+)
+
+type Outbox struct {
+	ID        int64  `db:"id" json:"id"`
+	EventID   string `db:"event_id" json:"eventID"`
+	EventType string `db:"event_type" json:"eventType"`
+	ScopeType string `db:"scope_type" json:"scopeType"`
+	ScopeID   int64  `db:"scope_id" json:"scopeID"`
+	ActorID   int64  `db:"actor_id" json:"actorID"`
+	// Seq orders rows by the commit of the relay that sequenced them, it is invalid until then
+	Seq         null.Int64 `db:"seq" json:"seq" swaggertype:"integer"`
+	Payload     string     `db:"payload" json:"payload"`
+	PublishedAt null.Time  `db:"published_at" json:"publishedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	// Attempts counts failed publishes, a row that fails too often is dead lettered and no longer relayed
+	Attempts      int64       `db:"attempts" json:"attempts"`
+	LastError     null.String `db:"last_error" json:"lastError,omitempty" swaggertype:"string"`
+	NextAttemptAt null.Time   `db:"next_attempt_at" json:"nextAttemptAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeadAt        null.Time   `db:"dead_at" json:"deadAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Status        int64       `db:"status" json:"status"`
+	CreatedAt     null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
+
+type OutboxInputParam struct {
+	EventID   string    `db:"event_id"`
+	EventType string    `db:"event_type"`
+	ScopeType string    `db:"scope_type"`
+	ScopeID   int64     `db:"scope_id"`
+	ActorID   int64     `db:"actor_id"`
+	Payload   string    `db:"payload"`
+	CreatedAt null.Time `db:"created_at"`
+}
+
+// OutboxPublishParam bounds one relay run, a row failing MaxAttempt times is dead lettered and
+// every failure waits twice as long as the previous one before the row is tried again
+type OutboxPublishParam struct {
+	Limit           int
+	MaxAttempt      int64
+	BackoffInterval time.Duration
+}
+
+// OutboxEventParam selects the sequenced events recorded against any of the scopes after AfterSeq
+type OutboxEventParam struct {
+	AfterSeq int64
+	Scopes   []EventScope
+	Limit    int64
+}
+
+func (o Outbox) ToEvent() Event {
+	return Event{
+		ID:        o.EventID,
+		Type:      o.EventType,
+		ScopeType: o.ScopeType,
+		ScopeID:   o.ScopeID,
+		ActorID:   o.ActorID,
+		Data:      json.RawMessage(o.Payload),
+		CreatedAt: o.CreatedAt.Time,
+	}
+}
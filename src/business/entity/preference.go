@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	NotificationLevelAll      = "all"
+	NotificationLevelMentions = "mentions"
+	NotificationLevelNone     = "none"
+
+	ScopePreferenceTypeWorkspace = "workspace"
+
+	DefaultTimeZone = "UTC"
+
+	// PreferenceClockLayout is the format of quiet hours
+	PreferenceClockLayout = "15:04"
+)
+
+// Preference holds the global notification settings of a user, quiet hours are HH:MM in TimeZone
+// and may wrap around midnight
+type Preference struct {
+	ID              int64       `db:"id" json:"id"`
+	UserID          int64       `db:"fk_user_id" json:"userID"`
+	DoNotDisturb    bool        `db:"do_not_disturb" json:"doNotDisturb"`
+	QuietHoursStart string      `db:"quiet_hours_start" json:"quietHoursStart"`
+	QuietHoursEnd   string      `db:"quiet_hours_end" json:"quietHoursEnd"`
+	TimeZone        string      `db:"time_zone" json:"timeZone"`
+	EmailDigest     bool        `db:"email_digest" json:"emailDigest"`
+	Status          int64       `db:"status" json:"status"`
+	Flag            int64       `db:"flag" json:"flag,omitempty"`
+	Meta            null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt       null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy       null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt       null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy       null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+}
+
+type PreferenceInputParam struct {
+	UserID          int64       `db:"fk_user_id" json:"-"`
+	DoNotDisturb    bool        `db:"do_not_disturb" json:"doNotDisturb"`
+	QuietHoursStart string      `db:"quiet_hours_start" json:"quietHoursStart"`
+	QuietHoursEnd   string      `db:"quiet_hours_end" json:"quietHoursEnd"`
+	TimeZone        string      `db:"time_zone" json:"timeZone"`
+	EmailDigest     *bool       `db:"-" json:"emailDigest,omitempty"`
+	CreatedAt       null.Time   `db:"created_at" json:"-"`
+	CreatedBy       null.String `db:"created_by" json:"-"`
+}
+
+// PreferenceDigestInputParam only touches the email digest subscription, so unsubscribing from
+// a digest link does not reset the rest of the preference
+type PreferenceDigestInputParam struct {
+	UserID      int64       `db:"fk_user_id"`
+	EmailDigest bool        `db:"email_digest"`
+	CreatedAt   null.Time   `db:"created_at"`
+	CreatedBy   null.String `db:"created_by"`
+}
+
+type PreferenceParam struct {
+	UserID int64 `db:"fk_user_id" param:"fk_user_id"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
+
+// ScopePreference overrides the notification level for a single workspace
+type ScopePreference struct {
+	ID                int64       `db:"id" json:"id"`
+	UserID            int64       `db:"fk_user_id" json:"userID"`
+	ScopeType         string      `db:"scope_type" json:"scopeType"`
+	ScopeID           int64       `db:"scope_id" json:"scopeID"`
+	NotificationLevel string      `db:"notification_level" json:"level"`
+	Status            int64       `db:"status" json:"status"`
+	Flag              int64       `db:"flag" json:"flag,omitempty"`
+	Meta              null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt         null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy         null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt         null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy         null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+}
+
+type ScopePreferenceInputParam struct {
+	UserID            int64       `db:"fk_user_id" json:"-"`
+	ScopeType         string      `db:"scope_type" json:"scopeType"`
+	ScopeID           int64       `db:"scope_id" json:"scopeID"`
+	NotificationLevel string      `db:"notification_level" json:"level"`
+	CreatedAt         null.Time   `db:"created_at" json:"-"`
+	CreatedBy         null.String `db:"created_by" json:"-"`
+}
+
+type ScopePreferenceParam struct {
+	UserID    int64  `db:"fk_user_id" param:"fk_user_id"`
+	ScopeType string `db:"scope_type" form:"scopeType" param:"scope_type"`
+	ScopeID   int64  `db:"scope_id" form:"scopeID" param:"scope_id"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
+
+// DefaultPreference is what a user without a stored preference gets
+func DefaultPreference(userID int64) Preference {
+	return Preference{UserID: userID, TimeZone: DefaultTimeZone, EmailDigest: true}
+}
+
+// IsPushAllowed reports whether push notifications may be sent at at, do not disturb and quiet hours
+// only silence push, the in-app feed is kept
+func (p Preference) IsPushAllowed(at time.Time) bool {
+	return !p.DoNotDisturb && !p.InQuietHours(at)
+}
+
+// InQuietHours reports whether at falls inside the quiet hours of the preference in the user's time zone,
+// a window whose end is before its start wraps around midnight
+func (p Preference) InQuietHours(at time.Time) bool {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return false
+	}
+
+	loc, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	start, err := time.Parse(PreferenceClockLayout, p.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+
+	end, err := time.Parse(PreferenceClockLayout, p.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return now >= from && now < to
+	}
+
+	return now >= from || now < to
+}
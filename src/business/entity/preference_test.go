@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Preference_InQuietHours(t *testing.T) {
+	type args struct {
+		preference Preference
+		at         time.Time
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "no quiet hours",
+			args: args{
+				preference: Preference{TimeZone: "UTC"},
+				at:         time.Date(2024, 6, 21, 23, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
+		{
+			name: "inside same day window",
+			args: args{
+				preference: Preference{QuietHoursStart: "12:00", QuietHoursEnd: "13:00", TimeZone: "UTC"},
+				at:         time.Date(2024, 6, 21, 12, 30, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name: "end of window is exclusive",
+			args: args{
+				preference: Preference{QuietHoursStart: "12:00", QuietHoursEnd: "13:00", TimeZone: "UTC"},
+				at:         time.Date(2024, 6, 21, 13, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
+		{
+			name: "inside window wrapping midnight",
+			args: args{
+				preference: Preference{QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "UTC"},
+				at:         time.Date(2024, 6, 21, 3, 0, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name: "outside window wrapping midnight",
+			args: args{
+				preference: Preference{QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "UTC"},
+				at:         time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
+		{
+			name: "evaluated in user time zone",
+			args: args{
+				preference: Preference{QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "Asia/Jakarta"},
+				// 16:00 UTC is 23:00 in Jakarta
+				at: time.Date(2024, 6, 21, 16, 0, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.args.preference.InQuietHours(tt.args.at)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	RelationTypeBlock = "block"
+	RelationTypeMute  = "mute"
+
+	RelationTargetUser = "user"
+)
+
+type Relation struct {
+	ID           int64       `db:"id" json:"id"`
+	UserID       int64       `db:"fk_user_id" json:"userID"`
+	RelationType string      `db:"relation_type" json:"relationType"`
+	TargetType   string      `db:"target_type" json:"targetType"`
+	TargetID     int64       `db:"target_id" json:"targetID"`
+	Status       int64       `db:"status" json:"status"`
+	Flag         int64       `db:"flag" json:"flag,omitempty"`
+	Meta         null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt    null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy    null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt    null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy    null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt    null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy    null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type RelationInputParam struct {
+	UserID       int64       `db:"fk_user_id" json:"-"`
+	RelationType string      `db:"relation_type" json:"relationType"`
+	TargetType   string      `db:"target_type" json:"targetType"`
+	TargetID     int64       `db:"target_id" json:"targetID"`
+	CreatedAt    null.Time   `db:"created_at" json:"-"`
+	CreatedBy    null.String `db:"created_by" json:"-"`
+}
+
+type RelationUpdateParam struct {
+	Status    null.Int64  `db:"status" json:"-"`
+	UpdatedAt null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy null.String `db:"updated_by" json:"-"`
+	DeletedAt null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy null.String `db:"deleted_by" json:"-"`
+}
+
+type RelationParam struct {
+	ID           int64  `db:"id" uri:"relation_id" param:"id"`
+	UserID       int64  `db:"fk_user_id" param:"fk_user_id"`
+	RelationType string `db:"relation_type" form:"relationType" param:"relation_type"`
+	TargetType   string `db:"target_type" form:"targetType" param:"target_type"`
+	TargetID     int64  `db:"target_id" form:"targetID" param:"target_id"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	ReportTargetUser = "user"
+
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonHate          = "hate"
+	ReportReasonViolence      = "violence"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonOther         = "other"
+
+	ReportCaseStatusOpen      = "open"
+	ReportCaseStatusInReview  = "in_review"
+	ReportCaseStatusResolved  = "resolved"
+	ReportCaseStatusDismissed = "dismissed"
+
+	ReportActionWarn          = "warn"
+	ReportActionSuspendUser   = "suspend_user"
+	ReportActionDismiss       = "dismiss"
+	ReportActionUnsuspendUser = "unsuspend_user"
+)
+
+// Report is a single complaint from a user, reports about the same target are grouped into one open case
+type Report struct {
+	ID         int64       `db:"id" json:"id"`
+	CaseID     int64       `db:"fk_case_id" json:"caseID"`
+	ReporterID int64       `db:"fk_reporter_id" json:"reporterID"`
+	TargetType string      `db:"target_type" json:"targetType"`
+	TargetID   int64       `db:"target_id" json:"targetID"`
+	Reason     string      `db:"reason" json:"reason"`
+	Details    null.String `db:"details" json:"details,omitempty" swaggertype:"string"`
+	Status     int64       `db:"status" json:"status"`
+	Flag       int64       `db:"flag" json:"flag,omitempty"`
+	Meta       null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt  null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy  null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt  null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy  null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt  null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy  null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type ReportInputParam struct {
+	CaseID     int64       `db:"fk_case_id" json:"-"`
+	ReporterID int64       `db:"fk_reporter_id" json:"-"`
+	TargetType string      `db:"target_type" json:"targetType"`
+	TargetID   int64       `db:"target_id" json:"targetID"`
+	Reason     string      `db:"reason" json:"reason"`
+	Details    null.String `db:"details" json:"details" swaggertype:"string"`
+	CreatedAt  null.Time   `db:"created_at" json:"-"`
+	CreatedBy  null.String `db:"created_by" json:"-"`
+}
+
+type ReportParam struct {
+	ID         int64 `db:"id" param:"id"`
+	CaseID     int64 `db:"fk_case_id" param:"fk_case_id"`
+	ReporterID int64 `db:"fk_reporter_id" param:"fk_reporter_id"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+// ReportCase is what moderators work on, it stays open until it is resolved or dismissed
+type ReportCase struct {
+	ID             int64       `db:"id" json:"id"`
+	TargetType     string      `db:"target_type" json:"targetType"`
+	TargetID       int64       `db:"target_id" json:"targetID"`
+	CaseStatus     string      `db:"case_status" json:"caseStatus"`
+	ReportCount    int64       `db:"report_count" json:"reportCount"`
+	AssigneeID     null.Int64  `db:"fk_assignee_id" json:"assigneeID,omitempty" swaggertype:"integer"`
+	Resolution     null.String `db:"resolution" json:"resolution,omitempty" swaggertype:"string"`
+	ResolutionNote null.String `db:"resolution_note" json:"resolutionNote,omitempty" swaggertype:"string"`
+	ResolvedBy     null.Int64  `db:"fk_resolved_by" json:"resolvedBy,omitempty" swaggertype:"integer"`
+	ResolvedAt     null.Time   `db:"resolved_at" json:"resolvedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Reports        []Report    `db:"-" json:"reports,omitempty"`
+	Status         int64       `db:"status" json:"status"`
+	Flag           int64       `db:"flag" json:"flag,omitempty"`
+	Meta           null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt      null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy      null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt      null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy      null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt      null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy      null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type ReportCaseUpdateParam struct {
+	CaseStatus     null.String `db:"case_status" json:"-"`
+	AssigneeID     null.Int64  `db:"fk_assignee_id" json:"-"`
+	Resolution     null.String `db:"resolution" json:"-"`
+	ResolutionNote null.String `db:"resolution_note" json:"-"`
+	ResolvedBy     null.Int64  `db:"fk_resolved_by" json:"-"`
+	ResolvedAt     null.Time   `db:"resolved_at" json:"-"`
+	UpdatedAt      null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy      null.String `db:"updated_by" json:"-"`
+}
+
+type ReportCaseParam struct {
+	ID         int64  `db:"id" uri:"case_id" param:"id"`
+	TargetType string `db:"target_type" form:"targetType" param:"target_type"`
+	TargetID   int64  `db:"target_id" form:"targetID" param:"target_id"`
+	CaseStatus string `db:"case_status" form:"caseStatus" param:"case_status"`
+	AssigneeID int64  `db:"fk_assignee_id" form:"assigneeID" param:"fk_assignee_id"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+type ReportCaseAssignParam struct {
+	CaseID     int64 `uri:"case_id" json:"-"`
+	AssigneeID int64 `json:"assigneeID"`
+}
+
+type ReportCaseResolveParam struct {
+	CaseID int64  `uri:"case_id" json:"-"`
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+// ReportCaseUnsuspendParam lifts the suspension a resolved case put on its user
+type ReportCaseUnsuspendParam struct {
+	CaseID int64  `uri:"case_id" json:"-"`
+	Note   string `json:"note"`
+}
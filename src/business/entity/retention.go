@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	RetentionScopeWorkspace = "workspace"
+
+	// retention targets are the tables a policy purges
+	RetentionTargetNotification = "notification"
+	RetentionTargetEvent        = "event"
+)
+
+type RetentionPolicy struct {
+	ID            int64       `db:"id" json:"id"`
+	ScopeType     string      `db:"scope_type" json:"scopeType"`
+	ScopeID       int64       `db:"scope_id" json:"scopeID"`
+	RetentionDays int64       `db:"retention_days" json:"retentionDays"`
+	Status        int64       `db:"status" json:"status"`
+	Flag          int64       `db:"flag" json:"flag,omitempty"`
+	Meta          null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt     null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy     null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt     null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy     null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt     null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy     null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type RetentionPolicyInputParam struct {
+	ScopeType     string      `db:"scope_type" json:"-"`
+	ScopeID       int64       `db:"scope_id" json:"-"`
+	RetentionDays int64       `db:"retention_days" json:"retentionDays"`
+	CreatedAt     null.Time   `db:"created_at" json:"-"`
+	CreatedBy     null.String `db:"created_by" json:"-"`
+}
+
+type RetentionPolicyUpdateParam struct {
+	Status    null.Int64  `db:"status" json:"-"`
+	UpdatedAt null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy null.String `db:"updated_by" json:"-"`
+	DeletedAt null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy null.String `db:"deleted_by" json:"-"`
+}
+
+type RetentionPolicyParam struct {
+	ID        int64  `db:"id" param:"id"`
+	ScopeType string `db:"scope_type" param:"scope_type"`
+	ScopeID   int64  `db:"scope_id" param:"scope_id"`
+	PaginationParam
+	QueryOption query.Option
+}
+
+// RetentionPurgeParam selects the rows of one target in a scope that are older than the policy allows
+type RetentionPurgeParam struct {
+	Target    string
+	ScopeType string
+	ScopeID   int64
+	Before    time.Time
+	Limit     int
+}
+
+// RetentionReport is the outcome of applying one policy, in a dry run Purged counts what would be deleted
+type RetentionReport struct {
+	ScopeType     string    `json:"scopeType"`
+	ScopeID       int64     `json:"scopeID"`
+	RetentionDays int64     `json:"retentionDays"`
+	Before        time.Time `json:"before"`
+	DryRun        bool      `json:"dryRun"`
+	// OnHold policies are skipped until the legal hold on their scope is released
+	OnHold bool             `json:"onHold"`
+	Purged map[string]int64 `json:"purged"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+type SyncParam struct {
+	Since string `form:"since"`
+	Limit int64  `form:"limit"`
+}
+
+// Sync is a page of changes, NextToken is passed as since to get the changes that follow
+type Sync struct {
+	Changes   []Event `json:"changes"`
+	NextToken string  `json:"nextToken"`
+	HasMore   bool    `json:"hasMore"`
+}
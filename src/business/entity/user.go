@@ -6,6 +6,16 @@ import (
 	"github.com/reyhanmichiels/go-pkg/query"
 )
 
+const (
+	RoleIDAdmin int64 = 1
+	RoleIDUser  int64 = 2
+
+	// a suspended user keeps their email, so the address can neither sign in nor be registered again
+	// until a moderator lifts the suspension
+	UserStatusActive    int64 = 1
+	UserStatusSuspended int64 = 2
+)
+
 type User struct {
 	ID           int64       `db:"id" json:"id"`
 	RoleID       int64       `db:"fk_role_id" json:"roleID"`
@@ -35,12 +45,19 @@ type UserInputParam struct {
 }
 
 type UserUpdateParam struct {
+	RoleID       int64       `db:"fk_role_id" json:"-"`
 	Name         string      `db:"name" json:"name"`
 	RefreshToken string      `db:"refresh_token" json:"refreshToken"`
 	UpdatedAt    null.Time   `db:"updated_at" json:""`
 	UpdatedBy    null.String `db:"updated_by" json:""`
 }
 
+type UserEventData struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
 type UserParam struct {
 	ID           int64  `db:"id" uri:"user_id" param:"id"`
 	Email        string `db:"email" param:"email"`
@@ -50,6 +67,16 @@ type UserParam struct {
 	BypassCache bool
 }
 
+type UserRoleUpdateParam struct {
+	UserID int64 `uri:"user_id" json:"-"`
+	RoleID int64 `json:"roleID"`
+}
+
+// UserDeleteParam confirms the password before the account is erased
+type UserDeleteParam struct {
+	Password string `json:"password"`
+}
+
 type UserLoginParam struct {
 	Email    string `db:"email" json:"email"`
 	Password string `db:"password" json:"password"`
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	WebhookScopeWorkspace = "workspace"
+
+	// a delivery is pending until it succeeds or runs out of attempts
+	WebhookDeliveryStatusPending = "pending"
+	WebhookDeliveryStatusSuccess = "success"
+	WebhookDeliveryStatusFailed  = "failed"
+
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookEventIDHeader   = "X-Webhook-Event-ID"
+)
+
+type Webhook struct {
+	ID           int64       `db:"id" json:"id"`
+	UserID       int64       `db:"fk_user_id" json:"userID"`
+	ScopeType    string      `db:"scope_type" json:"scopeType"`
+	ScopeID      int64       `db:"scope_id" json:"scopeID"`
+	URL          string      `db:"url" json:"url"`
+	Secret       string      `db:"secret" json:"secret,omitempty"`
+	Events       string      `db:"events" json:"events"`
+	FailureCount int64       `db:"failure_count" json:"failureCount"`
+	IsDisabled   bool        `db:"is_disabled" json:"isDisabled"`
+	DisabledAt   null.Time   `db:"disabled_at" json:"disabledAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	Status       int64       `db:"status" json:"status"`
+	Flag         int64       `db:"flag" json:"flag,omitempty"`
+	Meta         null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt    null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy    null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt    null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy    null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt    null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy    null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type WebhookInputParam struct {
+	UserID    int64       `db:"fk_user_id" json:"-"`
+	ScopeType string      `db:"scope_type" json:"scopeType"`
+	ScopeID   int64       `db:"scope_id" json:"scopeID"`
+	URL       string      `db:"url" json:"url"`
+	Secret    string      `db:"secret" json:"-"`
+	Events    []string    `db:"-" json:"events"`
+	EventList string      `db:"events" json:"-"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type WebhookUpdateParam struct {
+	FailureCount null.Int64  `db:"failure_count" json:"-"`
+	IsDisabled   null.Bool   `db:"is_disabled" json:"-"`
+	DisabledAt   null.Time   `db:"disabled_at" json:"-"`
+	Status       null.Int64  `db:"status" json:"-"`
+	UpdatedAt    null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy    null.String `db:"updated_by" json:"-"`
+	DeletedAt    null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy    null.String `db:"deleted_by" json:"-"`
+}
+
+type WebhookParam struct {
+	ID        int64  `db:"id" uri:"webhook_id" param:"id"`
+	UserID    int64  `db:"fk_user_id" param:"fk_user_id"`
+	ScopeType string `db:"scope_type" form:"scopeType" param:"scope_type"`
+	ScopeID   int64  `db:"scope_id" form:"scopeID" param:"scope_id"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
+
+type WebhookDelivery struct {
+	ID             int64       `db:"id" json:"id"`
+	WebhookID      int64       `db:"fk_webhook_id" json:"webhookID"`
+	EventID        string      `db:"event_id" json:"eventID"`
+	EventType      string      `db:"event_type" json:"eventType"`
+	Payload        string      `db:"payload" json:"-"`
+	Attempt        int64       `db:"attempt" json:"attempt"`
+	DeliveryStatus string      `db:"delivery_status" json:"deliveryStatus"`
+	NextAttemptAt  null.Time   `db:"next_attempt_at" json:"nextAttemptAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	ResponseCode   int64       `db:"response_code" json:"responseCode"`
+	ErrorMessage   null.String `db:"error_message" json:"errorMessage,omitempty" swaggertype:"string"`
+	DurationMs     int64       `db:"duration_ms" json:"durationMs"`
+	Status         int64       `db:"status" json:"status"`
+	CreatedAt      null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedAt      null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+}
+
+type WebhookDeliveryInputParam struct {
+	WebhookID      int64     `db:"fk_webhook_id"`
+	EventID        string    `db:"event_id"`
+	EventType      string    `db:"event_type"`
+	Payload        string    `db:"payload"`
+	DeliveryStatus string    `db:"delivery_status"`
+	NextAttemptAt  null.Time `db:"next_attempt_at"`
+	CreatedAt      null.Time `db:"created_at"`
+}
+
+type WebhookDeliveryUpdateParam struct {
+	Attempt        null.Int64  `db:"attempt"`
+	DeliveryStatus null.String `db:"delivery_status"`
+	NextAttemptAt  null.Time   `db:"next_attempt_at"`
+	ResponseCode   null.Int64  `db:"response_code"`
+	ErrorMessage   null.String `db:"error_message"`
+	DurationMs     null.Int64  `db:"duration_ms"`
+	UpdatedAt      null.Time   `db:"updated_at"`
+}
+
+type WebhookDeliveryParam struct {
+	ID        int64  `db:"id" param:"id"`
+	WebhookID int64  `db:"fk_webhook_id" uri:"webhook_id" param:"fk_webhook_id"`
+	EventID   string `db:"event_id" form:"eventID" param:"event_id"`
+	PaginationParam
+	QueryOption query.Option
+}
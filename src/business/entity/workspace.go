@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+)
+
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
+type Workspace struct {
+	ID        int64       `db:"id" json:"id"`
+	OwnerID   int64       `db:"fk_owner_id" json:"ownerID"`
+	Name      string      `db:"name" json:"name"`
+	Slug      string      `db:"slug" json:"slug"`
+	Status    int64       `db:"status" json:"status"`
+	Flag      int64       `db:"flag" json:"flag,omitempty"`
+	Meta      null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type WorkspaceInputParam struct {
+	OwnerID   int64       `db:"fk_owner_id" json:"-"`
+	Name      string      `db:"name" json:"name"`
+	Slug      string      `db:"slug" json:"slug"`
+	CreatedAt null.Time   `db:"created_at" json:"-"`
+	CreatedBy null.String `db:"created_by" json:"-"`
+}
+
+type WorkspaceParam struct {
+	ID   int64  `db:"id" uri:"workspace_id" param:"id"`
+	Slug string `db:"slug" param:"slug"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
+
+type WorkspaceMember struct {
+	ID          int64       `db:"id" json:"id"`
+	WorkspaceID int64       `db:"fk_workspace_id" json:"workspaceID"`
+	UserID      int64       `db:"fk_user_id" json:"userID"`
+	MemberRole  string      `db:"member_role" json:"role"`
+	Status      int64       `db:"status" json:"status"`
+	Flag        int64       `db:"flag" json:"flag,omitempty"`
+	Meta        null.String `db:"meta" json:"meta,omitempty" swaggertype:"string"`
+	CreatedAt   null.Time   `db:"created_at" json:"createdAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	CreatedBy   null.String `db:"created_by" json:"createdBy" swaggertype:"string"`
+	UpdatedAt   null.Time   `db:"updated_at" json:"updatedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	UpdatedBy   null.String `db:"updated_by" json:"updatedBy" swaggertype:"string"`
+	DeletedAt   null.Time   `db:"deleted_at" json:"deletedAt,omitempty" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	DeletedBy   null.String `db:"deleted_by" json:"deletedBy,omitempty" swaggertype:"string"`
+}
+
+type WorkspaceMemberInputParam struct {
+	WorkspaceID int64       `db:"fk_workspace_id" json:"-"`
+	UserID      int64       `db:"fk_user_id" json:"userID"`
+	MemberRole  string      `db:"member_role" json:"role"`
+	CreatedAt   null.Time   `db:"created_at" json:"-"`
+	CreatedBy   null.String `db:"created_by" json:"-"`
+}
+
+type WorkspaceMemberUpdateParam struct {
+	MemberRole null.String `db:"member_role" json:"-"`
+	Status     null.Int64  `db:"status" json:"-"`
+	UpdatedAt  null.Time   `db:"updated_at" json:"-"`
+	UpdatedBy  null.String `db:"updated_by" json:"-"`
+	DeletedAt  null.Time   `db:"deleted_at" json:"-"`
+	DeletedBy  null.String `db:"deleted_by" json:"-"`
+}
+
+type WorkspaceMemberParam struct {
+	ID          int64 `db:"id" param:"id"`
+	WorkspaceID int64 `db:"fk_workspace_id" param:"fk_workspace_id"`
+	UserID      int64 `db:"fk_user_id" uri:"user_id" param:"fk_user_id"`
+	PaginationParam
+	QueryOption query.Option
+	BypassCache bool
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var Now = time.Now
+
+const (
+	defaultPurgeInterval  = time.Hour
+	defaultPurgeBatchSize = 1000
+)
+
+type Interface interface {
+	GetList(ctx context.Context, param entity.AuditParam) ([]entity.Audit, *entity.Pagination, error)
+	// Purge deletes every entry older than the configured retention, it is a no-op when retention is not set
+	Purge(ctx context.Context) (int64, error)
+}
+
+type audit struct {
+	audit auditDomain.Interface
+	log   log.Interface
+	cfg   config.AuditConfig
+}
+
+type InitParam struct {
+	AuditDomain auditDomain.Interface
+	Log         log.Interface
+	Scheduler   scheduler.Interface
+	Config      config.AuditConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	if cfg.PurgeBatchSize < 1 {
+		cfg.PurgeBatchSize = defaultPurgeBatchSize
+	}
+
+	a := &audit{
+		audit: param.AuditDomain,
+		log:   param.Log,
+		cfg:   cfg,
+	}
+
+	err := param.Scheduler.Register("audit-purge", scheduler.Every(cfg.PurgeInterval), a.purge)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return a
+}
+
+func (a *audit) GetList(ctx context.Context, param entity.AuditParam) ([]entity.Audit, *entity.Pagination, error) {
+	param.IncludePagination = true
+
+	audits, pg, err := a.audit.GetList(ctx, param)
+	if err != nil {
+		return audits, pg, err
+	}
+
+	// reading the audit trail is an admin action on its own
+	err = a.audit.Create(ctx, entity.AuditInputParam{
+		Action:     entity.AuditActionAuditQuery,
+		TargetType: entity.AuditTargetAudit,
+		AfterValue: param,
+	})
+	if err != nil {
+		a.log.Error(ctx, err)
+	}
+
+	return audits, pg, nil
+}
+
+func (a *audit) Purge(ctx context.Context) (int64, error) {
+	if a.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	before := Now().Add(-a.cfg.Retention)
+
+	var total int64
+	for {
+		deleted, err := a.audit.DeleteBefore(ctx, before, a.cfg.PurgeBatchSize)
+		total += deleted
+		if err != nil || deleted < int64(a.cfg.PurgeBatchSize) {
+			return total, err
+		}
+	}
+}
+
+func (a *audit) purge(ctx context.Context) error {
+	deleted, err := a.Purge(ctx)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		a.log.Info(ctx, fmt.Sprintf("purged %v audit entries", deleted))
+	}
+
+	return nil
+}
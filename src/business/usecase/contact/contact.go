@@ -0,0 +1,269 @@
+package contact
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	contactDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/contact"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/presence"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/relation"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+)
+
+var Now = time.Now
+
+type Interface interface {
+	SendRequest(ctx context.Context, inputParam entity.ContactInputParam) (entity.Contact, error)
+	GetRequestList(ctx context.Context, param entity.ContactParam) ([]entity.Contact, *entity.Pagination, error)
+	Accept(ctx context.Context, param entity.ContactParam) error
+	Decline(ctx context.Context, param entity.ContactParam) error
+	Cancel(ctx context.Context, param entity.ContactParam) error
+	// GetList returns a page of the accepted contacts of the current user with their presence,
+	// users on either side of a block with the current user are left out
+	GetList(ctx context.Context, param entity.ContactUserParam) ([]entity.ContactUser, *entity.Pagination, error)
+	// EnsureCanDirectMessage returns a forbidden error when direct messages are restricted to
+	// contacts and the two users are not contacts
+	EnsureCanDirectMessage(ctx context.Context, userID, otherUserID int64) error
+}
+
+type contact struct {
+	contact  contactDomain.Interface
+	user     userDomain.Interface
+	relation relation.Interface
+	presence presence.Interface
+	log      log.Interface
+	cfg      config.ContactConfig
+}
+
+type InitParam struct {
+	ContactDomain contactDomain.Interface
+	UserDomain    userDomain.Interface
+	Relation      relation.Interface
+	Presence      presence.Interface
+	Log           log.Interface
+	Config        config.ContactConfig
+}
+
+func Init(param InitParam) Interface {
+	return &contact{
+		contact:  param.ContactDomain,
+		user:     param.UserDomain,
+		relation: param.Relation,
+		presence: param.Presence,
+		log:      param.Log,
+		cfg:      param.Config,
+	}
+}
+
+func (c *contact) SendRequest(ctx context.Context, inputParam entity.ContactInputParam) (entity.Contact, error) {
+	contact := entity.Contact{}
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if inputParam.ContactID < 1 {
+		return contact, errors.NewWithCode(codes.CodeBadRequest, "user id is required")
+	}
+
+	if inputParam.ContactID == userID {
+		return contact, errors.NewWithCode(codes.CodeBadRequest, "cannot add yourself as contact")
+	}
+
+	_, err := c.user.Get(ctx, entity.UserParam{
+		ID: inputParam.ContactID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return contact, errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return contact, err
+	}
+
+	isBlocked, err := c.relation.IsBlocked(ctx, userID, inputParam.ContactID)
+	if err != nil {
+		return contact, err
+	} else if isBlocked {
+		return contact, errors.NewWithCode(codes.CodeForbidden, "cannot send contact request to this user")
+	}
+
+	for _, status := range []string{entity.ContactStatusAccepted, entity.ContactStatusPending} {
+		existing, isExist, err := c.getBetween(ctx, userID, inputParam.ContactID, status)
+		if err != nil {
+			return contact, err
+		} else if !isExist {
+			continue
+		}
+
+		switch {
+		case status == entity.ContactStatusAccepted:
+			return contact, errors.NewWithCode(codes.CodeConflict, "user is already a contact")
+		case existing.UserID == userID:
+			return contact, errors.NewWithCode(codes.CodeConflict, "contact request already sent")
+		default:
+			return contact, errors.NewWithCode(codes.CodeConflict, "user already sent you a contact request")
+		}
+	}
+
+	inputParam.UserID = userID
+	inputParam.ContactStatus = entity.ContactStatusPending
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	// the checks above race with a concurrent request from either side, the unique pair key settles it
+	contact, err = c.contact.Create(ctx, inputParam)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLUniqueConstraint {
+		return contact, errors.NewWithCode(codes.CodeConflict, "a contact request with this user already exists")
+	} else if err != nil {
+		return contact, err
+	}
+
+	return contact, nil
+}
+
+func (c *contact) GetRequestList(ctx context.Context, param entity.ContactParam) ([]entity.Contact, *entity.Pagination, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	switch param.Direction {
+	case entity.ContactDirectionOutgoing:
+		param.UserID = userID
+	case entity.ContactDirectionIncoming, "":
+		param.ContactID = userID
+	default:
+		return nil, nil, errors.NewWithCode(codes.CodeBadRequest, "invalid direction %s", param.Direction)
+	}
+
+	param.ContactStatus = entity.ContactStatusPending
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return c.contact.GetList(ctx, param)
+}
+
+func (c *contact) Accept(ctx context.Context, param entity.ContactParam) error {
+	return c.respond(ctx, param, entity.ContactStatusAccepted)
+}
+
+func (c *contact) Decline(ctx context.Context, param entity.ContactParam) error {
+	return c.respond(ctx, param, entity.ContactStatusDeclined)
+}
+
+func (c *contact) Cancel(ctx context.Context, param entity.ContactParam) error {
+	return c.respond(ctx, param, entity.ContactStatusCancelled)
+}
+
+func (c *contact) GetList(ctx context.Context, param entity.ContactUserParam) ([]entity.ContactUser, *entity.Pagination, error) {
+	param.UserID = int64(appcontext.GetUserId(ctx))
+
+	blockedUserIDs, err := c.relation.GetBlockedUserIDs(ctx, param.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	param.ExcludeUserIDs = blockedUserIDs
+
+	contactUsers, pg, err := c.contact.GetUserList(ctx, param)
+	if err != nil {
+		return contactUsers, pg, err
+	}
+
+	for i := range contactUsers {
+		// presence is best effort, a redis failure should not hide the contact list
+		presence, err := c.presence.Get(ctx, contactUsers[i].ID)
+		if err != nil {
+			c.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+			continue
+		}
+
+		contactUsers[i].IsOnline = presence.IsOnline
+		contactUsers[i].LastSeenAt = presence.LastSeenAt
+	}
+
+	return contactUsers, pg, nil
+}
+
+func (c *contact) EnsureCanDirectMessage(ctx context.Context, userID, otherUserID int64) error {
+	if !c.cfg.ContactsOnlyDirectMessage {
+		return nil
+	}
+
+	_, isContact, err := c.getBetween(ctx, userID, otherUserID, entity.ContactStatusAccepted)
+	if err != nil {
+		return err
+	} else if !isContact {
+		return errors.NewWithCode(codes.CodeForbidden, "direct messages are restricted to contacts")
+	}
+
+	return nil
+}
+
+func (c *contact) respond(ctx context.Context, param entity.ContactParam, contactStatus string) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	contact, err := c.contact.Get(ctx, entity.ContactParam{
+		ID: param.ID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+		BypassCache: true,
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "contact request not found")
+	} else if err != nil {
+		return err
+	}
+
+	// only the sender can cancel and only the receiver can accept or decline
+	ownerID := contact.ContactID
+	if contactStatus == entity.ContactStatusCancelled {
+		ownerID = contact.UserID
+	}
+
+	if ownerID != userID {
+		return errors.NewWithCode(codes.CodeNotFound, "contact request not found")
+	}
+
+	if contact.ContactStatus != entity.ContactStatusPending {
+		return errors.NewWithCode(codes.CodeConflict, "contact request is already %s", contact.ContactStatus)
+	}
+
+	return c.contact.Update(ctx, entity.ContactUpdateParam{
+		ContactStatus: null.StringFrom(contactStatus),
+		RespondedAt:   null.TimeFrom(Now()),
+		UpdatedAt:     null.TimeFrom(Now()),
+		UpdatedBy:     null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.ContactParam{
+		ID: contact.ID,
+	})
+}
+
+// getBetween looks up a request in either direction between the two users
+func (c *contact) getBetween(ctx context.Context, userID, otherUserID int64, contactStatus string) (entity.Contact, bool, error) {
+	for _, param := range []entity.ContactParam{
+		{UserID: userID, ContactID: otherUserID},
+		{UserID: otherUserID, ContactID: userID},
+	} {
+		param.ContactStatus = contactStatus
+		param.QueryOption.IsActive = true
+		param.BypassCache = true
+
+		contact, err := c.contact.Get(ctx, param)
+		if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+			continue
+		} else if err != nil {
+			return contact, false, err
+		}
+
+		return contact, true, nil
+	}
+
+	return entity.Contact{}, false, nil
+}
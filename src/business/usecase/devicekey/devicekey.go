@@ -0,0 +1,259 @@
+package devicekey
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	devicekeyDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/devicekey"
+	notificationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/contact"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/relation"
+)
+
+var Now = time.Now
+
+const (
+	// maxPublicKeyLength fits a base64 encoded key in the key columns
+	maxPublicKeyLength  = 255
+	maxPrekeysPerUpload = 100
+)
+
+type Interface interface {
+	// Upload publishes the public keys of one of the current user's devices. A new identity key
+	// invalidates the one-time prekeys uploaded for the previous one
+	Upload(ctx context.Context, inputParam entity.DeviceKeyInputParam) (entity.DeviceKey, error)
+	// Get returns the published keys of one of the current user's devices and how many prekeys are left
+	Get(ctx context.Context, param entity.DeviceKeyParam) (entity.DeviceKey, error)
+	// GetBundleList returns a key bundle for every device of the user, claiming one one-time prekey per device
+	GetBundleList(ctx context.Context, param entity.KeyBundleParam) ([]entity.KeyBundle, error)
+}
+
+type devicekey struct {
+	devicekey    devicekeyDomain.Interface
+	notification notificationDomain.Interface
+	relation     relation.Interface
+	contact      contact.Interface
+}
+
+type InitParam struct {
+	DeviceKeyDomain    devicekeyDomain.Interface
+	NotificationDomain notificationDomain.Interface
+	Relation           relation.Interface
+	Contact            contact.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &devicekey{
+		devicekey:    param.DeviceKeyDomain,
+		notification: param.NotificationDomain,
+		relation:     param.Relation,
+		contact:      param.Contact,
+	}
+}
+
+func (d *devicekey) Upload(ctx context.Context, inputParam entity.DeviceKeyInputParam) (entity.DeviceKey, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if err := ValidateInput(inputParam); err != nil {
+		return entity.DeviceKey{}, err
+	}
+
+	_, err := d.notification.GetDevice(ctx, entity.DeviceParam{
+		ID:     inputParam.DeviceID,
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.DeviceKey{}, errors.NewWithCode(codes.CodeNotFound, "device not found")
+	} else if err != nil {
+		return entity.DeviceKey{}, err
+	}
+
+	// prekeys left by another identity, or by the previous owner of the device, must not be handed out
+	existing, err := d.devicekey.Get(ctx, entity.DeviceKeyParam{
+		DeviceID: inputParam.DeviceID,
+	})
+	switch {
+	case err == nil:
+		inputParam.ResetPrekeys = existing.UserID != userID || existing.IdentityKey != inputParam.IdentityKey
+	case errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist:
+		return entity.DeviceKey{}, err
+	}
+
+	inputParam.UserID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	for i := range inputParam.OneTimePrekeys {
+		inputParam.OneTimePrekeys[i].UserID = userID
+		inputParam.OneTimePrekeys[i].DeviceID = inputParam.DeviceID
+		inputParam.OneTimePrekeys[i].CreatedAt = inputParam.CreatedAt
+		inputParam.OneTimePrekeys[i].CreatedBy = inputParam.CreatedBy
+	}
+
+	err = d.devicekey.Upsert(ctx, inputParam)
+	if err != nil {
+		return entity.DeviceKey{}, err
+	}
+
+	return d.Get(ctx, entity.DeviceKeyParam{DeviceID: inputParam.DeviceID})
+}
+
+func (d *devicekey) Get(ctx context.Context, param entity.DeviceKeyParam) (entity.DeviceKey, error) {
+	deviceKey, err := d.devicekey.Get(ctx, entity.DeviceKeyParam{
+		DeviceID: param.DeviceID,
+		UserID:   int64(appcontext.GetUserId(ctx)),
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return deviceKey, errors.NewWithCode(codes.CodeNotFound, "device key not found")
+	} else if err != nil {
+		return deviceKey, err
+	}
+
+	deviceKey.RemainingPrekeys, err = d.devicekey.CountOneTimePrekey(ctx, deviceKey.DeviceID)
+	if err != nil {
+		return deviceKey, err
+	}
+
+	return deviceKey, nil
+}
+
+func (d *devicekey) GetBundleList(ctx context.Context, param entity.KeyBundleParam) ([]entity.KeyBundle, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+	bundles := []entity.KeyBundle{}
+
+	// fetching a bundle is the first step of a direct conversation, it follows the same rules
+	if param.UserID != userID {
+		isBlocked, err := d.relation.IsBlocked(ctx, userID, param.UserID)
+		if err != nil {
+			return bundles, err
+		} else if isBlocked {
+			return bundles, errors.NewWithCode(codes.CodeForbidden, "user is not available")
+		}
+
+		err = d.contact.EnsureCanDirectMessage(ctx, userID, param.UserID)
+		if err != nil {
+			return bundles, err
+		}
+	}
+
+	option := query.Option{
+		IsActive:     true,
+		DisableLimit: true,
+	}
+
+	devices, _, err := d.notification.GetDeviceList(ctx, entity.DeviceParam{
+		UserID:      param.UserID,
+		QueryOption: option,
+	})
+	if err != nil {
+		return bundles, err
+	}
+
+	activeDevices := map[int64]bool{}
+	for _, device := range devices {
+		activeDevices[device.ID] = true
+	}
+
+	deviceKeys, _, err := d.devicekey.GetList(ctx, entity.DeviceKeyParam{
+		UserID:      param.UserID,
+		QueryOption: option,
+	})
+	if err != nil {
+		return bundles, err
+	}
+
+	for _, deviceKey := range deviceKeys {
+		if !activeDevices[deviceKey.DeviceID] {
+			continue
+		}
+
+		bundle := entity.KeyBundle{
+			UserID:                deviceKey.UserID,
+			DeviceID:              deviceKey.DeviceID,
+			IdentityKey:           deviceKey.IdentityKey,
+			SignedPrekeyID:        deviceKey.SignedPrekeyID,
+			SignedPrekey:          deviceKey.SignedPrekey,
+			SignedPrekeySignature: deviceKey.SignedPrekeySignature,
+		}
+
+		// a device out of one-time prekeys can still be reached with its signed prekey alone
+		prekey, err := d.devicekey.ClaimOneTimePrekey(ctx, entity.OneTimePrekeyClaimParam{
+			DeviceID:  deviceKey.DeviceID,
+			ClaimedBy: userID,
+			ClaimedAt: null.TimeFrom(Now()),
+		})
+		switch {
+		case err == nil:
+			bundle.OneTimePrekey = &prekey
+		case errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist:
+			return bundles, err
+		}
+
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, nil
+}
+
+// ValidateInput checks the shape of the uploaded keys only, signatures are verified by the peers
+// against the identity key so the server never has to be trusted with them
+func ValidateInput(inputParam entity.DeviceKeyInputParam) error {
+	keys := []struct{ name, value string }{
+		{name: "identity key", value: inputParam.IdentityKey},
+		{name: "signed prekey", value: inputParam.SignedPrekey},
+		{name: "signed prekey signature", value: inputParam.SignedPrekeySignature},
+	}
+	for _, key := range keys {
+		if err := validateKey(key.name, key.value); err != nil {
+			return err
+		}
+	}
+
+	if len(inputParam.OneTimePrekeys) > maxPrekeysPerUpload {
+		return errors.NewWithCode(codes.CodeBadRequest, "at most %d one-time prekeys can be uploaded at once", maxPrekeysPerUpload)
+	}
+
+	keyIDs := map[int64]bool{}
+	for _, prekey := range inputParam.OneTimePrekeys {
+		if keyIDs[prekey.KeyID] {
+			return errors.NewWithCode(codes.CodeBadRequest, "duplicate one-time prekey id %d", prekey.KeyID)
+		}
+		keyIDs[prekey.KeyID] = true
+
+		if err := validateKey("one-time prekey", prekey.PublicKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateKey(name, key string) error {
+	if key == "" {
+		return errors.NewWithCode(codes.CodeBadRequest, "%s is required", name)
+	}
+
+	if len(key) > maxPublicKeyLength {
+		return errors.NewWithCode(codes.CodeBadRequest, "%s must be at most %d characters", name, maxPublicKeyLength)
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(key); err != nil {
+		return errors.NewWithCode(codes.CodeBadRequest, "%s must be base64 encoded", name)
+	}
+
+	return nil
+}
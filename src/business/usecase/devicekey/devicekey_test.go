@@ -0,0 +1,56 @@
+package devicekey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateInput(t *testing.T) {
+	valid := func() entity.DeviceKeyInputParam {
+		return entity.DeviceKeyInputParam{
+			IdentityKey:           "aWRlbnRpdHk=",
+			SignedPrekeyID:        1,
+			SignedPrekey:          "cHJla2V5",
+			SignedPrekeySignature: "c2lnbmF0dXJl",
+			OneTimePrekeys: []entity.OneTimePrekeyInputParam{
+				{KeyID: 1, PublicKey: "b25l"},
+				{KeyID: 2, PublicKey: "dHdv"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *entity.DeviceKeyInputParam)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *entity.DeviceKeyInputParam) {}},
+		{name: "missing identity key", modify: func(p *entity.DeviceKeyInputParam) { p.IdentityKey = "" }, wantErr: true},
+		{name: "signature not base64", modify: func(p *entity.DeviceKeyInputParam) { p.SignedPrekeySignature = "not base64!" }, wantErr: true},
+		{name: "key too long", modify: func(p *entity.DeviceKeyInputParam) { p.SignedPrekey = strings.Repeat("A", 256) }, wantErr: true},
+		{name: "duplicate prekey id", modify: func(p *entity.DeviceKeyInputParam) { p.OneTimePrekeys[1].KeyID = 1 }, wantErr: true},
+		{
+			name: "too many prekeys",
+			modify: func(p *entity.DeviceKeyInputParam) {
+				p.OneTimePrekeys = nil
+				for i := 0; i <= maxPrekeysPerUpload; i++ {
+					p.OneTimePrekeys = append(p.OneTimePrekeys, entity.OneTimePrekeyInputParam{KeyID: int64(i), PublicKey: "a2V5"})
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := valid()
+			tt.modify(&param)
+
+			err := ValidateInput(param)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/query"
+	digestDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/digest"
+	notificationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/presence"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/mail"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var Now = time.Now
+
+const (
+	defaultInterval         = time.Hour
+	templateName            = "digest.tmpl"
+	defaultTemplatePath     = "./docs/templates/digest.html"
+	defaultMaxItemsPerScope = 3
+	maxUnreadPerUser        = 100
+
+	// markerTTL keeps the run and per-user markers across outages much longer than any interval,
+	// windows never reach back further than it
+	markerTTL = 30 * 24 * time.Hour
+)
+
+type Interface interface {
+	// Send emails a digest to every offline user with notifications received since the previous run
+	Send(ctx context.Context) (int, error)
+	// Unsubscribe turns off the email digest of the user the token was signed for
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type digest struct {
+	digest       digestDomain.Interface
+	notification notificationDomain.Interface
+	user         userDomain.Interface
+	preference   preference.Interface
+	presence     presence.Interface
+	mail         mail.Interface
+	log          log.Interface
+	cfg          config.DigestConfig
+	tmpl         *template.Template
+}
+
+type InitParam struct {
+	DigestDomain       digestDomain.Interface
+	NotificationDomain notificationDomain.Interface
+	UserDomain         userDomain.Interface
+	Preference         preference.Interface
+	Presence           presence.Interface
+	Mail               mail.Interface
+	Scheduler          scheduler.Interface
+	Log                log.Interface
+	Config             config.DigestConfig
+}
+
+func Init(param InitParam) Interface {
+	// an empty secret would let anyone forge unsubscribe links
+	if param.Config.UnsubscribeSecret == "" {
+		param.Log.Fatal(context.Background(), errors.NewWithCode(codes.CodeInternalServerError, "digest unsubscribe secret is not configured"))
+	}
+
+	if param.Config.TemplatePath == "" {
+		param.Config.TemplatePath = defaultTemplatePath
+	}
+
+	if param.Config.Interval <= 0 {
+		param.Config.Interval = defaultInterval
+	}
+
+	if param.Config.MaxItemsPerScope <= 0 {
+		param.Config.MaxItemsPerScope = defaultMaxItemsPerScope
+	}
+
+	d := &digest{
+		digest:       param.DigestDomain,
+		notification: param.NotificationDomain,
+		user:         param.UserDomain,
+		preference:   param.Preference,
+		presence:     param.Presence,
+		mail:         param.Mail,
+		log:          param.Log,
+		cfg:          param.Config,
+	}
+
+	if param.Config.Enabled {
+		tmpl, err := template.ParseFiles(param.Config.TemplatePath)
+		if err != nil {
+			param.Log.Fatal(context.Background(), err)
+		}
+		d.tmpl = tmpl
+
+		err = param.Scheduler.Register("email-digest", scheduler.Every(param.Config.Interval), d.send)
+		if err != nil {
+			param.Log.Fatal(context.Background(), err)
+		}
+	}
+
+	return d
+}
+
+func (d *digest) Send(ctx context.Context) (int, error) {
+	now := Now()
+
+	// the window starts where the previous run stopped, so replicas taking turns neither skip
+	// nor repeat notifications
+	lastSent, err := d.digest.GetLastSent(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	from := now.Add(-d.cfg.Interval)
+	if lastSent.Valid && now.Sub(lastSent.Time) < markerTTL {
+		from = lastSent.Time
+	}
+
+	userIDs, err := d.notification.GetUnreadUserIDList(ctx, from, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, userID := range userIDs {
+		// each user resumes from their own progress, so a run retried after a failure only
+		// mails the users it did not get to
+		userFrom := from
+		userLastSent, err := d.digest.GetUserLastSent(ctx, userID)
+		if err != nil {
+			d.log.Error(ctx, err)
+		} else if userLastSent.Valid && userLastSent.Time.After(userFrom) {
+			userFrom = userLastSent.Time
+		}
+
+		if !userFrom.Before(now) {
+			continue
+		}
+
+		ok, err := d.sendUser(ctx, userID, userFrom, now)
+		if err != nil {
+			d.log.Error(ctx, err)
+			failed++
+			continue
+		} else if ok {
+			sent++
+		}
+
+		err = d.digest.SetUserLastSent(ctx, userID, now, markerTTL)
+		if err != nil {
+			d.log.Error(ctx, err)
+		}
+	}
+
+	// the run marker only moves once every user of the window was handled, failed users are
+	// picked up again by the next run
+	if failed > 0 {
+		return sent, errors.NewWithCode(codes.CodeInternalServerError, "email digest failed for %v of %v users", failed, len(userIDs))
+	}
+
+	err = d.digest.SetLastSent(ctx, now, markerTTL)
+	if err != nil {
+		return sent, err
+	}
+
+	return sent, nil
+}
+
+func (d *digest) send(ctx context.Context) error {
+	sent, err := d.Send(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		d.log.Info(ctx, fmt.Sprintf("sent %v email digests", sent))
+	}
+
+	return nil
+}
+
+func (d *digest) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := VerifyUnsubscribeToken(d.cfg.UnsubscribeSecret, token)
+	if err != nil {
+		return err
+	}
+
+	return d.preference.SetEmailDigest(ctx, userID, false)
+}
+
+func (d *digest) sendUser(ctx context.Context, userID int64, from, now time.Time) (bool, error) {
+	isAllowed, err := d.preference.IsDigestAllowed(ctx, userID)
+	if err != nil || !isAllowed {
+		return false, err
+	}
+
+	presence, err := d.presence.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	// users who were around recently have already seen their feed
+	if presence.IsOnline || (presence.LastSeenAt.Valid && now.Sub(presence.LastSeenAt.Time) < d.cfg.OfflineAfter) {
+		return false, nil
+	}
+
+	user, err := d.user.Get(ctx, entity.UserParam{
+		ID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	notifications, _, err := d.notification.GetList(ctx, entity.NotificationParam{
+		UserID:     userID,
+		ReadStatus: entity.NotificationReadStatusUnread,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+		PaginationParam: entity.PaginationParam{
+			Limit: maxUnreadPerUser,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	digest := BuildDigest(notifications, from, d.cfg.MaxItemsPerScope)
+	if digest.UnreadCount == 0 {
+		return false, nil
+	}
+
+	digest.Name = user.Name
+	digest.Subject = fmt.Sprintf("You have %d unread notifications", digest.UnreadCount)
+	digest.UnsubscribeURL = d.unsubscribeURL(userID)
+
+	var body bytes.Buffer
+	err = d.tmpl.ExecuteTemplate(&body, templateName, digest)
+	if err != nil {
+		return false, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	err = d.mail.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: digest.Subject,
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe": fmt.Sprintf("<%s>", digest.UnsubscribeURL),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (d *digest) unsubscribeURL(userID int64) string {
+	separator := "?"
+	if strings.Contains(d.cfg.UnsubscribeURL, "?") {
+		separator = "&"
+	}
+
+	return d.cfg.UnsubscribeURL + separator + "token=" + SignUnsubscribeToken(d.cfg.UnsubscribeSecret, userID)
+}
+
+// BuildDigest groups the unread notifications received since from by scope, keeping at most
+// maxItems notifications per scope while still counting all of them
+func BuildDigest(notifications []entity.Notification, from time.Time, maxItems int) entity.Digest {
+	digest := entity.Digest{}
+	scopeIndex := map[string]int{}
+
+	for _, notification := range notifications {
+		if notification.ReadStatus != entity.NotificationReadStatusUnread || notification.CreatedAt.Time.Before(from) {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", notification.ScopeType, notification.ScopeID)
+		i, ok := scopeIndex[key]
+		if !ok {
+			i = len(digest.Scopes)
+			scopeIndex[key] = i
+			digest.Scopes = append(digest.Scopes, entity.DigestScope{
+				ScopeType: notification.ScopeType,
+				ScopeID:   notification.ScopeID,
+				Title:     notification.Title,
+			})
+		}
+
+		scope := &digest.Scopes[i]
+		scope.UnreadCount++
+		digest.UnreadCount++
+		if notification.NotificationType == entity.NotificationTypeMention {
+			scope.MentionCount++
+			digest.MentionCount++
+		}
+
+		if len(scope.Items) < maxItems {
+			scope.Items = append(scope.Items, notification)
+		}
+	}
+
+	return digest
+}
+
+// SignUnsubscribeToken returns a token that identifies the user in an unsubscribe link without a session
+func SignUnsubscribeToken(secret string, userID int64) string {
+	id := strconv.FormatInt(userID, 10)
+
+	return id + "." + sign(secret, id)
+}
+
+func VerifyUnsubscribeToken(secret, token string) (int64, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(secret, id))) {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid unsubscribe token")
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid unsubscribe token")
+	}
+
+	return userID, nil
+}
+
+func sign(secret, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
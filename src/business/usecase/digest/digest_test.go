@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildDigest(t *testing.T) {
+	from := time.Date(2024, 6, 21, 10, 0, 0, 0, time.UTC)
+	notification := func(scopeID int64, notificationType, readStatus string, createdAt time.Time) entity.Notification {
+		return entity.Notification{
+			NotificationType: notificationType,
+			ScopeType:        "conversation",
+			ScopeID:          scopeID,
+			Title:            "general",
+			ReadStatus:       readStatus,
+			CreatedAt:        null.TimeFrom(createdAt),
+		}
+	}
+
+	notifications := []entity.Notification{
+		notification(1, entity.NotificationTypeMessage, entity.NotificationReadStatusUnread, from.Add(time.Minute)),
+		notification(1, entity.NotificationTypeMention, entity.NotificationReadStatusUnread, from.Add(2*time.Minute)),
+		notification(1, entity.NotificationTypeMessage, entity.NotificationReadStatusUnread, from.Add(3*time.Minute)),
+		notification(2, entity.NotificationTypeMessage, entity.NotificationReadStatusUnread, from.Add(time.Minute)),
+		notification(2, entity.NotificationTypeMessage, entity.NotificationReadStatusRead, from.Add(time.Minute)),
+		notification(3, entity.NotificationTypeMessage, entity.NotificationReadStatusUnread, from.Add(-time.Minute)),
+	}
+
+	digest := BuildDigest(notifications, from, 2)
+
+	assert.Equal(t, 4, digest.UnreadCount)
+	assert.Equal(t, 1, digest.MentionCount)
+	assert.Len(t, digest.Scopes, 2)
+	assert.Equal(t, int64(1), digest.Scopes[0].ScopeID)
+	assert.Equal(t, 3, digest.Scopes[0].UnreadCount)
+	assert.Equal(t, 1, digest.Scopes[0].MentionCount)
+	assert.Len(t, digest.Scopes[0].Items, 2)
+	assert.Equal(t, int64(2), digest.Scopes[1].ScopeID)
+	assert.Equal(t, 1, digest.Scopes[1].UnreadCount)
+}
+
+func Test_UnsubscribeToken(t *testing.T) {
+	token := SignUnsubscribeToken("secret", 42)
+
+	userID, err := VerifyUnsubscribeToken("secret", token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "wrong secret", token: SignUnsubscribeToken("other", 42)},
+		{name: "tampered user", token: "43" + token[2:]},
+		{name: "missing signature", token: "42"},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyUnsubscribeToken("secret", tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
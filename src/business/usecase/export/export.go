@@ -0,0 +1,775 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	exportDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/export"
+	notificationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var Now = time.Now
+
+const (
+	defaultInterval   = time.Minute
+	defaultBatchSize  = 1000
+	defaultChunkSize  = 1 << 20
+	defaultRunTimeout = time.Hour
+	defaultFileTTL    = 7 * 24 * time.Hour
+	defaultLinkTTL    = 24 * time.Hour
+	maxErrorLength    = 255
+	fileNamePrefix    = "export-"
+)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.ExportInputParam) (entity.Export, error)
+	Get(ctx context.Context, param entity.ExportParam) (entity.Export, error)
+	GetList(ctx context.Context, param entity.ExportParam) ([]entity.Export, *entity.Pagination, error)
+	// RequestOwn queues an export of everything stored about the current user since the account was created
+	RequestOwn(ctx context.Context) (entity.Export, error)
+	// GetOwn returns an export the current user requested about themselves
+	GetOwn(ctx context.Context, param entity.ExportParam) (entity.Export, error)
+	// Download returns the archive of the finished export the token was signed for
+	Download(ctx context.Context, token string) (entity.ExportFile, error)
+}
+
+type export struct {
+	export       exportDomain.Interface
+	user         userDomain.Interface
+	notification notificationDomain.Interface
+	audit        auditDomain.Interface
+	log          log.Interface
+	cfg          config.ExportConfig
+}
+
+type InitParam struct {
+	ExportDomain       exportDomain.Interface
+	UserDomain         userDomain.Interface
+	NotificationDomain notificationDomain.Interface
+	AuditDomain        auditDomain.Interface
+	Scheduler          scheduler.Interface
+	Log                log.Interface
+	Config             config.ExportConfig
+}
+
+func Init(param InitParam) Interface {
+	// an empty secret would let anyone forge download links
+	if param.Config.Secret == "" {
+		param.Log.Fatal(context.Background(), errors.NewWithCode(codes.CodeInternalServerError, "export secret is not configured"))
+	}
+
+	if param.Config.Interval <= 0 {
+		param.Config.Interval = defaultInterval
+	}
+
+	if param.Config.BatchSize <= 0 {
+		param.Config.BatchSize = defaultBatchSize
+	}
+
+	if param.Config.ChunkSize <= 0 {
+		param.Config.ChunkSize = defaultChunkSize
+	}
+
+	if param.Config.RunTimeout <= 0 {
+		param.Config.RunTimeout = defaultRunTimeout
+	}
+
+	if param.Config.FileTTL <= 0 {
+		param.Config.FileTTL = defaultFileTTL
+	}
+
+	if param.Config.LinkTTL <= 0 {
+		param.Config.LinkTTL = defaultLinkTTL
+	}
+
+	e := &export{
+		export:       param.ExportDomain,
+		user:         param.UserDomain,
+		notification: param.NotificationDomain,
+		audit:        param.AuditDomain,
+		log:          param.Log,
+		cfg:          param.Config,
+	}
+
+	err := param.Scheduler.Register("compliance-export", scheduler.Every(param.Config.Interval), e.run)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return e
+}
+
+func (e *export) Create(ctx context.Context, inputParam entity.ExportInputParam) (entity.Export, error) {
+	if inputParam.SubjectType != entity.ExportSubjectUser {
+		return entity.Export{}, errors.NewWithCode(codes.CodeBadRequest, "invalid export subject %s", inputParam.SubjectType)
+	} else if inputParam.RangeStart.IsZero() || inputParam.RangeEnd.IsZero() {
+		return entity.Export{}, errors.NewWithCode(codes.CodeBadRequest, "export range is required")
+	} else if !inputParam.RangeStart.Before(inputParam.RangeEnd) {
+		return entity.Export{}, errors.NewWithCode(codes.CodeBadRequest, "export range start must be before its end")
+	}
+
+	_, err := e.user.Get(ctx, entity.UserParam{ID: inputParam.SubjectID, BypassCache: true})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.Export{}, errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return entity.Export{}, err
+	}
+
+	return e.create(ctx, inputParam, entity.AuditActionExport)
+}
+
+func (e *export) RequestOwn(ctx context.Context) (entity.Export, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	user, err := e.user.Get(ctx, entity.UserParam{
+		ID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.Export{}, errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return entity.Export{}, err
+	}
+
+	// one queued export per user is enough, the next one can be requested once it has started
+	_, err = e.export.Get(ctx, entity.ExportParam{
+		RequestedBy:  userID,
+		SubjectType:  entity.ExportSubjectUser,
+		SubjectID:    userID,
+		ExportStatus: entity.ExportStatusPending,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err == nil {
+		return entity.Export{}, errors.NewWithCode(codes.CodeConflict, "an export is already pending")
+	} else if errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist {
+		return entity.Export{}, err
+	}
+
+	return e.create(ctx, entity.ExportInputParam{
+		SubjectType: entity.ExportSubjectUser,
+		SubjectID:   userID,
+		RangeStart:  user.CreatedAt.Time,
+		RangeEnd:    Now(),
+	}, entity.AuditActionDataExport)
+}
+
+func (e *export) GetOwn(ctx context.Context, param entity.ExportParam) (entity.Export, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	return e.Get(ctx, entity.ExportParam{
+		ID:          param.ID,
+		RequestedBy: userID,
+		SubjectType: entity.ExportSubjectUser,
+		SubjectID:   userID,
+	})
+}
+
+func (e *export) Get(ctx context.Context, param entity.ExportParam) (entity.Export, error) {
+	param.QueryOption.IsActive = true
+
+	export, err := e.export.Get(ctx, param)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return export, errors.NewWithCode(codes.CodeNotFound, "export not found")
+	} else if err != nil {
+		return export, err
+	}
+
+	export.DownloadURL = e.downloadURL(export)
+
+	return export, nil
+}
+
+func (e *export) GetList(ctx context.Context, param entity.ExportParam) ([]entity.Export, *entity.Pagination, error) {
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	exports, pg, err := e.export.GetList(ctx, param)
+	if err != nil {
+		return exports, pg, err
+	}
+
+	for i := range exports {
+		exports[i].DownloadURL = e.downloadURL(exports[i])
+	}
+
+	return exports, pg, nil
+}
+
+func (e *export) Download(ctx context.Context, token string) (entity.ExportFile, error) {
+	exportID, err := VerifyDownloadToken(e.cfg.Secret, token, Now())
+	if err != nil {
+		return entity.ExportFile{}, err
+	}
+
+	export, err := e.export.Get(ctx, entity.ExportParam{
+		ID: exportID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.ExportFile{}, errors.NewWithCode(codes.CodeNotFound, "export not found")
+	} else if err != nil {
+		return entity.ExportFile{}, err
+	} else if !e.isDownloadable(export) {
+		return entity.ExportFile{}, errors.NewWithCode(codes.CodeNotFound, "export file is no longer available")
+	}
+
+	// the first chunk is read up front so an archive removed by the retention sweep is reported before streaming
+	chunk, err := e.export.GetChunk(ctx, export.ID, 0)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.ExportFile{}, errors.NewWithCode(codes.CodeNotFound, "export file is no longer available")
+	} else if err != nil {
+		return entity.ExportFile{}, err
+	}
+
+	size := int64(-1)
+	if export.FileSize.Valid {
+		size = export.FileSize.Int64
+	}
+
+	return entity.ExportFile{
+		Name: export.FileName.String,
+		Size: size,
+		Content: &chunkReader{
+			ctx:      ctx,
+			export:   e.export,
+			exportID: export.ID,
+			index:    1,
+			buf:      chunk.Content,
+		},
+	}, nil
+}
+
+func (e *export) create(ctx context.Context, inputParam entity.ExportInputParam, action string) (entity.Export, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+	inputParam.RequestedBy = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	export, err := e.export.Create(ctx, inputParam)
+	if err != nil {
+		return export, err
+	}
+
+	err = e.audit.Create(ctx, entity.AuditInputParam{
+		Action:     action,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   null.Int64From(export.SubjectID),
+		AfterValue: export,
+	})
+	if err != nil {
+		e.log.Error(ctx, err)
+	}
+
+	return export, nil
+}
+
+// run builds every pending export, one at a time so a large export does not hold the others in memory
+func (e *export) run(ctx context.Context) error {
+	for {
+		export, err := e.export.ClaimPending(ctx, Now(), Now().Add(-e.cfg.RunTimeout))
+		if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		updateParam := entity.ExportUpdateParam{
+			ExportStatus: null.StringFrom(entity.ExportStatusDone),
+			CompletedAt:  null.TimeFrom(Now()),
+			UpdatedAt:    null.TimeFrom(Now()),
+		}
+
+		size, err := e.build(ctx, export)
+		if err != nil {
+			e.log.Error(ctx, err)
+			updateParam.ExportStatus = null.StringFrom(entity.ExportStatusFailed)
+			updateParam.ErrorMessage = null.StringFrom(truncate(err.Error(), maxErrorLength))
+
+			if err := e.export.DeleteChunks(ctx, export.ID); err != nil {
+				e.log.Error(ctx, err)
+			}
+		} else {
+			updateParam.FileName = null.StringFrom(fmt.Sprintf("%s%d.zip", fileNamePrefix, export.ID))
+			updateParam.FileSize = null.Int64From(size)
+		}
+
+		err = e.export.Update(ctx, updateParam, entity.ExportParam{ID: export.ID})
+		if err != nil {
+			return err
+		}
+	}
+
+	e.removeExpired(ctx)
+
+	return nil
+}
+
+// build stores the archive in chunks and returns its size, a download is only served once the export
+// is marked done so it never sees a partial archive
+func (e *export) build(ctx context.Context, export entity.Export) (int64, error) {
+	// a build that died mid way leaves chunks behind that would collide with the new ones
+	err := e.export.DeleteChunks(ctx, export.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	w := &chunkWriter{
+		ctx:      ctx,
+		export:   e.export,
+		exportID: export.ID,
+		size:     e.cfg.ChunkSize,
+	}
+	archive := zip.NewWriter(w)
+
+	err = e.writeArchive(ctx, archive, export)
+	if err != nil {
+		return 0, err
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return w.written, nil
+}
+
+func (e *export) writeArchive(ctx context.Context, archive *zip.Writer, export entity.Export) error {
+	user, err := e.user.Get(ctx, entity.UserParam{ID: export.SubjectID, BypassCache: true})
+	if err != nil {
+		return err
+	}
+
+	recordParam := entity.ExportRecordParam{
+		SubjectID:  export.SubjectID,
+		RangeStart: export.RangeStart.Time,
+		RangeEnd:   export.RangeEnd.Time,
+	}
+
+	notifications, err := e.getNotificationList(ctx, recordParam)
+	if err != nil {
+		return err
+	}
+
+	audits, err := e.getAuditList(ctx, recordParam)
+	if err != nil {
+		return err
+	}
+
+	// devices are the sessions the user signed in from, their push tokens are not exported
+	devices, _, err := e.notification.GetDeviceList(ctx, entity.DeviceParam{
+		UserID: export.SubjectID,
+		QueryOption: query.Option{
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name  string
+		write func(f *zip.Writer, name string) error
+	}{
+		{"profile.json", jsonWriter(Profile{ID: user.ID, Name: user.Name, Email: user.Email, CreatedAt: user.CreatedAt})},
+		{"notifications.json", jsonWriter(notifications)},
+		{"notifications.csv", csvWriter(NotificationRecords(notifications))},
+		{"audit.json", jsonWriter(audits)},
+		{"audit.csv", csvWriter(AuditRecords(audits))},
+		{"devices.json", jsonWriter(devices)},
+		{"devices.csv", csvWriter(DeviceRecords(devices))},
+	}
+
+	manifest := Manifest{
+		ExportID:    export.ID,
+		SubjectType: export.SubjectType,
+		SubjectID:   export.SubjectID,
+		RangeStart:  export.RangeStart.Time,
+		RangeEnd:    export.RangeEnd.Time,
+		GeneratedAt: Now(),
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	err = jsonWriter(manifest)(archive, "manifest.json")
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		err = f.write(archive, f.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *export) getNotificationList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Notification, error) {
+	param.Limit = e.cfg.BatchSize
+
+	result := []entity.Notification{}
+	for {
+		notifications, err := e.export.GetNotificationList(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, notifications...)
+		if len(notifications) < param.Limit {
+			return result, nil
+		}
+
+		param.AfterID = notifications[len(notifications)-1].ID
+	}
+}
+
+func (e *export) getAuditList(ctx context.Context, param entity.ExportRecordParam) ([]entity.Audit, error) {
+	param.Limit = e.cfg.BatchSize
+
+	result := []entity.Audit{}
+	for {
+		audits, err := e.export.GetAuditList(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, audits...)
+		if len(audits) < param.Limit {
+			return result, nil
+		}
+
+		param.AfterID = audits[len(audits)-1].ID
+	}
+}
+
+// removeExpired deletes archives past their retention along with chunks left behind by a failed build
+func (e *export) removeExpired(ctx context.Context) {
+	expiredBefore := Now().Add(-e.cfg.FileTTL)
+	for {
+		rowCount, err := e.export.DeleteChunksBefore(ctx, expiredBefore, e.cfg.BatchSize)
+		if err != nil {
+			e.log.Error(ctx, err)
+			return
+		} else if rowCount < int64(e.cfg.BatchSize) {
+			return
+		}
+	}
+}
+
+func (e *export) isDownloadable(export entity.Export) bool {
+	return export.ExportStatus == entity.ExportStatusDone &&
+		export.FileName.Valid &&
+		export.CompletedAt.Valid &&
+		Now().Before(export.CompletedAt.Time.Add(e.cfg.FileTTL))
+}
+
+func (e *export) downloadURL(export entity.Export) string {
+	if !e.isDownloadable(export) {
+		return ""
+	}
+
+	separator := "?"
+	if strings.Contains(e.cfg.DownloadURL, "?") {
+		separator = "&"
+	}
+
+	token := SignDownloadToken(e.cfg.Secret, export.ID, Now().Add(e.cfg.LinkTTL))
+
+	return e.cfg.DownloadURL + separator + "token=" + token
+}
+
+// chunkWriter stores what is written to it as chunks of at most size bytes
+type chunkWriter struct {
+	ctx      context.Context
+	export   exportDomain.Interface
+	exportID int64
+	size     int
+	index    int64
+	buf      []byte
+	written  int64
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		length := w.size - len(w.buf)
+		if length > len(p) {
+			length = len(p)
+		}
+
+		w.buf = append(w.buf, p[:length]...)
+		p = p[length:]
+
+		if len(w.buf) == w.size {
+			err := w.Flush()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// Flush stores the buffered bytes as the next chunk
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	err := w.export.CreateChunk(w.ctx, entity.ExportChunk{
+		ExportID:   w.exportID,
+		ChunkIndex: w.index,
+		Content:    w.buf,
+		CreatedAt:  null.TimeFrom(Now()),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.written += int64(len(w.buf))
+	w.index++
+	w.buf = w.buf[:0]
+
+	return nil
+}
+
+// chunkReader reads the chunks of an archive in order, one at a time
+type chunkReader struct {
+	ctx      context.Context
+	export   exportDomain.Interface
+	exportID int64
+	index    int64
+	buf      []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.export.GetChunk(r.ctx, r.exportID, r.index)
+		if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+			return 0, io.EOF
+		} else if err != nil {
+			return 0, err
+		}
+
+		r.buf = chunk.Content
+		r.index++
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// Manifest describes the content of an export archive
+type Manifest struct {
+	ExportID    int64     `json:"exportID"`
+	SubjectType string    `json:"subjectType"`
+	SubjectID   int64     `json:"subjectID"`
+	RangeStart  time.Time `json:"rangeStart"`
+	RangeEnd    time.Time `json:"rangeEnd"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Files       []string  `json:"files"`
+}
+
+// Profile is the part of the user record that is exported, credentials are left out
+type Profile struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt null.Time `json:"createdAt"`
+}
+
+func jsonWriter(v interface{}) func(*zip.Writer, string) error {
+	return func(archive *zip.Writer, name string) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(v)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+		}
+
+		return nil
+	}
+}
+
+func csvWriter(records [][]string) func(*zip.Writer, string) error {
+	return func(archive *zip.Writer, name string) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+		}
+
+		err = csv.NewWriter(w).WriteAll(records)
+		if err != nil {
+			return errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+		}
+
+		return nil
+	}
+}
+
+// NotificationRecords returns the notifications as csv rows, starting with the header
+func NotificationRecords(notifications []entity.Notification) [][]string {
+	records := [][]string{{"id", "actor_id", "type", "scope_type", "scope_id", "title", "body", "read_status", "read_at", "created_at"}}
+	for _, n := range notifications {
+		records = append(records, []string{
+			strconv.FormatInt(n.ID, 10),
+			formatInt64(n.ActorID),
+			n.NotificationType,
+			n.ScopeType,
+			strconv.FormatInt(n.ScopeID, 10),
+			n.Title,
+			n.Body,
+			n.ReadStatus,
+			formatTime(n.ReadAt),
+			formatTime(n.CreatedAt),
+		})
+	}
+
+	return records
+}
+
+// AuditRecords returns the audit entries as csv rows, starting with the header
+func AuditRecords(audits []entity.Audit) [][]string {
+	records := [][]string{{"id", "actor_id", "action", "target_type", "target_id", "request_id", "ip_address", "user_agent", "before", "after", "created_at"}}
+	for _, a := range audits {
+		records = append(records, []string{
+			strconv.FormatInt(a.ID, 10),
+			formatInt64(a.ActorID),
+			a.Action,
+			a.TargetType,
+			formatInt64(a.TargetID),
+			a.RequestID,
+			a.IPAddress,
+			a.UserAgent,
+			a.Before.String,
+			a.After.String,
+			formatTime(a.CreatedAt),
+		})
+	}
+
+	return records
+}
+
+// DeviceRecords returns the devices as csv rows, starting with the header
+func DeviceRecords(devices []entity.Device) [][]string {
+	records := [][]string{{"id", "platform", "status", "created_at", "deleted_at"}}
+	for _, d := range devices {
+		records = append(records, []string{
+			strconv.FormatInt(d.ID, 10),
+			d.Platform,
+			strconv.FormatInt(d.Status, 10),
+			formatTime(d.CreatedAt),
+			formatTime(d.DeletedAt),
+		})
+	}
+
+	return records
+}
+
+func formatInt64(v null.Int64) string {
+	if !v.Valid {
+		return ""
+	}
+
+	return strconv.FormatInt(v.Int64, 10)
+}
+
+func formatTime(v null.Time) string {
+	if !v.Valid {
+		return ""
+	}
+
+	return v.Time.UTC().Format(time.RFC3339)
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	return s[:length]
+}
+
+// SignDownloadToken returns a token that grants access to the export archive until expiresAt
+func SignDownloadToken(secret string, exportID int64, expiresAt time.Time) string {
+	value := strconv.FormatInt(exportID, 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return value + "." + sign(secret, value)
+}
+
+func VerifyDownloadToken(secret, token string, now time.Time) (int64, error) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 || !hmac.Equal([]byte(token[i+1:]), []byte(sign(secret, token[:i]))) {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid download token")
+	}
+
+	id, expires, ok := strings.Cut(token[:i], ".")
+	if !ok {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid download token")
+	}
+
+	exportID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid download token")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid download token")
+	} else if now.Unix() >= expiresAt {
+		return 0, errors.NewWithCode(codes.CodeUnauthorized, "download link has expired")
+	}
+
+	return exportID, nil
+}
+
+func sign(secret, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
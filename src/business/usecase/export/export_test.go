@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	exportDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/export"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DownloadToken(t *testing.T) {
+	now := time.Date(2024, 6, 21, 10, 0, 0, 0, time.UTC)
+	token := SignDownloadToken("secret", 42, now.Add(time.Hour))
+
+	exportID, err := VerifyDownloadToken("secret", token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), exportID)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "wrong secret", token: SignDownloadToken("other", 42, now.Add(time.Hour))},
+		{name: "tampered export", token: "43" + token[2:]},
+		{name: "missing signature", token: "42"},
+		{name: "missing expiry", token: "42." + sign("secret", "42")},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyDownloadToken("secret", tt.token, now)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := VerifyDownloadToken("secret", token, now.Add(time.Hour))
+		assert.Error(t, err)
+	})
+}
+
+func Test_NotificationRecords(t *testing.T) {
+	records := NotificationRecords([]entity.Notification{{
+		ID:               1,
+		NotificationType: entity.NotificationTypeMention,
+		ScopeType:        "workspace",
+		ScopeID:          7,
+		Title:            "general",
+		Body:             "hello, world",
+		ReadStatus:       entity.NotificationReadStatusUnread,
+		CreatedAt:        null.TimeFrom(time.Date(2024, 6, 21, 10, 0, 0, 0, time.UTC)),
+	}})
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, len(records[0]), len(records[1]))
+	assert.Equal(t, []string{"1", "", entity.NotificationTypeMention, "workspace", "7", "general", "hello, world", entity.NotificationReadStatusUnread, "", "2024-06-21T10:00:00Z"}, records[1])
+}
+
+type mockExportDomain struct {
+	exportDomain.Interface
+	chunks [][]byte
+}
+
+func (m *mockExportDomain) CreateChunk(ctx context.Context, chunk entity.ExportChunk) error {
+	m.chunks = append(m.chunks, append([]byte{}, chunk.Content...))
+	return nil
+}
+
+func (m *mockExportDomain) GetChunk(ctx context.Context, exportID, chunkIndex int64) (entity.ExportChunk, error) {
+	if chunkIndex >= int64(len(m.chunks)) {
+		return entity.ExportChunk{}, errors.NewWithCode(codes.CodeSQLRecordDoesNotExist, "no chunk")
+	}
+
+	return entity.ExportChunk{ExportID: exportID, ChunkIndex: chunkIndex, Content: m.chunks[chunkIndex]}, nil
+}
+
+func Test_chunk(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 25)
+	domain := &mockExportDomain{}
+
+	w := &chunkWriter{ctx: context.Background(), export: domain, exportID: 1, size: 64}
+	for _, part := range [][]byte{data[:10], data[10:200], data[200:]} {
+		_, err := w.Write(part)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, int64(len(data)), w.written)
+	assert.Len(t, domain.chunks, 4)
+	for _, chunk := range domain.chunks[:3] {
+		assert.Len(t, chunk, 64)
+	}
+
+	content, err := io.ReadAll(&chunkReader{ctx: context.Background(), export: domain, exportID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+}
@@ -0,0 +1,164 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/redis"
+	idempotencyDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/idempotency"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+)
+
+var Now = time.Now
+
+const (
+	defaultTTL     = 24 * time.Hour
+	defaultLockTTL = 30 * time.Second
+
+	lockKey = "boilerplate:idempotency:lock:%s"
+)
+
+type Interface interface {
+	// Lock rejects a request while another one with the same key is still in flight. The lock is
+	// refreshed until it is released, so a handler still running after its request timed out keeps it
+	Lock(ctx context.Context, param entity.IdempotencyParam) (locker.Lock, error)
+	// Get returns the stored response of an earlier request with the same key, or a not found error
+	Get(ctx context.Context, param entity.IdempotencyParam) (entity.IdempotentResponse, error)
+	Save(ctx context.Context, param entity.IdempotencyParam, response entity.IdempotentResponse) error
+}
+
+type idempotency struct {
+	idempotency idempotencyDomain.Interface
+	locker      locker.Interface
+	cfg         config.IdempotencyConfig
+}
+
+type InitParam struct {
+	IdempotencyDomain idempotencyDomain.Interface
+	Locker            locker.Interface
+	Config            config.IdempotencyConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultLockTTL
+	}
+
+	return &idempotency{
+		idempotency: param.IdempotencyDomain,
+		locker:      param.Locker,
+		cfg:         cfg,
+	}
+}
+
+func (i *idempotency) Lock(ctx context.Context, param entity.IdempotencyParam) (locker.Lock, error) {
+	lock, err := i.locker.Obtain(ctx, fmt.Sprintf(lockKey, Key(ctx, param)), i.cfg.LockTTL)
+	if errors.Is(err, locker.ErrNotObtained) {
+		return nil, errors.NewWithCode(codes.CodeConflict, "a request with this idempotency key is still in progress")
+	} else if err != nil {
+		return nil, errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	// the refresh outlives the request context, the handler may keep running after a timeout
+	refreshCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	held := &heldLock{
+		Lock:    lock,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	go held.refresh(refreshCtx, i.cfg.LockTTL)
+
+	return held, nil
+}
+
+func (i *idempotency) Get(ctx context.Context, param entity.IdempotencyParam) (entity.IdempotentResponse, error) {
+	response, err := i.idempotency.Get(ctx, Key(ctx, param))
+	if errors.Is(err, redis.Nil) {
+		return response, errors.NewWithCode(codes.CodeNotFound, "idempotent response not found")
+	} else if err != nil {
+		return response, err
+	}
+
+	if response.Fingerprint != param.Fingerprint {
+		return response, errors.NewWithCode(codes.CodeBadRequest, "idempotency key was already used with a different request body")
+	}
+
+	return response, nil
+}
+
+func (i *idempotency) Save(ctx context.Context, param entity.IdempotencyParam, response entity.IdempotentResponse) error {
+	response.Fingerprint = param.Fingerprint
+	response.CreatedAt = Now()
+
+	return i.idempotency.Set(ctx, Key(ctx, param), response, i.cfg.TTL)
+}
+
+// IsReplayable reports whether a response with the status is final for its key. Successes and client
+// errors are, except auth failures, timeouts, conflicts and rate limits which can pass on a retry; server
+// errors are always left for the client to retry
+func IsReplayable(statusCode int) bool {
+	switch {
+	case statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices:
+		return true
+	case statusCode < http.StatusBadRequest || statusCode >= http.StatusInternalServerError:
+		return false
+	}
+
+	return !slices.Contains([]int{http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests}, statusCode)
+}
+
+// Key scopes the client key to the current user and route, hashed so client input never ends up in redis keys
+func Key(ctx context.Context, param entity.IdempotencyParam) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s:%s:%s", appcontext.GetUserId(ctx), param.Method, param.Path, param.Key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// heldLock refreshes the key lock until it is released
+type heldLock struct {
+	locker.Lock
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+func (h *heldLock) refresh(ctx context.Context, ttl time.Duration) {
+	defer close(h.stopped)
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// a lost lock cannot be taken back, the retry it lets through is answered from the saved
+		// response once this request saves it
+		if err := h.Lock.Refresh(ctx, ttl); errors.Is(err, locker.ErrNotObtained) {
+			return
+		}
+	}
+}
+
+func (h *heldLock) Release(ctx context.Context) error {
+	h.cancel()
+	<-h.stopped
+
+	return h.Lock.Release(ctx)
+}
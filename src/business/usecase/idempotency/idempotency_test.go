@@ -0,0 +1,106 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLock struct {
+	refreshed atomic.Int64
+	released  atomic.Bool
+}
+
+func (f *fakeLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	f.refreshed.Add(1)
+	return nil
+}
+
+func (f *fakeLock) Release(ctx context.Context) error {
+	f.released.Store(true)
+	return nil
+}
+
+type fakeLocker struct {
+	lock *fakeLock
+}
+
+func (f *fakeLocker) Obtain(ctx context.Context, key string, ttl time.Duration) (locker.Lock, error) {
+	return f.lock, nil
+}
+
+func Test_Key(t *testing.T) {
+	ctx := appcontext.SetUserId(context.Background(), 1)
+	param := entity.IdempotencyParam{Key: "abc", Method: "POST", Path: "/v1/webhooks", Fingerprint: "x"}
+
+	// the body fingerprint is not part of the key, a reused key with another body must be found and rejected
+	other := param
+	other.Fingerprint = "y"
+	assert.Equal(t, Key(ctx, param), Key(ctx, other))
+
+	otherPath := param
+	otherPath.Path = "/v1/relations"
+	assert.NotEqual(t, Key(ctx, param), Key(ctx, otherPath))
+
+	otherUser := appcontext.SetUserId(context.Background(), 2)
+	assert.NotEqual(t, Key(ctx, param), Key(otherUser, param))
+}
+
+func Test_IsReplayable(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{statusCode: http.StatusOK, want: true},
+		{statusCode: http.StatusCreated, want: true},
+		{statusCode: http.StatusNoContent, want: true},
+		{statusCode: http.StatusFound, want: false},
+		{statusCode: http.StatusBadRequest, want: true},
+		{statusCode: http.StatusNotFound, want: true},
+		{statusCode: http.StatusUnprocessableEntity, want: true},
+		{statusCode: http.StatusUnauthorized, want: false},
+		{statusCode: http.StatusForbidden, want: false},
+		{statusCode: http.StatusRequestTimeout, want: false},
+		{statusCode: http.StatusConflict, want: false},
+		{statusCode: http.StatusTooManyRequests, want: false},
+		{statusCode: http.StatusInternalServerError, want: false},
+		{statusCode: http.StatusServiceUnavailable, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			assert.Equal(t, tt.want, IsReplayable(tt.statusCode))
+		})
+	}
+}
+
+func Test_Lock_RefreshedUntilReleased(t *testing.T) {
+	lock := &fakeLock{}
+	i := Init(InitParam{
+		Locker: &fakeLocker{lock: lock},
+		Config: config.IdempotencyConfig{LockTTL: 30 * time.Millisecond},
+	})
+
+	// the request context is done long before the handler finishes, the lock must still be kept
+	ctx, cancel := context.WithCancel(appcontext.SetUserId(context.Background(), 1))
+	held, err := i.Lock(ctx, entity.IdempotencyParam{Key: "abc", Method: "POST", Path: "/v1/webhooks"})
+	assert.NoError(t, err)
+	cancel()
+
+	assert.Eventually(t, func() bool { return lock.refreshed.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, held.Release(context.Background()))
+	assert.True(t, lock.released.Load())
+
+	refreshed := lock.refreshed.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, refreshed, lock.refreshed.Load())
+}
@@ -0,0 +1,186 @@
+package legalhold
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	legalholdDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/legalhold"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	workspaceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+var Now = time.Now
+
+const maxReasonLength = 255
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error)
+	GetList(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error)
+	Release(ctx context.Context, param entity.LegalHoldParam) error
+	IsHeld(ctx context.Context, scopeType string, scopeID int64) (bool, error)
+	// EnsureNotHeld returns a forbidden error when the scope is on legal hold, deletes must call it first
+	EnsureNotHeld(ctx context.Context, scopeType string, scopeID int64) error
+}
+
+type legalhold struct {
+	legalhold legalholdDomain.Interface
+	user      userDomain.Interface
+	workspace workspaceDomain.Interface
+	audit     auditDomain.Interface
+	log       log.Interface
+}
+
+type InitParam struct {
+	LegalHoldDomain legalholdDomain.Interface
+	UserDomain      userDomain.Interface
+	WorkspaceDomain workspaceDomain.Interface
+	AuditDomain     auditDomain.Interface
+	Log             log.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &legalhold{
+		legalhold: param.LegalHoldDomain,
+		user:      param.UserDomain,
+		workspace: param.WorkspaceDomain,
+		audit:     param.AuditDomain,
+		log:       param.Log,
+	}
+}
+
+func (l *legalhold) Create(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error) {
+	inputParam.Reason = strings.TrimSpace(inputParam.Reason)
+	if inputParam.Reason == "" {
+		return entity.LegalHold{}, errors.NewWithCode(codes.CodeBadRequest, "legal hold reason is required")
+	} else if len(inputParam.Reason) > maxReasonLength {
+		return entity.LegalHold{}, errors.NewWithCode(codes.CodeBadRequest, "legal hold reason must be at most %d characters", maxReasonLength)
+	}
+
+	err := l.ensureScopeExists(ctx, inputParam.ScopeType, inputParam.ScopeID)
+	if err != nil {
+		return entity.LegalHold{}, err
+	}
+
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.Itoa(appcontext.GetUserId(ctx)))
+
+	legalHold, err := l.legalhold.Create(ctx, inputParam)
+	if err != nil {
+		return legalHold, err
+	}
+
+	l.recordAudit(ctx, entity.AuditActionLegalHold, legalHold.ScopeType, legalHold.ScopeID, nil, legalHold)
+
+	return legalHold, nil
+}
+
+func (l *legalhold) GetList(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error) {
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return l.legalhold.GetList(ctx, param)
+}
+
+func (l *legalhold) Release(ctx context.Context, param entity.LegalHoldParam) error {
+	legalHold, err := l.legalhold.Get(ctx, entity.LegalHoldParam{
+		ID: param.ID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "legal hold not found")
+	} else if err != nil {
+		return err
+	}
+
+	err = l.legalhold.Update(ctx, entity.LegalHoldUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(strconv.Itoa(appcontext.GetUserId(ctx))),
+	}, entity.LegalHoldParam{
+		ID: legalHold.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.recordAudit(ctx, entity.AuditActionHoldRelease, legalHold.ScopeType, legalHold.ScopeID, legalHold, nil)
+
+	return nil
+}
+
+func (l *legalhold) IsHeld(ctx context.Context, scopeType string, scopeID int64) (bool, error) {
+	legalHolds, _, err := l.legalhold.GetList(ctx, entity.LegalHoldParam{
+		ScopeType: scopeType,
+		ScopeID:   scopeID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(legalHolds) > 0, nil
+}
+
+func (l *legalhold) EnsureNotHeld(ctx context.Context, scopeType string, scopeID int64) error {
+	isHeld, err := l.IsHeld(ctx, scopeType, scopeID)
+	if err != nil {
+		return err
+	} else if isHeld {
+		return errors.NewWithCode(codes.CodeForbidden, "%s is on legal hold", scopeType)
+	}
+
+	return nil
+}
+
+func (l *legalhold) ensureScopeExists(ctx context.Context, scopeType string, scopeID int64) error {
+	var err error
+
+	switch scopeType {
+	case entity.LegalHoldScopeUser:
+		_, err = l.user.Get(ctx, entity.UserParam{ID: scopeID, BypassCache: true})
+	case entity.LegalHoldScopeWorkspace:
+		_, err = l.workspace.Get(ctx, entity.WorkspaceParam{ID: scopeID, BypassCache: true})
+	default:
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid legal hold scope %s", scopeType)
+	}
+
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "%s not found", scopeType)
+	}
+
+	return err
+}
+
+// recordAudit writes the audit trail without failing the action it describes
+func (l *legalhold) recordAudit(ctx context.Context, action, scopeType string, scopeID int64, before, after interface{}) {
+	targetType := entity.AuditTargetUser
+	if scopeType == entity.LegalHoldScopeWorkspace {
+		targetType = entity.AuditTargetWorkspace
+	}
+
+	err := l.audit.Create(ctx, entity.AuditInputParam{
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    null.Int64From(scopeID),
+		BeforeValue: before,
+		AfterValue:  after,
+	})
+	if err != nil {
+		l.log.Error(ctx, err)
+	}
+}
@@ -0,0 +1,543 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/query"
+	notificationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	preferenceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/preference"
+	presenceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/presence"
+	relationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	workspaceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/push"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var Now = time.Now
+
+const (
+	defaultOnlineWindow    = 5 * time.Minute
+	defaultPushConcurrency = 32
+	defaultJobInterval     = time.Second
+	defaultBatchSize       = 100
+	defaultMaxAttempt      = 5
+	defaultBackoffInterval = time.Second
+)
+
+type Interface interface {
+	// Notify stores an in-app notification for every recipient that should hear about the request
+	// and pushes it to their devices when they are offline
+	Notify(ctx context.Context, req entity.NotificationRequest) error
+	// Dispatch queues the fan-out of relayed outbox events, it is subscribed to the outbox
+	Dispatch(ctx context.Context, event entity.Event) error
+	GetList(ctx context.Context, param entity.NotificationParam) ([]entity.Notification, *entity.Pagination, error)
+	MarkRead(ctx context.Context, param entity.NotificationParam) error
+	RegisterDevice(ctx context.Context, inputParam entity.DeviceInputParam) (entity.Device, error)
+	DeleteDevice(ctx context.Context, param entity.DeviceParam) error
+}
+
+type notification struct {
+	notification notificationDomain.Interface
+	relation     relationDomain.Interface
+	presence     presenceDomain.Interface
+	preference   preferenceDomain.Interface
+	workspace    workspaceDomain.Interface
+	user         userDomain.Interface
+	push         push.Interface
+	log          log.Interface
+	json         parser.JSONInterface
+	cfg          config.NotificationConfig
+	onlineWindow time.Duration
+	// pushSem bounds the pushes in flight, pushes beyond it are dropped since the in-app feed has them
+	pushSem chan struct{}
+}
+
+type InitParam struct {
+	NotificationDomain notificationDomain.Interface
+	RelationDomain     relationDomain.Interface
+	PresenceDomain     presenceDomain.Interface
+	PreferenceDomain   preferenceDomain.Interface
+	WorkspaceDomain    workspaceDomain.Interface
+	UserDomain         userDomain.Interface
+	Push               push.Interface
+	Log                log.Interface
+	Json               parser.JSONInterface
+	Scheduler          scheduler.Interface
+	Presence           config.PresenceConfig
+	Config             config.NotificationConfig
+}
+
+func Init(param InitParam) Interface {
+	onlineWindow := param.Presence.OnlineWindow
+	if onlineWindow <= 0 {
+		onlineWindow = defaultOnlineWindow
+	}
+
+	cfg := param.Config
+	if cfg.PushConcurrency < 1 {
+		cfg.PushConcurrency = defaultPushConcurrency
+	}
+
+	if cfg.JobInterval <= 0 {
+		cfg.JobInterval = defaultJobInterval
+	}
+
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = defaultBatchSize
+	}
+
+	if cfg.MaxAttempt < 1 {
+		cfg.MaxAttempt = defaultMaxAttempt
+	}
+
+	if cfg.BackoffInterval <= 0 {
+		cfg.BackoffInterval = defaultBackoffInterval
+	}
+
+	n := &notification{
+		notification: param.NotificationDomain,
+		relation:     param.RelationDomain,
+		presence:     param.PresenceDomain,
+		preference:   param.PreferenceDomain,
+		workspace:    param.WorkspaceDomain,
+		user:         param.UserDomain,
+		push:         param.Push,
+		log:          param.Log,
+		json:         param.Json,
+		cfg:          cfg,
+		onlineWindow: onlineWindow,
+		pushSem:      make(chan struct{}, cfg.PushConcurrency),
+	}
+
+	err := param.Scheduler.Register("notification-fanout", scheduler.Every(cfg.JobInterval), n.processDue)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return n
+}
+
+// Notify returns the first error it met after trying every recipient, a retry skips the recipients
+// already notified of req.EventID
+func (n *notification) Notify(ctx context.Context, req entity.NotificationRequest) error {
+	notified := map[int64]bool{}
+	var firstErr error
+
+	for _, userID := range req.RecipientIDs {
+		if userID == req.ActorID || notified[userID] {
+			continue
+		}
+		notified[userID] = true
+
+		isMentioned := slices.Contains(req.MentionedIDs, userID)
+
+		shouldNotify, err := n.shouldNotify(ctx, userID, req, isMentioned)
+		if err != nil {
+			n.log.Error(ctx, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		} else if !shouldNotify {
+			continue
+		}
+
+		notificationType := req.NotificationType
+		if isMentioned {
+			notificationType = entity.NotificationTypeMention
+		}
+
+		inputParam := entity.NotificationInputParam{
+			UserID:           userID,
+			ActorID:          null.Int64From(req.ActorID),
+			NotificationType: notificationType,
+			ScopeType:        req.ScopeType,
+			ScopeID:          req.ScopeID,
+			Title:            req.Title,
+			Body:             req.Body,
+			ReadStatus:       entity.NotificationReadStatusUnread,
+			CreatedAt:        null.TimeFrom(Now()),
+			CreatedBy:        null.StringFrom(strconv.FormatInt(req.ActorID, 10)),
+		}
+		if req.EventID != "" {
+			inputParam.EventID = null.StringFrom(req.EventID)
+		}
+
+		_, err = n.notification.Create(ctx, inputParam)
+		if errors.GetCode(err) == codes.CodeSQLUniqueConstraint {
+			// notified by an earlier run of the same event, including its push
+			continue
+		} else if err != nil {
+			n.log.Error(ctx, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		// online users already see the in-app feed, push is only for users who are away
+		lastSeen, err := n.presence.GetLastSeen(ctx, userID)
+		if err != nil {
+			n.log.Error(ctx, fmt.Sprintf(entity.ErrorRedis, err.Error()))
+		} else if lastSeen.Valid && Now().Sub(lastSeen.Time) <= n.onlineWindow {
+			continue
+		}
+
+		preference, err := n.getPreference(ctx, userID)
+		if err != nil {
+			n.log.Error(ctx, err)
+			continue
+		} else if !preference.IsPushAllowed(Now()) {
+			continue
+		}
+
+		select {
+		case n.pushSem <- struct{}{}:
+			go func(userID int64) {
+				defer func() { <-n.pushSem }()
+				n.sendPush(context.WithoutCancel(ctx), userID, req)
+			}(userID)
+		default:
+			n.log.Warn(ctx, fmt.Sprintf("push to user %v dropped, %v pushes already in flight", userID, cap(n.pushSem)))
+		}
+	}
+
+	return firstErr
+}
+
+// Dispatch only queues the event, the fan-out runs in notification-fanout so the relay is not held
+// up by the recipients and their pushes
+func (n *notification) Dispatch(ctx context.Context, event entity.Event) error {
+	// a new member is announced to the rest of the workspace, the actor is the member who joined
+	if event.Type != entity.EventMemberJoined || event.ScopeType != entity.WebhookScopeWorkspace {
+		return nil
+	}
+
+	payload, err := n.json.Marshal(event)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	return n.notification.CreateJob(ctx, entity.NotificationJobInputParam{
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       string(payload),
+		JobStatus:     entity.NotificationJobStatusPending,
+		NextAttemptAt: null.TimeFrom(Now()),
+		CreatedAt:     null.TimeFrom(Now()),
+	})
+}
+
+func (n *notification) GetList(ctx context.Context, param entity.NotificationParam) ([]entity.Notification, *entity.Pagination, error) {
+	param.UserID = int64(appcontext.GetUserId(ctx))
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return n.notification.GetList(ctx, param)
+}
+
+func (n *notification) MarkRead(ctx context.Context, param entity.NotificationParam) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	notification, err := n.notification.Get(ctx, entity.NotificationParam{
+		ID:     param.ID,
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "notification not found")
+	} else if err != nil {
+		return err
+	}
+
+	if notification.ReadStatus == entity.NotificationReadStatusRead {
+		return nil
+	}
+
+	return n.notification.Update(ctx, entity.NotificationUpdateParam{
+		ReadStatus: null.StringFrom(entity.NotificationReadStatusRead),
+		ReadAt:     null.TimeFrom(Now()),
+		UpdatedAt:  null.TimeFrom(Now()),
+		UpdatedBy:  null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.NotificationParam{
+		ID:     notification.ID,
+		UserID: userID,
+	})
+}
+
+func (n *notification) RegisterDevice(ctx context.Context, inputParam entity.DeviceInputParam) (entity.Device, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if !slices.Contains([]string{entity.DevicePlatformAndroid, entity.DevicePlatformIOS, entity.DevicePlatformWeb}, inputParam.Platform) {
+		return entity.Device{}, errors.NewWithCode(codes.CodeBadRequest, "invalid platform %s", inputParam.Platform)
+	}
+
+	inputParam.Token = strings.TrimSpace(inputParam.Token)
+	if inputParam.Token == "" {
+		return entity.Device{}, errors.NewWithCode(codes.CodeBadRequest, "device token is required")
+	}
+
+	// a token belongs to one physical device, it only moves to another account once the previous
+	// account unregistered it, otherwise anyone holding the token could take over its pushes
+	device, err := n.notification.GetDevice(ctx, entity.DeviceParam{
+		Token: inputParam.Token,
+	})
+	switch {
+	case err == nil && device.Status == 1 && device.UserID != userID:
+		return entity.Device{}, errors.NewWithCode(codes.CodeConflict, "device token is registered to another account")
+	case err == nil:
+		device.UserID = userID
+		device.Platform = inputParam.Platform
+		err = n.notification.RestoreDevice(ctx, device, Now(), strconv.FormatInt(userID, 10))
+		if err != nil {
+			return device, err
+		}
+
+		device.Status = 1
+		device.DeletedAt = null.Time{}
+		device.DeletedBy = null.String{}
+		return device, nil
+	case errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist:
+		return device, err
+	}
+
+	inputParam.UserID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	return n.notification.CreateDevice(ctx, inputParam)
+}
+
+func (n *notification) DeleteDevice(ctx context.Context, param entity.DeviceParam) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	device, err := n.notification.GetDevice(ctx, entity.DeviceParam{
+		ID:     param.ID,
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "device not found")
+	} else if err != nil {
+		return err
+	}
+
+	return n.notification.UpdateDevice(ctx, entity.DeviceUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.DeviceParam{
+		ID: device.ID,
+	})
+}
+
+// processDue fans out the jobs that are due, a failed job is retried on a later run after a backoff
+// until the attempts run out
+func (n *notification) processDue(ctx context.Context) error {
+	jobs, err := n.notification.GetDueJobList(ctx, Now(), n.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		n.process(ctx, job)
+	}
+
+	return nil
+}
+
+func (n *notification) process(ctx context.Context, job entity.NotificationJob) {
+	attempt := job.Attempt + 1
+	updateParam := entity.NotificationJobUpdateParam{
+		Attempt:   null.Int64From(attempt),
+		UpdatedAt: null.TimeFrom(Now()),
+	}
+
+	event := entity.Event{}
+	err := n.json.Unmarshal([]byte(job.Payload), &event)
+	if err != nil {
+		err = errors.NewWithCode(codes.CodeMarshal, err.Error())
+	} else {
+		err = n.fanOut(ctx, event)
+	}
+
+	switch {
+	case err == nil:
+		updateParam.JobStatus = null.StringFrom(entity.NotificationJobStatusDone)
+	case attempt >= int64(n.cfg.MaxAttempt):
+		n.log.Error(ctx, fmt.Sprintf("notification job %v failed after %v attempts: %s", job.ID, attempt, err.Error()))
+		updateParam.JobStatus = null.StringFrom(entity.NotificationJobStatusFailed)
+		updateParam.ErrorMessage = null.StringFrom(err.Error())
+	default:
+		updateParam.NextAttemptAt = null.TimeFrom(Now().Add(n.cfg.BackoffInterval * time.Duration(1<<(attempt-1))))
+		updateParam.ErrorMessage = null.StringFrom(err.Error())
+	}
+
+	err = n.notification.UpdateJob(ctx, updateParam, entity.NotificationJobParam{ID: job.ID})
+	if err != nil {
+		n.log.Error(ctx, err)
+	}
+}
+
+// fanOut notifies the workspace of the member who joined in event
+func (n *notification) fanOut(ctx context.Context, event entity.Event) error {
+	members, _, err := n.workspace.GetMemberList(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: event.ScopeID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	user, err := n.user.Get(ctx, entity.UserParam{
+		ID: event.ActorID,
+	})
+	if err != nil {
+		return err
+	}
+
+	recipientIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		recipientIDs = append(recipientIDs, member.UserID)
+	}
+
+	return n.Notify(ctx, entity.NotificationRequest{
+		EventID:          event.ID,
+		ActorID:          event.ActorID,
+		NotificationType: entity.NotificationTypeMember,
+		ScopeType:        event.ScopeType,
+		ScopeID:          event.ScopeID,
+		Title:            "New workspace member",
+		Body:             fmt.Sprintf("%s joined the workspace", user.Name),
+		RecipientIDs:     recipientIDs,
+	})
+}
+
+func (n *notification) shouldNotify(ctx context.Context, userID int64, req entity.NotificationRequest, isMentioned bool) (bool, error) {
+	isBlocked, err := n.relation.IsBlocked(ctx, userID, req.ActorID)
+	if err != nil || isBlocked {
+		return false, err
+	}
+
+	level, err := n.getLevel(ctx, userID, req.ScopeType, req.ScopeID)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case level == entity.NotificationLevelNone:
+		return false, nil
+	case level == entity.NotificationLevelMentions && !isMentioned:
+		return false, nil
+	case isMentioned:
+		// a mention gets through a muted user
+		return true, nil
+	}
+
+	isMuted, err := n.relation.IsMuted(ctx, userID, entity.RelationTargetUser, req.ActorID)
+	if err != nil || isMuted {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getLevel returns the notification level of the user for a scope, defaulting to all
+func (n *notification) getLevel(ctx context.Context, userID int64, scopeType string, scopeID int64) (string, error) {
+	scope, err := n.preference.GetScope(ctx, entity.ScopePreferenceParam{
+		UserID:    userID,
+		ScopeType: scopeType,
+		ScopeID:   scopeID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.NotificationLevelAll, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return scope.NotificationLevel, nil
+}
+
+func (n *notification) getPreference(ctx context.Context, userID int64) (entity.Preference, error) {
+	preference, err := n.preference.Get(ctx, entity.PreferenceParam{
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.DefaultPreference(userID), nil
+	} else if err != nil {
+		return preference, err
+	}
+
+	return preference, nil
+}
+
+func (n *notification) sendPush(ctx context.Context, userID int64, req entity.NotificationRequest) {
+	devices, _, err := n.notification.GetDeviceList(ctx, entity.DeviceParam{
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		n.log.Error(ctx, err)
+		return
+	}
+
+	for _, device := range devices {
+		err := n.push.Send(ctx, push.Message{
+			Token:    device.Token,
+			Platform: device.Platform,
+			Title:    req.Title,
+			Body:     req.Body,
+			Data: map[string]string{
+				"scopeType": req.ScopeType,
+				"scopeID":   strconv.FormatInt(req.ScopeID, 10),
+			},
+		})
+		if errors.Is(err, push.ErrInvalidToken) {
+			n.deactivateDevice(ctx, device)
+		} else if err != nil {
+			n.log.Error(ctx, err)
+		}
+	}
+}
+
+func (n *notification) deactivateDevice(ctx context.Context, device entity.Device) {
+	err := n.notification.UpdateDevice(ctx, entity.DeviceUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+	}, entity.DeviceParam{
+		ID: device.ID,
+	})
+	if err != nil {
+		n.log.Error(ctx, err)
+	}
+}
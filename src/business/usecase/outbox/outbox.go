@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	outboxDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+const (
+	defaultInterval        = time.Second
+	defaultBatchSize       = 100
+	defaultMaxAttempt      = 10
+	defaultBackoffInterval = time.Second
+)
+
+type Interface interface {
+	// Subscribe registers a handler on the event bus, handlers are called in registration order for every event
+	Subscribe(handler outboxDomain.PublishFunc)
+	// Relay publishes every due outbox row and returns how many were handled
+	Relay(ctx context.Context) (int, error)
+}
+
+type outbox struct {
+	outbox   outboxDomain.Interface
+	log      log.Interface
+	cfg      config.OutboxConfig
+	handlers []outboxDomain.PublishFunc
+}
+
+type InitParam struct {
+	OutboxDomain outboxDomain.Interface
+	Log          log.Interface
+	Scheduler    scheduler.Interface
+	Config       config.OutboxConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = defaultBatchSize
+	}
+
+	if cfg.MaxAttempt < 1 {
+		cfg.MaxAttempt = defaultMaxAttempt
+	}
+
+	if cfg.BackoffInterval <= 0 {
+		cfg.BackoffInterval = defaultBackoffInterval
+	}
+
+	o := &outbox{
+		outbox: param.OutboxDomain,
+		log:    param.Log,
+		cfg:    cfg,
+	}
+
+	err := param.Scheduler.Register("outbox-relay", scheduler.Every(cfg.Interval), o.relay)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return o
+}
+
+func (o *outbox) Subscribe(handler outboxDomain.PublishFunc) {
+	o.handlers = append(o.handlers, handler)
+}
+
+func (o *outbox) Relay(ctx context.Context) (int, error) {
+	param := entity.OutboxPublishParam{
+		Limit:           o.cfg.BatchSize,
+		MaxAttempt:      o.cfg.MaxAttempt,
+		BackoffInterval: o.cfg.BackoffInterval,
+	}
+
+	total := 0
+	for {
+		// failed rows are rescheduled with a backoff, so a full batch always means more rows are due
+		handled, err := o.outbox.PublishPending(ctx, param, o.publish)
+		total += handled
+		if err != nil || handled < o.cfg.BatchSize {
+			return total, err
+		}
+	}
+}
+
+func (o *outbox) relay(ctx context.Context) error {
+	_, err := o.Relay(ctx)
+	return err
+}
+
+func (o *outbox) publish(ctx context.Context, event entity.Event) error {
+	for _, handler := range o.handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package preference
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	preferenceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/workspace"
+)
+
+var Now = time.Now
+
+type Interface interface {
+	Get(ctx context.Context) (entity.Preference, error)
+	Update(ctx context.Context, inputParam entity.PreferenceInputParam) (entity.Preference, error)
+	GetScopeList(ctx context.Context, param entity.ScopePreferenceParam) ([]entity.ScopePreference, *entity.Pagination, error)
+	UpdateScope(ctx context.Context, inputParam entity.ScopePreferenceInputParam) error
+	// IsDigestAllowed reports whether the user is subscribed to the email digest
+	IsDigestAllowed(ctx context.Context, userID int64) (bool, error)
+	// SetEmailDigest subscribes or unsubscribes the user from the email digest
+	SetEmailDigest(ctx context.Context, userID int64, enabled bool) error
+}
+
+type preference struct {
+	preference preferenceDomain.Interface
+	workspace  workspace.Interface
+}
+
+type InitParam struct {
+	PreferenceDomain preferenceDomain.Interface
+	Workspace        workspace.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &preference{
+		preference: param.PreferenceDomain,
+		workspace:  param.Workspace,
+	}
+}
+
+func (p *preference) Get(ctx context.Context) (entity.Preference, error) {
+	return p.get(ctx, int64(appcontext.GetUserId(ctx)))
+}
+
+func (p *preference) Update(ctx context.Context, inputParam entity.PreferenceInputParam) (entity.Preference, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if inputParam.TimeZone == "" {
+		inputParam.TimeZone = entity.DefaultTimeZone
+	}
+
+	if _, err := time.LoadLocation(inputParam.TimeZone); err != nil {
+		return entity.Preference{}, errors.NewWithCode(codes.CodeBadRequest, "invalid time zone %s", inputParam.TimeZone)
+	}
+
+	if (inputParam.QuietHoursStart == "") != (inputParam.QuietHoursEnd == "") {
+		return entity.Preference{}, errors.NewWithCode(codes.CodeBadRequest, "quiet hours need both start and end")
+	}
+
+	for _, clock := range []string{inputParam.QuietHoursStart, inputParam.QuietHoursEnd} {
+		if _, err := time.Parse(entity.PreferenceClockLayout, clock); clock != "" && err != nil {
+			return entity.Preference{}, errors.NewWithCode(codes.CodeBadRequest, "quiet hours must be formatted as HH:MM")
+		}
+	}
+
+	inputParam.UserID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	err := p.preference.Upsert(ctx, inputParam)
+	if err != nil {
+		return entity.Preference{}, err
+	}
+
+	if inputParam.EmailDigest != nil {
+		err = p.SetEmailDigest(ctx, userID, *inputParam.EmailDigest)
+		if err != nil {
+			return entity.Preference{}, err
+		}
+	}
+
+	return p.get(ctx, userID)
+}
+
+func (p *preference) GetScopeList(ctx context.Context, param entity.ScopePreferenceParam) ([]entity.ScopePreference, *entity.Pagination, error) {
+	param.UserID = int64(appcontext.GetUserId(ctx))
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return p.preference.GetScopeList(ctx, param)
+}
+
+func (p *preference) UpdateScope(ctx context.Context, inputParam entity.ScopePreferenceInputParam) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if !slices.Contains([]string{entity.NotificationLevelAll, entity.NotificationLevelMentions, entity.NotificationLevelNone}, inputParam.NotificationLevel) {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid notification level %s", inputParam.NotificationLevel)
+	}
+
+	if inputParam.ScopeType != entity.ScopePreferenceTypeWorkspace {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid scope type %s", inputParam.ScopeType)
+	}
+
+	if inputParam.ScopeID < 1 {
+		return errors.NewWithCode(codes.CodeBadRequest, "scope id is required")
+	}
+
+	err := p.workspace.EnsureMember(ctx, inputParam.ScopeID, userID)
+	if err != nil {
+		return err
+	}
+
+	inputParam.UserID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	return p.preference.UpsertScope(ctx, inputParam)
+}
+
+func (p *preference) IsDigestAllowed(ctx context.Context, userID int64) (bool, error) {
+	preference, err := p.get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return preference.EmailDigest, nil
+}
+
+func (p *preference) SetEmailDigest(ctx context.Context, userID int64, enabled bool) error {
+	return p.preference.UpsertDigest(ctx, entity.PreferenceDigestInputParam{
+		UserID:      userID,
+		EmailDigest: enabled,
+		CreatedAt:   null.TimeFrom(Now()),
+		CreatedBy:   null.StringFrom(strconv.FormatInt(userID, 10)),
+	})
+}
+
+func (p *preference) get(ctx context.Context, userID int64) (entity.Preference, error) {
+	preference, err := p.preference.Get(ctx, entity.PreferenceParam{
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return entity.DefaultPreference(userID), nil
+	} else if err != nil {
+		return preference, err
+	}
+
+	return preference, nil
+}
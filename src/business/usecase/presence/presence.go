@@ -0,0 +1,68 @@
+package presence
+
+import (
+	"context"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	presenceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/presence"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+)
+
+var Now = time.Now
+
+const (
+	defaultOnlineWindow = 5 * time.Minute
+	defaultTTL          = 30 * 24 * time.Hour
+)
+
+type Interface interface {
+	// Touch marks the current user as active now
+	Touch(ctx context.Context) error
+	Get(ctx context.Context, userID int64) (entity.Presence, error)
+}
+
+type presence struct {
+	presence presenceDomain.Interface
+	cfg      config.PresenceConfig
+}
+
+type InitParam struct {
+	PresenceDomain presenceDomain.Interface
+	Config         config.PresenceConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.OnlineWindow <= 0 {
+		cfg.OnlineWindow = defaultOnlineWindow
+	}
+
+	if cfg.TTL < cfg.OnlineWindow {
+		cfg.TTL = defaultTTL
+	}
+
+	return &presence{
+		presence: param.PresenceDomain,
+		cfg:      cfg,
+	}
+}
+
+func (p *presence) Touch(ctx context.Context) error {
+	return p.presence.Touch(ctx, int64(appcontext.GetUserId(ctx)), Now(), p.cfg.TTL)
+}
+
+func (p *presence) Get(ctx context.Context, userID int64) (entity.Presence, error) {
+	presence := entity.Presence{UserID: userID}
+
+	lastSeen, err := p.presence.GetLastSeen(ctx, userID)
+	if err != nil {
+		return presence, err
+	}
+
+	presence.LastSeenAt = lastSeen
+	presence.IsOnline = lastSeen.Valid && Now().Sub(lastSeen.Time) <= p.cfg.OnlineWindow
+
+	return presence, nil
+}
@@ -0,0 +1,259 @@
+package realtime
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/query"
+	eventDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/event"
+	relationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	workspaceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+)
+
+var Now = time.Now
+
+const (
+	defaultMaxLen    = 1000
+	defaultTTL       = 24 * time.Hour
+	defaultHeartbeat = 15 * time.Second
+	readBatchSize    = 100
+
+	// firstID is the position before any event in a log
+	firstID = "0-0"
+)
+
+type Interface interface {
+	// Dispatch fans an outbox event out to the logs of the users it concerns, real-time delivery
+	// is best effort so failures are logged instead of holding the outbox back
+	Dispatch(ctx context.Context, event entity.Event) error
+	// Publish appends the event to the log of every user
+	Publish(ctx context.Context, userIDs []int64, event entity.Event) error
+	// Cursor returns the position of the latest event of the current user, streaming from it
+	// only delivers new events
+	Cursor(ctx context.Context) (string, error)
+	// Read returns the events of the current user after lastEventID, waiting up to the heartbeat
+	// interval when there are none. When events after lastEventID were trimmed a single
+	// stream.reset event is returned, the client should resync before resuming from its id
+	Read(ctx context.Context, lastEventID string) ([]entity.StreamEvent, error)
+}
+
+type realtime struct {
+	event     eventDomain.Interface
+	workspace workspaceDomain.Interface
+	relation  relationDomain.Interface
+	log       log.Interface
+	cfg       config.RealtimeConfig
+}
+
+type InitParam struct {
+	EventDomain     eventDomain.Interface
+	WorkspaceDomain workspaceDomain.Interface
+	RelationDomain  relationDomain.Interface
+	Log             log.Interface
+	Config          config.RealtimeConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.MaxLen < 1 {
+		cfg.MaxLen = defaultMaxLen
+	}
+
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultHeartbeat
+	}
+
+	return &realtime{
+		event:     param.EventDomain,
+		workspace: param.WorkspaceDomain,
+		relation:  param.RelationDomain,
+		log:       param.Log,
+		cfg:       cfg,
+	}
+}
+
+func (r *realtime) Dispatch(ctx context.Context, event entity.Event) error {
+	userIDs, err := r.getRecipientIDs(ctx, event)
+	if err != nil {
+		r.log.Error(ctx, err)
+		return nil
+	}
+
+	if err := r.Publish(ctx, userIDs, event); err != nil {
+		r.log.Error(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *realtime) Publish(ctx context.Context, userIDs []int64, event entity.Event) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = Now()
+	}
+
+	for _, userID := range userIDs {
+		if _, err := r.event.Append(ctx, userID, event, r.cfg.MaxLen, r.cfg.TTL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *realtime) Cursor(ctx context.Context) (string, error) {
+	latestID, err := r.event.GetLatestID(ctx, int64(appcontext.GetUserId(ctx)))
+	if err != nil {
+		return "", err
+	} else if latestID == "" {
+		return firstID, nil
+	}
+
+	return latestID, nil
+}
+
+func (r *realtime) Read(ctx context.Context, lastEventID string) ([]entity.StreamEvent, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if lastEventID != firstID {
+		oldestID, err := r.event.GetOldestID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if oldestID == "" || CompareID(lastEventID, oldestID) < 0 {
+			return r.reset(ctx, userID)
+		}
+	}
+
+	events, err := r.event.GetList(ctx, userID, lastEventID, readBatchSize)
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+
+	return r.event.Wait(ctx, userID, lastEventID, readBatchSize, r.cfg.Heartbeat)
+}
+
+func (r *realtime) reset(ctx context.Context, userID int64) ([]entity.StreamEvent, error) {
+	latestID, err := r.event.GetLatestID(ctx, userID)
+	if err != nil {
+		return nil, err
+	} else if latestID == "" {
+		latestID = firstID
+	}
+
+	return []entity.StreamEvent{{
+		ID: latestID,
+		Event: entity.Event{
+			ID:        uuid.New().String(),
+			Type:      entity.EventStreamReset,
+			ScopeType: entity.EventScopeUser,
+			ScopeID:   userID,
+			CreatedAt: Now(),
+		},
+	}}, nil
+}
+
+func (r *realtime) getRecipientIDs(ctx context.Context, event entity.Event) ([]int64, error) {
+	switch event.ScopeType {
+	case entity.EventScopeUser:
+		return []int64{event.ScopeID}, nil
+	case entity.WebhookScopeWorkspace:
+		members, _, err := r.workspace.GetMemberList(ctx, entity.WorkspaceMemberParam{
+			WorkspaceID: event.ScopeID,
+			QueryOption: query.Option{
+				IsActive:     true,
+				DisableLimit: true,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		blockedUserIDs := []int64{}
+		if event.ActorID > 0 {
+			blockedUserIDs, err = r.relation.GetBlockedUserIDs(ctx, event.ActorID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		userIDs := make([]int64, 0, len(members))
+		for _, member := range members {
+			if slices.Contains(blockedUserIDs, member.UserID) {
+				continue
+			}
+			userIDs = append(userIDs, member.UserID)
+		}
+
+		return userIDs, nil
+	default:
+		// conversation membership is not tracked by this service, those events are not streamed
+		return nil, nil
+	}
+}
+
+// CompareID orders two redis stream ids ("<milliseconds>-<sequence>"), ids that cannot be parsed
+// sort before every valid id
+func CompareID(a, b string) int {
+	aMs, aSeq, aOk := parseID(a)
+	bMs, bSeq, bOk := parseID(b)
+
+	switch {
+	case !aOk && !bOk:
+		return 0
+	case !aOk:
+		return -1
+	case !bOk:
+		return 1
+	case aMs != bMs:
+		return compare(aMs, bMs)
+	default:
+		return compare(aSeq, bSeq)
+	}
+}
+
+func parseID(id string) (uint64, uint64, bool) {
+	msText, seqText, _ := strings.Cut(id, "-")
+	if seqText == "" {
+		seqText = "0"
+	}
+
+	ms, err := strconv.ParseUint(msText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
+
+func compare(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,31 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CompareID(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "equal", a: "1718965949000-0", b: "1718965949000-0", want: 0},
+		{name: "older millisecond", a: "1718965948999-5", b: "1718965949000-0", want: -1},
+		{name: "newer sequence", a: "1718965949000-2", b: "1718965949000-1", want: 1},
+		{name: "sequence compared as number", a: "1718965949000-10", b: "1718965949000-9", want: 1},
+		{name: "missing sequence", a: "1718965949000", b: "1718965949000-0", want: 0},
+		{name: "first id", a: "0-0", b: "1718965949000-0", want: -1},
+		{name: "invalid before valid", a: "not-an-id", b: "0-0", want: -1},
+		{name: "valid after invalid", a: "0-0", b: "", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareID(tt.a, tt.b))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package relation
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	relationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+var Now = time.Now
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.RelationInputParam) (entity.Relation, error)
+	GetList(ctx context.Context, param entity.RelationParam) ([]entity.Relation, *entity.Pagination, error)
+	Delete(ctx context.Context, param entity.RelationParam) error
+	// IsBlocked reports whether either user has blocked the other
+	IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, error)
+	// GetBlockedUserIDs returns every user who blocked or was blocked by userID, used to filter lists and fan-out
+	GetBlockedUserIDs(ctx context.Context, userID int64) ([]int64, error)
+}
+
+type relation struct {
+	relation relationDomain.Interface
+	user     userDomain.Interface
+}
+
+type InitParam struct {
+	RelationDomain relationDomain.Interface
+	UserDomain     userDomain.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &relation{
+		relation: param.RelationDomain,
+		user:     param.UserDomain,
+	}
+}
+
+func (r *relation) Create(ctx context.Context, inputParam entity.RelationInputParam) (entity.Relation, error) {
+	relation := entity.Relation{}
+	userID := int64(appcontext.GetUserId(ctx))
+
+	err := r.validateInput(ctx, userID, inputParam)
+	if err != nil {
+		return relation, err
+	}
+
+	// blocking or muting twice is a no-op, a removed relation is restored instead of inserted again
+	relation, err = r.relation.Get(ctx, entity.RelationParam{
+		UserID:       userID,
+		RelationType: inputParam.RelationType,
+		TargetType:   inputParam.TargetType,
+		TargetID:     inputParam.TargetID,
+		BypassCache:  true,
+	})
+	switch {
+	case err == nil && relation.Status == 1:
+		return relation, nil
+	case err == nil:
+		restoredAt := Now()
+		err = r.relation.Restore(ctx, relation.ID, restoredAt, strconv.FormatInt(userID, 10))
+		if err != nil {
+			return relation, err
+		}
+
+		relation.Status = 1
+		relation.UpdatedAt = null.TimeFrom(restoredAt)
+		relation.UpdatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+		relation.DeletedAt = null.Time{}
+		relation.DeletedBy = null.String{}
+		return relation, nil
+	case errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist:
+		return relation, err
+	}
+
+	inputParam.UserID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	return r.relation.Create(ctx, inputParam)
+}
+
+func (r *relation) GetList(ctx context.Context, param entity.RelationParam) ([]entity.Relation, *entity.Pagination, error) {
+	param.UserID = int64(appcontext.GetUserId(ctx))
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return r.relation.GetList(ctx, param)
+}
+
+func (r *relation) Delete(ctx context.Context, param entity.RelationParam) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	relation, err := r.relation.Get(ctx, entity.RelationParam{
+		ID:     param.ID,
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "relation not found")
+	} else if err != nil {
+		return err
+	}
+
+	return r.relation.Update(ctx, entity.RelationUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.RelationParam{
+		ID: relation.ID,
+	})
+}
+
+func (r *relation) IsBlocked(ctx context.Context, userID, otherUserID int64) (bool, error) {
+	return r.relation.IsBlocked(ctx, userID, otherUserID)
+}
+
+func (r *relation) GetBlockedUserIDs(ctx context.Context, userID int64) ([]int64, error) {
+	return r.relation.GetBlockedUserIDs(ctx, userID)
+}
+
+func (r *relation) validateInput(ctx context.Context, userID int64, inputParam entity.RelationInputParam) error {
+	switch inputParam.RelationType {
+	case entity.RelationTypeBlock, entity.RelationTypeMute:
+	default:
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid relation type %s", inputParam.RelationType)
+	}
+
+	// users are the only target this service tracks, conversations are not stored here
+	if inputParam.TargetType != entity.RelationTargetUser {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid target type %s", inputParam.TargetType)
+	}
+
+	if inputParam.TargetID < 1 {
+		return errors.NewWithCode(codes.CodeBadRequest, "target id is required")
+	}
+
+	if inputParam.TargetID == userID {
+		return errors.NewWithCode(codes.CodeBadRequest, "cannot %s yourself", inputParam.RelationType)
+	}
+
+	_, err := r.user.Get(ctx, entity.UserParam{
+		ID: inputParam.TargetID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
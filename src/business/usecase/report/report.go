@@ -0,0 +1,416 @@
+package report
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	notificationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/notification"
+	reportDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/report"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+var Now = time.Now
+
+const (
+	maxDetailsLength = 1000
+	maxNoteLength    = 1000
+
+	warningTitle = "Your account received a warning"
+	warningBody  = "A moderator reviewed reports about your account and issued a warning. Repeated violations may lead to suspension."
+)
+
+var reasons = map[string]bool{
+	entity.ReportReasonSpam:          true,
+	entity.ReportReasonHarassment:    true,
+	entity.ReportReasonHate:          true,
+	entity.ReportReasonViolence:      true,
+	entity.ReportReasonImpersonation: true,
+	entity.ReportReasonOther:         true,
+}
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.ReportInputParam) (entity.Report, error)
+	GetCaseList(ctx context.Context, param entity.ReportCaseParam) ([]entity.ReportCase, *entity.Pagination, error)
+	GetCase(ctx context.Context, param entity.ReportCaseParam) (entity.ReportCase, error)
+	Assign(ctx context.Context, param entity.ReportCaseAssignParam) error
+	// Resolve applies the moderator action to the reported target and closes the case, later reports
+	// about the same target open a new case
+	Resolve(ctx context.Context, param entity.ReportCaseResolveParam) error
+	// Unsuspend lifts the suspension a resolved case put on its user, the case then records the lifting
+	// as its resolution and the suspension stays in the audit trail
+	Unsuspend(ctx context.Context, param entity.ReportCaseUnsuspendParam) error
+}
+
+type report struct {
+	report       reportDomain.Interface
+	user         userDomain.Interface
+	notification notificationDomain.Interface
+	audit        auditDomain.Interface
+	log          log.Interface
+}
+
+type InitParam struct {
+	ReportDomain       reportDomain.Interface
+	UserDomain         userDomain.Interface
+	NotificationDomain notificationDomain.Interface
+	AuditDomain        auditDomain.Interface
+	Log                log.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &report{
+		report:       param.ReportDomain,
+		user:         param.UserDomain,
+		notification: param.NotificationDomain,
+		audit:        param.AuditDomain,
+		log:          param.Log,
+	}
+}
+
+func (r *report) Create(ctx context.Context, inputParam entity.ReportInputParam) (entity.Report, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	err := validateReport(inputParam, userID)
+	if err != nil {
+		return entity.Report{}, err
+	}
+
+	_, err = r.getActiveUser(ctx, inputParam.TargetID)
+	if err != nil {
+		return entity.Report{}, err
+	}
+
+	inputParam.ReporterID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	report, err := r.report.Create(ctx, inputParam)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLUniqueConstraint {
+		return report, errors.NewWithCode(codes.CodeConflict, "you already reported this %s", inputParam.TargetType)
+	} else if err != nil {
+		return report, err
+	}
+
+	r.recordAudit(ctx, entity.AuditActionReport, report.CaseID, nil, report)
+
+	return report, nil
+}
+
+func (r *report) GetCaseList(ctx context.Context, param entity.ReportCaseParam) ([]entity.ReportCase, *entity.Pagination, error) {
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return r.report.GetCaseList(ctx, param)
+}
+
+func (r *report) GetCase(ctx context.Context, param entity.ReportCaseParam) (entity.ReportCase, error) {
+	reportCase, err := r.getCase(ctx, param.ID)
+	if err != nil {
+		return reportCase, err
+	}
+
+	reports, _, err := r.report.GetList(ctx, entity.ReportParam{
+		CaseID: reportCase.ID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return reportCase, err
+	}
+
+	reportCase.Reports = reports
+
+	return reportCase, nil
+}
+
+func (r *report) Assign(ctx context.Context, param entity.ReportCaseAssignParam) error {
+	reportCase, err := r.getCase(ctx, param.CaseID)
+	if err != nil {
+		return err
+	} else if !isOpen(reportCase.CaseStatus) {
+		return errors.NewWithCode(codes.CodeConflict, "case is already %s", reportCase.CaseStatus)
+	}
+
+	assignee, err := r.getActiveUser(ctx, param.AssigneeID)
+	if err != nil {
+		return err
+	} else if assignee.RoleID != entity.RoleIDAdmin {
+		return errors.NewWithCode(codes.CodeBadRequest, "case can only be assigned to an admin")
+	}
+
+	userID := int64(appcontext.GetUserId(ctx))
+
+	err = r.report.UpdateCase(ctx, entity.ReportCaseUpdateParam{
+		CaseStatus: null.StringFrom(entity.ReportCaseStatusInReview),
+		AssigneeID: null.Int64From(assignee.ID),
+		UpdatedAt:  null.TimeFrom(Now()),
+		UpdatedBy:  null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.ReportCaseParam{
+		ID: reportCase.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.recordAudit(ctx, entity.AuditActionCaseAssign, reportCase.ID, reportCase, param)
+
+	return nil
+}
+
+func (r *report) Resolve(ctx context.Context, param entity.ReportCaseResolveParam) error {
+	err := validateResolution(param)
+	if err != nil {
+		return err
+	}
+
+	reportCase, err := r.getCase(ctx, param.CaseID)
+	if err != nil {
+		return err
+	} else if !isOpen(reportCase.CaseStatus) {
+		return errors.NewWithCode(codes.CodeConflict, "case is already %s", reportCase.CaseStatus)
+	}
+
+	userID := int64(appcontext.GetUserId(ctx))
+	now := Now()
+
+	caseStatus := entity.ReportCaseStatusResolved
+	if param.Action == entity.ReportActionDismiss {
+		caseStatus = entity.ReportCaseStatusDismissed
+	}
+
+	note := null.String{}
+	if param.Note != "" {
+		note = null.StringFrom(param.Note)
+	}
+
+	updateParam := entity.ReportCaseUpdateParam{
+		CaseStatus:     null.StringFrom(caseStatus),
+		Resolution:     null.StringFrom(param.Action),
+		ResolutionNote: note,
+		ResolvedBy:     null.Int64From(userID),
+		ResolvedAt:     null.TimeFrom(now),
+		UpdatedAt:      null.TimeFrom(now),
+		UpdatedBy:      null.StringFrom(strconv.FormatInt(userID, 10)),
+	}
+
+	switch param.Action {
+	case entity.ReportActionWarn:
+		// the warning is sent before the case is closed so a failure leaves the case open for another attempt
+		err = r.warn(ctx, reportCase, userID, now)
+		if err != nil {
+			return err
+		}
+
+		err = r.closeCase(ctx, updateParam, reportCase.ID)
+	case entity.ReportActionSuspendUser:
+		err = r.suspend(ctx, reportCase, updateParam, userID)
+	default:
+		err = r.closeCase(ctx, updateParam, reportCase.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.recordAudit(ctx, entity.AuditActionCaseResolve, reportCase.ID, reportCase, param)
+
+	return nil
+}
+
+func (r *report) Unsuspend(ctx context.Context, param entity.ReportCaseUnsuspendParam) error {
+	if len(param.Note) > maxNoteLength {
+		return errors.NewWithCode(codes.CodeBadRequest, "note must not exceed %d characters", maxNoteLength)
+	}
+
+	reportCase, err := r.getCase(ctx, param.CaseID)
+	if err != nil {
+		return err
+	} else if reportCase.CaseStatus != entity.ReportCaseStatusResolved || reportCase.Resolution.String != entity.ReportActionSuspendUser {
+		return errors.NewWithCode(codes.CodeConflict, "case did not suspend its user")
+	}
+
+	userID := int64(appcontext.GetUserId(ctx))
+	now := Now()
+
+	note := null.String{}
+	if param.Note != "" {
+		note = null.StringFrom(param.Note)
+	}
+
+	err = r.report.UnsuspendCase(ctx, entity.ReportCaseUpdateParam{
+		Resolution:     null.StringFrom(entity.ReportActionUnsuspendUser),
+		ResolutionNote: note,
+		ResolvedBy:     null.Int64From(userID),
+		ResolvedAt:     null.TimeFrom(now),
+		UpdatedAt:      null.TimeFrom(now),
+		UpdatedBy:      null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, reportCase.ID, reportCase.TargetID)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLNoRowsAffected {
+		return errors.NewWithCode(codes.CodeConflict, "suspension was already lifted or its user is no longer suspended")
+	} else if err != nil {
+		return err
+	}
+
+	err = r.audit.Create(ctx, entity.AuditInputParam{
+		Action:     entity.AuditActionUnsuspend,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   null.Int64From(reportCase.TargetID),
+		AfterValue: param,
+	})
+	if err != nil {
+		r.log.Error(ctx, err)
+	}
+
+	r.recordAudit(ctx, entity.AuditActionCaseResolve, reportCase.ID, reportCase, param)
+
+	return nil
+}
+
+func (r *report) closeCase(ctx context.Context, updateParam entity.ReportCaseUpdateParam, caseID int64) error {
+	err := r.report.CloseCase(ctx, updateParam, caseID)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLNoRowsAffected {
+		return errors.NewWithCode(codes.CodeConflict, "case was already closed")
+	}
+
+	return err
+}
+
+func (r *report) warn(ctx context.Context, reportCase entity.ReportCase, userID int64, now time.Time) error {
+	// warnings come from a moderator so they bypass the recipient's mute and block preferences
+	_, err := r.notification.Create(ctx, entity.NotificationInputParam{
+		UserID:           reportCase.TargetID,
+		ActorID:          null.Int64From(userID),
+		NotificationType: entity.NotificationTypeWarning,
+		ScopeType:        entity.EventScopeUser,
+		ScopeID:          reportCase.TargetID,
+		Title:            warningTitle,
+		Body:             warningBody,
+		ReadStatus:       entity.NotificationReadStatusUnread,
+		CreatedAt:        null.TimeFrom(now),
+		CreatedBy:        null.StringFrom(strconv.FormatInt(userID, 10)),
+	})
+
+	return err
+}
+
+// suspend closes the case together with the suspension, the user is signed out of their sessions and
+// devices and their open streams receive user.suspended
+func (r *report) suspend(ctx context.Context, reportCase entity.ReportCase, updateParam entity.ReportCaseUpdateParam, userID int64) error {
+	if reportCase.TargetID == userID {
+		return errors.NewWithCode(codes.CodeBadRequest, "cannot suspend yourself")
+	}
+
+	target, err := r.getActiveUser(ctx, reportCase.TargetID)
+	if err != nil && errors.GetCode(err) == codes.CodeNotFound {
+		return errors.NewWithCode(codes.CodeConflict, "user is already inactive")
+	} else if err != nil {
+		return err
+	} else if target.RoleID == entity.RoleIDAdmin {
+		return errors.NewWithCode(codes.CodeForbidden, "cannot suspend an admin")
+	}
+
+	err = r.report.CloseCaseSuspend(ctx, updateParam, reportCase.ID, reportCase.TargetID)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLNoRowsAffected {
+		return errors.NewWithCode(codes.CodeConflict, "case was already closed or its user is already inactive")
+	} else if err != nil {
+		return err
+	}
+
+	err = r.audit.Create(ctx, entity.AuditInputParam{
+		Action:     entity.AuditActionSuspend,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   null.Int64From(reportCase.TargetID),
+		AfterValue: entity.ReportCaseResolveParam{CaseID: reportCase.ID, Action: entity.ReportActionSuspendUser},
+	})
+	if err != nil {
+		r.log.Error(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *report) getCase(ctx context.Context, caseID int64) (entity.ReportCase, error) {
+	reportCase, err := r.report.GetCase(ctx, entity.ReportCaseParam{
+		ID: caseID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return reportCase, errors.NewWithCode(codes.CodeNotFound, "report case not found")
+	}
+
+	return reportCase, err
+}
+
+func (r *report) getActiveUser(ctx context.Context, userID int64) (entity.User, error) {
+	user, err := r.user.Get(ctx, entity.UserParam{
+		ID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return user, errors.NewWithCode(codes.CodeNotFound, "user not found")
+	}
+
+	return user, err
+}
+
+func (r *report) recordAudit(ctx context.Context, action string, caseID int64, before, after interface{}) {
+	err := r.audit.Create(ctx, entity.AuditInputParam{
+		Action:      action,
+		TargetType:  entity.AuditTargetReportCase,
+		TargetID:    null.Int64From(caseID),
+		BeforeValue: before,
+		AfterValue:  after,
+	})
+	if err != nil {
+		r.log.Error(ctx, err)
+	}
+}
+
+func validateReport(inputParam entity.ReportInputParam, reporterID int64) error {
+	switch {
+	case inputParam.TargetType != entity.ReportTargetUser:
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid target type %q", inputParam.TargetType)
+	case inputParam.TargetID < 1:
+		return errors.NewWithCode(codes.CodeBadRequest, "target id is required")
+	case !reasons[inputParam.Reason]:
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid reason %q", inputParam.Reason)
+	case len(inputParam.Details.String) > maxDetailsLength:
+		return errors.NewWithCode(codes.CodeBadRequest, "details must not exceed %d characters", maxDetailsLength)
+	case inputParam.TargetID == reporterID:
+		return errors.NewWithCode(codes.CodeBadRequest, "cannot report yourself")
+	}
+
+	return nil
+}
+
+func validateResolution(param entity.ReportCaseResolveParam) error {
+	switch param.Action {
+	case entity.ReportActionWarn, entity.ReportActionSuspendUser, entity.ReportActionDismiss:
+	default:
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid action %q", param.Action)
+	}
+
+	if len(param.Note) > maxNoteLength {
+		return errors.NewWithCode(codes.CodeBadRequest, "note must not exceed %d characters", maxNoteLength)
+	}
+
+	return nil
+}
+
+func isOpen(caseStatus string) bool {
+	return caseStatus == entity.ReportCaseStatusOpen || caseStatus == entity.ReportCaseStatusInReview
+}
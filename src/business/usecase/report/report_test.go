@@ -0,0 +1,113 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateReport(t *testing.T) {
+	valid := entity.ReportInputParam{
+		TargetType: entity.ReportTargetUser,
+		TargetID:   2,
+		Reason:     entity.ReportReasonSpam,
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(p *entity.ReportInputParam)
+		wantErr bool
+	}{
+		{
+			name:   "valid report",
+			mutate: func(p *entity.ReportInputParam) {},
+		},
+		{
+			name:    "unsupported target type",
+			mutate:  func(p *entity.ReportInputParam) { p.TargetType = "message" },
+			wantErr: true,
+		},
+		{
+			name:    "missing target",
+			mutate:  func(p *entity.ReportInputParam) { p.TargetID = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "unknown reason",
+			mutate:  func(p *entity.ReportInputParam) { p.Reason = "boring" },
+			wantErr: true,
+		},
+		{
+			name:    "details too long",
+			mutate:  func(p *entity.ReportInputParam) { p.Details = null.StringFrom(strings.Repeat("a", maxDetailsLength+1)) },
+			wantErr: true,
+		},
+		{
+			name:    "reporting yourself",
+			mutate:  func(p *entity.ReportInputParam) { p.TargetID = 1 },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := valid
+			tt.mutate(&param)
+
+			err := validateReport(param, 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateResolution(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   entity.ReportCaseResolveParam
+		wantErr bool
+	}{
+		{
+			name:  "warn",
+			param: entity.ReportCaseResolveParam{Action: entity.ReportActionWarn},
+		},
+		{
+			name:  "suspend user with note",
+			param: entity.ReportCaseResolveParam{Action: entity.ReportActionSuspendUser, Note: "repeated harassment"},
+		},
+		{
+			name:  "dismiss",
+			param: entity.ReportCaseResolveParam{Action: entity.ReportActionDismiss},
+		},
+		{
+			name:    "unsupported action",
+			param:   entity.ReportCaseResolveParam{Action: "delete_message"},
+			wantErr: true,
+		},
+		{
+			name:    "unsuspend is not a resolution",
+			param:   entity.ReportCaseResolveParam{Action: entity.ReportActionUnsuspendUser},
+			wantErr: true,
+		},
+		{
+			name:    "note too long",
+			param:   entity.ReportCaseResolveParam{Action: entity.ReportActionWarn, Note: strings.Repeat("a", maxNoteLength+1)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResolution(tt.param)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
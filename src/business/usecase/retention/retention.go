@@ -0,0 +1,286 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
+	retentionDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/retention"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/legalhold"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/reqctx"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var Now = time.Now
+
+const (
+	defaultPurgeInterval  = time.Hour
+	defaultPurgeBatchSize = 1000
+
+	minRetentionDays = 1
+	maxRetentionDays = 3650
+)
+
+// targets lists what a policy purges, in the order it is purged
+var targets = []string{
+	entity.RetentionTargetNotification,
+	entity.RetentionTargetEvent,
+}
+
+type Interface interface {
+	// Get returns the retention policy of the workspace the request is scoped to
+	Get(ctx context.Context) (entity.RetentionPolicy, error)
+	// Upsert sets the retention of the workspace the request is scoped to, workspace admins only
+	Upsert(ctx context.Context, inputParam entity.RetentionPolicyInputParam) (entity.RetentionPolicy, error)
+	// Delete removes the retention of the workspace the request is scoped to so data is kept forever
+	Delete(ctx context.Context) error
+	// Purge applies every active policy, a dry run only counts what would be deleted
+	Purge(ctx context.Context, dryRun bool) ([]entity.RetentionReport, error)
+}
+
+type retention struct {
+	retention retentionDomain.Interface
+	workspace workspace.Interface
+	audit     auditDomain.Interface
+	legalhold legalhold.Interface
+	log       log.Interface
+	cfg       config.RetentionConfig
+}
+
+type InitParam struct {
+	RetentionDomain retentionDomain.Interface
+	Workspace       workspace.Interface
+	AuditDomain     auditDomain.Interface
+	LegalHold       legalhold.Interface
+	Log             log.Interface
+	Scheduler       scheduler.Interface
+	Config          config.RetentionConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+
+	if cfg.PurgeBatchSize < 1 {
+		cfg.PurgeBatchSize = defaultPurgeBatchSize
+	}
+
+	r := &retention{
+		retention: param.RetentionDomain,
+		workspace: param.Workspace,
+		audit:     param.AuditDomain,
+		legalhold: param.LegalHold,
+		log:       param.Log,
+		cfg:       cfg,
+	}
+
+	err := param.Scheduler.Register("retention-purge", scheduler.Every(cfg.PurgeInterval), r.purge)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return r
+}
+
+func (r *retention) Get(ctx context.Context) (entity.RetentionPolicy, error) {
+	policy, err := r.retention.Get(ctx, entity.RetentionPolicyParam{
+		ScopeType: entity.RetentionScopeWorkspace,
+		ScopeID:   reqctx.GetWorkspaceID(ctx),
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return policy, errors.NewWithCode(codes.CodeNotFound, "retention policy not found")
+	} else if err != nil {
+		return policy, err
+	}
+
+	return policy, nil
+}
+
+func (r *retention) Upsert(ctx context.Context, inputParam entity.RetentionPolicyInputParam) (entity.RetentionPolicy, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+	workspaceID := reqctx.GetWorkspaceID(ctx)
+
+	if inputParam.RetentionDays < minRetentionDays || inputParam.RetentionDays > maxRetentionDays {
+		return entity.RetentionPolicy{}, errors.NewWithCode(codes.CodeBadRequest, "retention days must be between %d and %d", minRetentionDays, maxRetentionDays)
+	}
+
+	err := r.workspace.EnsureAdmin(ctx, workspaceID, userID)
+	if err != nil {
+		return entity.RetentionPolicy{}, err
+	}
+
+	before, err := r.Get(ctx)
+	if err != nil && errors.GetCode(err) != codes.CodeNotFound {
+		return before, err
+	}
+
+	inputParam.ScopeType = entity.RetentionScopeWorkspace
+	inputParam.ScopeID = workspaceID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	err = r.retention.Upsert(ctx, inputParam)
+	if err != nil {
+		return entity.RetentionPolicy{}, err
+	}
+
+	r.recordAudit(ctx, entity.AuditActionRetention, workspaceID, before, inputParam)
+
+	return r.Get(ctx)
+}
+
+func (r *retention) Delete(ctx context.Context) error {
+	userID := int64(appcontext.GetUserId(ctx))
+	workspaceID := reqctx.GetWorkspaceID(ctx)
+
+	err := r.workspace.EnsureAdmin(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	policy, err := r.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = r.retention.Update(ctx, entity.RetentionPolicyUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.RetentionPolicyParam{
+		ID: policy.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.recordAudit(ctx, entity.AuditActionRetention, workspaceID, policy, nil)
+
+	return nil
+}
+
+func (r *retention) Purge(ctx context.Context, dryRun bool) ([]entity.RetentionReport, error) {
+	reports := []entity.RetentionReport{}
+
+	policies, _, err := r.retention.GetList(ctx, entity.RetentionPolicyParam{
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return reports, err
+	}
+
+	for _, policy := range policies {
+		report := entity.RetentionReport{
+			ScopeType:     policy.ScopeType,
+			ScopeID:       policy.ScopeID,
+			RetentionDays: policy.RetentionDays,
+			Before:        Now().AddDate(0, 0, -int(policy.RetentionDays)),
+			DryRun:        dryRun,
+			Purged:        map[string]int64{},
+		}
+
+		report.OnHold, err = r.legalhold.IsHeld(ctx, policy.ScopeType, policy.ScopeID)
+		if err != nil {
+			return reports, err
+		} else if report.OnHold {
+			reports = append(reports, report)
+			continue
+		}
+
+		for _, target := range targets {
+			param := entity.RetentionPurgeParam{
+				Target:    target,
+				ScopeType: policy.ScopeType,
+				ScopeID:   policy.ScopeID,
+				Before:    report.Before,
+				Limit:     r.cfg.PurgeBatchSize,
+			}
+
+			if dryRun {
+				report.Purged[target], err = r.retention.Count(ctx, param)
+			} else {
+				report.Purged[target], err = r.purgeTarget(ctx, param)
+			}
+			if err != nil {
+				return reports, err
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// purgeTarget deletes in batches so a large backlog does not hold long locks
+func (r *retention) purgeTarget(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	var total int64
+	for {
+		deleted, err := r.retention.Purge(ctx, param)
+		total += deleted
+		if err != nil || deleted < int64(param.Limit) {
+			return total, err
+		}
+	}
+}
+
+func (r *retention) purge(ctx context.Context) error {
+	reports, err := r.Purge(ctx, r.cfg.DryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		var total int64
+		for _, purged := range report.Purged {
+			total += purged
+		}
+
+		if total == 0 {
+			continue
+		}
+
+		if report.DryRun {
+			r.log.Info(ctx, fmt.Sprintf("retention dry run: would purge %v from %s %v: %v", total, report.ScopeType, report.ScopeID, report.Purged))
+			continue
+		}
+
+		r.log.Info(ctx, fmt.Sprintf("retention purged %v from %s %v: %v", total, report.ScopeType, report.ScopeID, report.Purged))
+		r.recordAudit(ctx, entity.AuditActionPurge, report.ScopeID, nil, report)
+	}
+
+	return nil
+}
+
+// recordAudit writes the audit trail without failing the action it describes
+func (r *retention) recordAudit(ctx context.Context, action string, workspaceID int64, before, after interface{}) {
+	err := r.audit.Create(ctx, entity.AuditInputParam{
+		Action:      action,
+		TargetType:  entity.AuditTargetWorkspace,
+		TargetID:    null.Int64From(workspaceID),
+		BeforeValue: before,
+		AfterValue:  after,
+	})
+	if err != nil {
+		r.log.Error(ctx, err)
+	}
+}
@@ -0,0 +1,122 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRetention keeps rows per target and deletes them in batches like the sql domain
+type fakeRetention struct {
+	policies []entity.RetentionPolicy
+	rows     map[string]int64
+	batches  int
+}
+
+func (f *fakeRetention) Upsert(ctx context.Context, inputParam entity.RetentionPolicyInputParam) error {
+	return nil
+}
+
+func (f *fakeRetention) Get(ctx context.Context, param entity.RetentionPolicyParam) (entity.RetentionPolicy, error) {
+	return entity.RetentionPolicy{}, nil
+}
+
+func (f *fakeRetention) GetList(ctx context.Context, param entity.RetentionPolicyParam) ([]entity.RetentionPolicy, *entity.Pagination, error) {
+	return f.policies, &entity.Pagination{}, nil
+}
+
+func (f *fakeRetention) Update(ctx context.Context, updateParam entity.RetentionPolicyUpdateParam, selectParam entity.RetentionPolicyParam) error {
+	return nil
+}
+
+func (f *fakeRetention) Count(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	return f.rows[param.Target], nil
+}
+
+func (f *fakeRetention) Purge(ctx context.Context, param entity.RetentionPurgeParam) (int64, error) {
+	f.batches++
+	deleted := min(f.rows[param.Target], int64(param.Limit))
+	f.rows[param.Target] -= deleted
+	return deleted, nil
+}
+
+// fakeLegalHold holds the scope ids it is given
+type fakeLegalHold struct {
+	held map[int64]bool
+}
+
+func (f *fakeLegalHold) Create(ctx context.Context, inputParam entity.LegalHoldInputParam) (entity.LegalHold, error) {
+	return entity.LegalHold{}, nil
+}
+
+func (f *fakeLegalHold) GetList(ctx context.Context, param entity.LegalHoldParam) ([]entity.LegalHold, *entity.Pagination, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeLegalHold) Release(ctx context.Context, param entity.LegalHoldParam) error {
+	return nil
+}
+
+func (f *fakeLegalHold) IsHeld(ctx context.Context, scopeType string, scopeID int64) (bool, error) {
+	return f.held[scopeID], nil
+}
+
+func (f *fakeLegalHold) EnsureNotHeld(ctx context.Context, scopeType string, scopeID int64) error {
+	return nil
+}
+
+func Test_Purge(t *testing.T) {
+	now := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	Now = func() time.Time { return now }
+	defer func() { Now = time.Now }()
+
+	newFake := func() *fakeRetention {
+		return &fakeRetention{
+			policies: []entity.RetentionPolicy{{ScopeType: entity.RetentionScopeWorkspace, ScopeID: 7, RetentionDays: 90, CreatedAt: null.TimeFrom(now)}},
+			rows:     map[string]int64{entity.RetentionTargetNotification: 25, entity.RetentionTargetEvent: 0},
+		}
+	}
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		fake := newFake()
+		r := &retention{retention: fake, legalhold: &fakeLegalHold{}, cfg: config.RetentionConfig{PurgeBatchSize: 10}}
+
+		reports, err := r.Purge(context.Background(), true)
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+		assert.True(t, reports[0].DryRun)
+		assert.Equal(t, now.AddDate(0, 0, -90), reports[0].Before)
+		assert.Equal(t, int64(25), reports[0].Purged[entity.RetentionTargetNotification])
+		assert.Equal(t, int64(25), fake.rows[entity.RetentionTargetNotification])
+		assert.Equal(t, 0, fake.batches)
+	})
+
+	t.Run("purge deletes in batches", func(t *testing.T) {
+		fake := newFake()
+		r := &retention{retention: fake, legalhold: &fakeLegalHold{}, cfg: config.RetentionConfig{PurgeBatchSize: 10}}
+
+		reports, err := r.Purge(context.Background(), false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(25), reports[0].Purged[entity.RetentionTargetNotification])
+		assert.Equal(t, int64(0), reports[0].Purged[entity.RetentionTargetEvent])
+		assert.Equal(t, int64(0), fake.rows[entity.RetentionTargetNotification])
+		// 10 + 10 + 5 for notifications, one empty batch for events
+		assert.Equal(t, 4, fake.batches)
+	})
+
+	t.Run("workspace on legal hold is skipped", func(t *testing.T) {
+		fake := newFake()
+		r := &retention{retention: fake, legalhold: &fakeLegalHold{held: map[int64]bool{7: true}}, cfg: config.RetentionConfig{PurgeBatchSize: 10}}
+
+		reports, err := r.Purge(context.Background(), false)
+		assert.NoError(t, err)
+		assert.True(t, reports[0].OnHold)
+		assert.Equal(t, int64(25), fake.rows[entity.RetentionTargetNotification])
+		assert.Equal(t, 0, fake.batches)
+	})
+}
@@ -0,0 +1,176 @@
+package sync
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/query"
+	outboxDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/outbox"
+	relationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	workspaceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+var Now = time.Now
+
+const (
+	// v2 tokens hold an outbox seq, v1 tokens held an outbox id and must start over
+	tokenPrefix = "v2:"
+
+	defaultLimit = 100
+	maxLimit     = 500
+)
+
+type Interface interface {
+	// Get returns the changes visible to the current user after the since token, an empty token
+	// returns no changes and the token of the latest change to start from
+	Get(ctx context.Context, param entity.SyncParam) (entity.Sync, error)
+}
+
+type sync struct {
+	outbox    outboxDomain.Interface
+	workspace workspaceDomain.Interface
+	relation  relationDomain.Interface
+}
+
+type InitParam struct {
+	OutboxDomain    outboxDomain.Interface
+	WorkspaceDomain workspaceDomain.Interface
+	RelationDomain  relationDomain.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &sync{
+		outbox:    param.OutboxDomain,
+		workspace: param.WorkspaceDomain,
+		relation:  param.RelationDomain,
+	}
+}
+
+func (s *sync) Get(ctx context.Context, param entity.SyncParam) (entity.Sync, error) {
+	result := entity.Sync{Changes: []entity.Event{}}
+
+	if param.Since == "" {
+		latestSeq, err := s.outbox.GetLatestSeq(ctx)
+		if err != nil {
+			return result, err
+		}
+
+		result.NextToken = EncodeToken(latestSeq)
+		return result, nil
+	}
+
+	// events only get a seq once the relay commits them, and seqs commit in order, so no event
+	// can later appear behind a token that was already handed out
+	afterSeq, err := DecodeToken(param.Since)
+	if err != nil {
+		return result, err
+	}
+
+	if param.Limit < 1 {
+		param.Limit = defaultLimit
+	} else if param.Limit > maxLimit {
+		param.Limit = maxLimit
+	}
+
+	scopes, err := s.getScopes(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// one extra row tells whether another page follows
+	rows, err := s.outbox.GetEventList(ctx, entity.OutboxEventParam{
+		AfterSeq: afterSeq,
+		Scopes:   scopes,
+		Limit:    param.Limit + 1,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// a token behind the retention purge missed events, like a stream.reset the client has to resync
+	// and start over with an empty token. Read after the page so a purge committed meanwhile is caught
+	purgedSeq, err := s.outbox.GetPurgedSeq(ctx, scopes)
+	if err != nil {
+		return result, err
+	} else if afterSeq < purgedSeq {
+		return result, errors.NewWithCode(codes.CodeConflict, "sync token expired, resync and start over with an empty token")
+	}
+
+	if int64(len(rows)) > param.Limit {
+		rows = rows[:param.Limit]
+		result.HasMore = true
+	}
+
+	blockedUserIDs, err := s.relation.GetBlockedUserIDs(ctx, int64(appcontext.GetUserId(ctx)))
+	if err != nil {
+		return result, err
+	}
+
+	// the token still moves past hidden events so they are not read again
+	for _, row := range rows {
+		afterSeq = row.Seq.Int64
+		if slices.Contains(blockedUserIDs, row.ActorID) {
+			continue
+		}
+		result.Changes = append(result.Changes, row.ToEvent())
+	}
+
+	result.NextToken = EncodeToken(afterSeq)
+
+	return result, nil
+}
+
+// getScopes returns the user itself and every workspace the user is a member of
+func (s *sync) getScopes(ctx context.Context) ([]entity.EventScope, error) {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	members, _, err := s.workspace.GetMemberList(ctx, entity.WorkspaceMemberParam{
+		UserID: userID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []entity.EventScope{{Type: entity.EventScopeUser, ID: userID}}
+	for _, member := range members {
+		scopes = append(scopes, entity.EventScope{Type: entity.WebhookScopeWorkspace, ID: member.WorkspaceID})
+	}
+
+	return scopes, nil
+}
+
+// EncodeToken hides the outbox seq behind an opaque, versioned token
+func EncodeToken(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(tokenPrefix + strconv.FormatInt(seq, 10)))
+}
+
+func DecodeToken(token string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid sync token")
+	}
+
+	position, ok := strings.CutPrefix(string(raw), tokenPrefix)
+	if !ok {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid sync token")
+	}
+
+	seq, err := strconv.ParseInt(position, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errors.NewWithCode(codes.CodeBadRequest, "invalid sync token")
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Token(t *testing.T) {
+	for _, id := range []int64{0, 1, 1234567890} {
+		got, err := DecodeToken(EncodeToken(id))
+		assert.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "%%%"},
+		{name: "unknown version", token: base64.RawURLEncoding.EncodeToString([]byte("v0:10"))},
+		{name: "id based version", token: base64.RawURLEncoding.EncodeToString([]byte("v1:10"))},
+		{name: "not a number", token: base64.RawURLEncoding.EncodeToString([]byte("v2:ten"))},
+		{name: "negative", token: base64.RawURLEncoding.EncodeToString([]byte("v2:-1"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeToken(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -6,23 +6,184 @@ import (
 	"github.com/reyhanmichiels/go-pkg/log"
 	"github.com/reyhanmichiels/go-pkg/parser"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/audit"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/contact"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/devicekey"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/digest"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/export"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/idempotency"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/legalhold"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/notification"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/outbox"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/preference"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/presence"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/realtime"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/relation"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/report"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/retention"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/sync"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/user"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/webhook"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/mail"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/push"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
 )
 
 type Usecases struct {
-	User user.Interface
+	User         user.Interface
+	Webhook      webhook.Interface
+	Outbox       outbox.Interface
+	Audit        audit.Interface
+	Relation     relation.Interface
+	Presence     presence.Interface
+	Contact      contact.Interface
+	Workspace    workspace.Interface
+	Notification notification.Interface
+	Preference   preference.Interface
+	Digest       digest.Interface
+	Realtime     realtime.Interface
+	Sync         sync.Interface
+	Idempotency  idempotency.Interface
+	DeviceKey    devicekey.Interface
+	Retention    retention.Interface
+	LegalHold    legalhold.Interface
+	Export       export.Interface
+	Report       report.Interface
 }
 
 type InitParam struct {
-	Dom  *domain.Domains
-	Json parser.JSONInterface
-	Log  log.Interface
-	Hash hash.Interface
-	Auth auth.Interface
+	Dom          *domain.Domains
+	Json         parser.JSONInterface
+	Log          log.Interface
+	Hash         hash.Interface
+	Auth         auth.Interface
+	Push         push.Interface
+	Mail         mail.Interface
+	Scheduler    scheduler.Interface
+	Locker       locker.Interface
+	Webhook      config.WebhookConfig
+	Outbox       config.OutboxConfig
+	Audit        config.AuditConfig
+	Contact      config.ContactConfig
+	Presence     config.PresenceConfig
+	Notification config.NotificationConfig
+	Digest       config.DigestConfig
+	Realtime     config.RealtimeConfig
+	Idempotency  config.IdempotencyConfig
+	Retention    config.RetentionConfig
+	Export       config.ExportConfig
 }
 
 func Init(param InitParam) *Usecases {
+	workspace := workspace.Init(workspace.InitParam{WorkspaceDomain: param.Dom.Workspace, UserDomain: param.Dom.User})
+	webhook := webhook.Init(webhook.InitParam{WebhookDomain: param.Dom.Webhook, Workspace: workspace, RelationDomain: param.Dom.Relation, UserDomain: param.Dom.User, Log: param.Log, Json: param.Json, Scheduler: param.Scheduler, Config: param.Webhook})
+
+	// outbox events are relayed to every subscriber of the event bus
+	outbox := outbox.Init(outbox.InitParam{OutboxDomain: param.Dom.Outbox, Log: param.Log, Scheduler: param.Scheduler, Config: param.Outbox})
+	outbox.Subscribe(webhook.Dispatch)
+
+	realtime := realtime.Init(realtime.InitParam{EventDomain: param.Dom.Event, WorkspaceDomain: param.Dom.Workspace, RelationDomain: param.Dom.Relation, Log: param.Log, Config: param.Realtime})
+	outbox.Subscribe(realtime.Dispatch)
+
+	// members joining a workspace are announced to the rest of it
+	notification := notification.Init(notification.InitParam{
+		NotificationDomain: param.Dom.Notification,
+		RelationDomain:     param.Dom.Relation,
+		PresenceDomain:     param.Dom.Presence,
+		PreferenceDomain:   param.Dom.Preference,
+		WorkspaceDomain:    param.Dom.Workspace,
+		UserDomain:         param.Dom.User,
+		Push:               param.Push,
+		Log:                param.Log,
+		Json:               param.Json,
+		Scheduler:          param.Scheduler,
+		Presence:           param.Presence,
+		Config:             param.Notification,
+	})
+	outbox.Subscribe(notification.Dispatch)
+
+	presence := presence.Init(presence.InitParam{PresenceDomain: param.Dom.Presence, Config: param.Presence})
+	preference := preference.Init(preference.InitParam{PreferenceDomain: param.Dom.Preference, Workspace: workspace})
+	relation := relation.Init(relation.InitParam{RelationDomain: param.Dom.Relation, UserDomain: param.Dom.User})
+	contact := contact.Init(contact.InitParam{
+		ContactDomain: param.Dom.Contact,
+		UserDomain:    param.Dom.User,
+		Relation:      relation,
+		Presence:      presence,
+		Log:           param.Log,
+		Config:        param.Contact,
+	})
+	legalhold := legalhold.Init(legalhold.InitParam{
+		LegalHoldDomain: param.Dom.LegalHold,
+		UserDomain:      param.Dom.User,
+		WorkspaceDomain: param.Dom.Workspace,
+		AuditDomain:     param.Dom.Audit,
+		Log:             param.Log,
+	})
+
 	return &Usecases{
-		User: user.Init(user.InitParam{UserDomain: param.Dom.User, Auth: param.Auth, Hash: param.Hash}),
+		User:         user.Init(user.InitParam{UserDomain: param.Dom.User, AuditDomain: param.Dom.Audit, LegalHold: legalhold, Auth: param.Auth, Hash: param.Hash, Log: param.Log}),
+		Webhook:      webhook,
+		Outbox:       outbox,
+		Audit:        audit.Init(audit.InitParam{AuditDomain: param.Dom.Audit, Log: param.Log, Scheduler: param.Scheduler, Config: param.Audit}),
+		Relation:     relation,
+		Presence:     presence,
+		Contact:      contact,
+		Workspace:    workspace,
+		Notification: notification,
+		Preference:   preference,
+		Digest: digest.Init(digest.InitParam{
+			DigestDomain:       param.Dom.Digest,
+			NotificationDomain: param.Dom.Notification,
+			UserDomain:         param.Dom.User,
+			Preference:         preference,
+			Presence:           presence,
+			Mail:               param.Mail,
+			Scheduler:          param.Scheduler,
+			Log:                param.Log,
+			Config:             param.Digest,
+		}),
+		Realtime: realtime,
+		Sync:     sync.Init(sync.InitParam{OutboxDomain: param.Dom.Outbox, WorkspaceDomain: param.Dom.Workspace, RelationDomain: param.Dom.Relation}),
+		Idempotency: idempotency.Init(idempotency.InitParam{
+			IdempotencyDomain: param.Dom.Idempotency,
+			Locker:            param.Locker,
+			Config:            param.Idempotency,
+		}),
+		DeviceKey: devicekey.Init(devicekey.InitParam{
+			DeviceKeyDomain:    param.Dom.DeviceKey,
+			NotificationDomain: param.Dom.Notification,
+			Relation:           relation,
+			Contact:            contact,
+		}),
+		Retention: retention.Init(retention.InitParam{
+			RetentionDomain: param.Dom.Retention,
+			Workspace:       workspace,
+			AuditDomain:     param.Dom.Audit,
+			LegalHold:       legalhold,
+			Log:             param.Log,
+			Scheduler:       param.Scheduler,
+			Config:          param.Retention,
+		}),
+		LegalHold: legalhold,
+		Export: export.Init(export.InitParam{
+			ExportDomain:       param.Dom.Export,
+			UserDomain:         param.Dom.User,
+			NotificationDomain: param.Dom.Notification,
+			AuditDomain:        param.Dom.Audit,
+			Scheduler:          param.Scheduler,
+			Log:                param.Log,
+			Config:             param.Export,
+		}),
+		Report: report.Init(report.InitParam{
+			ReportDomain:       param.Dom.Report,
+			UserDomain:         param.Dom.User,
+			NotificationDomain: param.Dom.Notification,
+			AuditDomain:        param.Dom.Audit,
+			Log:                param.Log,
+		}),
 	}
 }
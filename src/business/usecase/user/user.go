@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/reyhanmichiels/go-pkg/appcontext"
 	"github.com/reyhanmichiels/go-pkg/auth"
 	"github.com/reyhanmichiels/go-pkg/codes"
 	"github.com/reyhanmichiels/go-pkg/errors"
 	"github.com/reyhanmichiels/go-pkg/hash"
+	"github.com/reyhanmichiels/go-pkg/log"
 	"github.com/reyhanmichiels/go-pkg/null"
 	"github.com/reyhanmichiels/go-pkg/query"
+	auditDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/audit"
 	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/legalhold"
 )
 
 var Now = time.Now
@@ -22,25 +26,37 @@ type Interface interface {
 	SignIn(ctx context.Context, param entity.UserLoginParam) (entity.UserLoginResponse, error)
 	Get(ctx context.Context, param entity.UserParam) (entity.User, error)
 	RefreshToken(ctx context.Context, param entity.RefreshTokenParam) (entity.UserLoginResponse, error)
+	UpdateRole(ctx context.Context, param entity.UserRoleUpdateParam) error
+	// Delete erases the account of the current user, it is refused while the user is on legal hold
+	Delete(ctx context.Context, param entity.UserDeleteParam) error
 }
 
 type user struct {
-	user userDomain.Interface
-	auth auth.Interface
-	hash hash.Interface
+	user      userDomain.Interface
+	audit     auditDomain.Interface
+	legalhold legalhold.Interface
+	auth      auth.Interface
+	hash      hash.Interface
+	log       log.Interface
 }
 
 type InitParam struct {
-	UserDomain userDomain.Interface
-	Auth       auth.Interface
-	Hash       hash.Interface
+	UserDomain  userDomain.Interface
+	AuditDomain auditDomain.Interface
+	LegalHold   legalhold.Interface
+	Auth        auth.Interface
+	Hash        hash.Interface
+	Log         log.Interface
 }
 
 func Init(param InitParam) Interface {
 	return &user{
-		user: param.UserDomain,
-		auth: param.Auth,
-		hash: param.Hash,
+		user:      param.UserDomain,
+		audit:     param.AuditDomain,
+		legalhold: param.LegalHold,
+		auth:      param.Auth,
+		hash:      param.Hash,
+		log:       param.Log,
 	}
 }
 
@@ -51,20 +67,15 @@ func (u *user) Register(ctx context.Context, inputParam entity.UserInputParam) (
 		return user, errors.NewWithCode(codes.CodeBadRequest, "confirmation password failed")
 	}
 
-	isUserExist := false
-	_, err := u.user.Get(ctx, entity.UserParam{
+	// a suspended account keeps its email, so it is looked up whatever its status is
+	existingUser, err := u.user.Get(ctx, entity.UserParam{
 		Email: inputParam.Email,
-		QueryOption: query.Option{
-			IsActive: true,
-		},
 	})
 	if err != nil && errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist {
 		return user, err
+	} else if err == nil && existingUser.Status == entity.UserStatusSuspended {
+		return user, errors.NewWithCode(codes.CodeForbidden, "email belongs to a suspended account")
 	} else if err == nil {
-		isUserExist = true
-	}
-
-	if isUserExist {
 		return user, errors.NewWithCode(codes.CodeConflict, "email already used")
 	}
 
@@ -76,11 +87,13 @@ func (u *user) Register(ctx context.Context, inputParam entity.UserInputParam) (
 	inputParam.CreatedAt = null.TimeFrom(Now())
 	inputParam.Password = hashedPassword
 	user, err = u.user.Create(ctx, inputParam)
-	if err != nil {
+	if err != nil && errors.GetCode(err) == codes.CodeSQLUniqueConstraint {
+		return user, errors.NewWithCode(codes.CodeConflict, "email already used")
+	} else if err != nil {
 		return user, err
 	}
 
-	fmt.Printf("\n\n\nType of RefreshToken: %T\n\n\n", user.RefreshToken)
+	u.recordAudit(ctx, entity.AuditActionRegister, user.ID, nil, user)
 
 	return user, nil
 }
@@ -90,11 +103,9 @@ func (u *user) SignIn(ctx context.Context, param entity.UserLoginParam) (entity.
 
 	user, err := u.user.Get(ctx, entity.UserParam{
 		Email: param.Email,
-		QueryOption: query.Option{
-			IsActive: true,
-		},
 	})
 	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		u.recordAudit(ctx, entity.AuditActionLoginFailed, 0, nil, entity.UserEventData{Email: param.Email})
 		return userLoginResponse, errors.NewWithCode(codes.CodeUnauthorized, "invalid email or password")
 	} else if err != nil && errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist {
 		return userLoginResponse, err
@@ -102,6 +113,16 @@ func (u *user) SignIn(ctx context.Context, param entity.UserLoginParam) (entity.
 
 	isPasswordSame := u.hash.Bcrypt().CompareHashWithText(user.Password, param.Password)
 	if !isPasswordSame {
+		u.recordAudit(ctx, entity.AuditActionLoginFailed, user.ID, nil, entity.UserEventData{Email: param.Email})
+		return userLoginResponse, errors.NewWithCode(codes.CodeUnauthorized, "invalid email or password")
+	}
+
+	// the suspension is only revealed to someone who knows the password
+	if user.Status == entity.UserStatusSuspended {
+		u.recordAudit(ctx, entity.AuditActionLoginFailed, user.ID, nil, entity.UserEventData{Email: param.Email})
+		return userLoginResponse, errors.NewWithCode(codes.CodeForbidden, "account is suspended")
+	} else if user.Status != entity.UserStatusActive {
+		u.recordAudit(ctx, entity.AuditActionLoginFailed, user.ID, nil, entity.UserEventData{Email: param.Email})
 		return userLoginResponse, errors.NewWithCode(codes.CodeUnauthorized, "invalid email or password")
 	}
 
@@ -110,6 +131,8 @@ func (u *user) SignIn(ctx context.Context, param entity.UserLoginParam) (entity.
 		return userLoginResponse, err
 	}
 
+	u.recordAudit(ctx, entity.AuditActionLogin, user.ID, nil, entity.UserEventData{ID: user.ID, Email: user.Email})
+
 	userLoginResponse = entity.UserLoginResponse{
 		Name:         user.Name,
 		Email:        user.Email,
@@ -155,6 +178,8 @@ func (u *user) RefreshToken(ctx context.Context, param entity.RefreshTokenParam)
 		return response, err
 	}
 
+	u.recordAudit(ctx, entity.AuditActionTokenRefresh, user.ID, nil, nil)
+
 	response = entity.UserLoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -163,6 +188,118 @@ func (u *user) RefreshToken(ctx context.Context, param entity.RefreshTokenParam)
 	return response, nil
 }
 
+func (u *user) UpdateRole(ctx context.Context, param entity.UserRoleUpdateParam) error {
+	if param.RoleID != entity.RoleIDAdmin && param.RoleID != entity.RoleIDUser {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid role")
+	}
+
+	user, err := u.user.Get(ctx, entity.UserParam{
+		ID: param.UserID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return err
+	}
+
+	if user.RoleID == param.RoleID {
+		return nil
+	}
+
+	err = u.user.Update(ctx, entity.UserUpdateParam{
+		RoleID:    param.RoleID,
+		UpdatedAt: null.TimeFrom(Now()),
+		UpdatedBy: null.StringFrom(fmt.Sprint(appcontext.GetUserId(ctx))),
+	}, entity.UserParam{
+		ID: param.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	u.recordAudit(ctx, entity.AuditActionRoleChange, user.ID, entity.UserRoleUpdateParam{RoleID: user.RoleID}, param)
+
+	return nil
+}
+
+func (u *user) Delete(ctx context.Context, param entity.UserDeleteParam) error {
+	userID := int64(appcontext.GetUserId(ctx))
+
+	user, err := u.user.Get(ctx, entity.UserParam{
+		ID: userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+		BypassCache: true,
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return err
+	}
+
+	isPasswordSame := u.hash.Bcrypt().CompareHashWithText(user.Password, param.Password)
+	if !isPasswordSame {
+		return errors.NewWithCode(codes.CodeUnauthorized, "invalid password")
+	}
+
+	err = u.legalhold.EnsureNotHeld(ctx, entity.LegalHoldScopeUser, userID)
+	if err != nil {
+		return err
+	}
+
+	// the audit trail is anonymized first so a failure leaves the account intact and can be retried
+	err = u.audit.Anonymize(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// access tokens stop working once the user is inactive and the refresh token is cleared
+	err = u.user.Erase(ctx, userID, Now())
+	if err != nil {
+		return err
+	}
+
+	// the client of the request that erased the account is personal data as well
+	err = u.audit.Create(ctx, entity.AuditInputParam{
+		Action:     entity.AuditActionDelete,
+		TargetType: entity.AuditTargetUser,
+		TargetID:   null.Int64From(userID),
+		OmitClient: true,
+	})
+	if err != nil {
+		u.log.Error(ctx, err)
+	}
+
+	return nil
+}
+
+// recordAudit writes the audit trail without failing the action it describes
+func (u *user) recordAudit(ctx context.Context, action string, targetID int64, before, after interface{}) {
+	inputParam := entity.AuditInputParam{
+		Action:      action,
+		TargetType:  entity.AuditTargetUser,
+		BeforeValue: before,
+		AfterValue:  after,
+	}
+
+	if targetID > 0 {
+		inputParam.TargetID = null.Int64From(targetID)
+	}
+
+	// unauthenticated auth actions are performed by the user they target, except failed logins
+	if appcontext.GetUserId(ctx) == 0 && targetID > 0 && action != entity.AuditActionLoginFailed {
+		inputParam.ActorID = null.Int64From(targetID)
+	}
+
+	if err := u.audit.Create(ctx, inputParam); err != nil {
+		u.log.Error(ctx, err)
+	}
+}
+
 func (u *user) issueToken(ctx context.Context, userID int64) (string, string, error) {
 	accessToken, err := u.auth.CreateAccessToken(userID)
 	if err != nil {
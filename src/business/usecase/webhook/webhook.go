@@ -0,0 +1,637 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/parser"
+	"github.com/reyhanmichiels/go-pkg/query"
+	relationDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/relation"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	webhookDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/webhook"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
+)
+
+var (
+	Now = time.Now
+
+	// LookupIPAddr resolves webhook hosts when they are created, replaced in tests
+	LookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+	errBlockedAddress = fmt.Errorf("webhook url resolves to a blocked address")
+)
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultMaxAttempt      = 3
+	defaultBackoffInterval = time.Second
+	defaultMaxFailure      = 10
+	defaultInterval        = 5 * time.Second
+	defaultBatchSize       = 100
+	defaultConcurrency     = 4
+	secretLength           = 32
+)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.WebhookInputParam) (entity.Webhook, error)
+	GetList(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, *entity.Pagination, error)
+	Get(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error)
+	Delete(ctx context.Context, param entity.WebhookParam) error
+	Enable(ctx context.Context, param entity.WebhookParam) error
+	GetDeliveryList(ctx context.Context, param entity.WebhookDeliveryParam) ([]entity.WebhookDelivery, *entity.Pagination, error)
+	// Dispatch queues the event for every active webhook subscribed to it
+	Dispatch(ctx context.Context, event entity.Event) error
+}
+
+type webhook struct {
+	webhook    webhookDomain.Interface
+	workspace  workspace.Interface
+	relation   relationDomain.Interface
+	user       userDomain.Interface
+	log        log.Interface
+	json       parser.JSONInterface
+	httpClient *http.Client
+	cfg        config.WebhookConfig
+}
+
+type InitParam struct {
+	WebhookDomain  webhookDomain.Interface
+	Workspace      workspace.Interface
+	RelationDomain relationDomain.Interface
+	UserDomain     userDomain.Interface
+	Log            log.Interface
+	Json           parser.JSONInterface
+	Scheduler      scheduler.Interface
+	Config         config.WebhookConfig
+}
+
+func Init(param InitParam) Interface {
+	cfg := param.Config
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	if cfg.MaxAttempt < 1 {
+		cfg.MaxAttempt = defaultMaxAttempt
+	}
+
+	if cfg.BackoffInterval <= 0 {
+		cfg.BackoffInterval = defaultBackoffInterval
+	}
+
+	if cfg.MaxFailure < 1 {
+		cfg.MaxFailure = defaultMaxFailure
+	}
+
+	if cfg.DeliveryInterval <= 0 {
+		cfg.DeliveryInterval = defaultInterval
+	}
+
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = defaultBatchSize
+	}
+
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = defaultConcurrency
+	}
+
+	// every connection is checked after the host is resolved, so a receiver cannot
+	// point its DNS or a redirect at an internal address after it was created
+	dialer := &net.Dialer{Timeout: cfg.Timeout, Control: guardDial}
+
+	w := &webhook{
+		webhook:   param.WebhookDomain,
+		workspace: param.Workspace,
+		relation:  param.RelationDomain,
+		user:      param.UserDomain,
+		log:       param.Log,
+		json:      param.Json,
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+		cfg: cfg,
+	}
+
+	err := param.Scheduler.Register("webhook-delivery", scheduler.Every(cfg.DeliveryInterval), w.deliverDue)
+	if err != nil {
+		param.Log.Fatal(context.Background(), err)
+	}
+
+	return w
+}
+
+func (w *webhook) Create(ctx context.Context, inputParam entity.WebhookInputParam) (entity.Webhook, error) {
+	webhook := entity.Webhook{}
+
+	err := w.validateInput(ctx, inputParam)
+	if err != nil {
+		return webhook, err
+	}
+
+	userID := int64(appcontext.GetUserId(ctx))
+
+	if inputParam.ScopeType == entity.WebhookScopeWorkspace {
+		err = w.workspace.EnsureAdmin(ctx, inputParam.ScopeID, userID)
+		if err != nil {
+			return webhook, err
+		}
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return webhook, err
+	}
+
+	inputParam.UserID = userID
+	inputParam.Secret = secret
+	inputParam.EventList = strings.Join(inputParam.Events, ",")
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	// the secret is only returned once, on creation
+	return w.webhook.Create(ctx, inputParam)
+}
+
+func (w *webhook) GetList(ctx context.Context, param entity.WebhookParam) ([]entity.Webhook, *entity.Pagination, error) {
+	param.UserID = int64(appcontext.GetUserId(ctx))
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	webhooks, pg, err := w.webhook.GetList(ctx, param)
+	if err != nil {
+		return webhooks, pg, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, pg, nil
+}
+
+func (w *webhook) Get(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error) {
+	webhook, err := w.getOwned(ctx, param)
+	if err != nil {
+		return webhook, err
+	}
+
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+func (w *webhook) Delete(ctx context.Context, param entity.WebhookParam) error {
+	webhook, err := w.getOwned(ctx, param)
+	if err != nil {
+		return err
+	}
+
+	userID := strconv.FormatInt(int64(appcontext.GetUserId(ctx)), 10)
+
+	return w.webhook.Update(ctx, entity.WebhookUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(userID),
+	}, entity.WebhookParam{
+		ID: webhook.ID,
+	})
+}
+
+func (w *webhook) Enable(ctx context.Context, param entity.WebhookParam) error {
+	webhook, err := w.getOwned(ctx, param)
+	if err != nil {
+		return err
+	}
+
+	userID := strconv.FormatInt(int64(appcontext.GetUserId(ctx)), 10)
+
+	return w.webhook.Update(ctx, entity.WebhookUpdateParam{
+		FailureCount: null.Int64From(0),
+		IsDisabled:   null.BoolFrom(false),
+		UpdatedAt:    null.TimeFrom(Now()),
+		UpdatedBy:    null.StringFrom(userID),
+	}, entity.WebhookParam{
+		ID: webhook.ID,
+	})
+}
+
+func (w *webhook) GetDeliveryList(ctx context.Context, param entity.WebhookDeliveryParam) ([]entity.WebhookDelivery, *entity.Pagination, error) {
+	_, err := w.getOwned(ctx, entity.WebhookParam{ID: param.WebhookID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	param.IncludePagination = true
+
+	return w.webhook.GetDeliveryList(ctx, param)
+}
+
+// Dispatch queues a delivery of the event for every active webhook subscribed to it, the
+// webhook-delivery job sends them so a slow receiver never holds up the relay. Queueing is
+// keyed by event id, an event relayed twice is delivered once.
+func (w *webhook) Dispatch(ctx context.Context, event entity.Event) error {
+	webhooks, _, err := w.webhook.GetList(ctx, entity.WebhookParam{
+		ScopeType: event.ScopeType,
+		ScopeID:   event.ScopeID,
+		QueryOption: query.Option{
+			IsActive:     true,
+			DisableLimit: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	payload, err := w.json.Marshal(event)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeMarshal, err.Error())
+	}
+
+	// a webhook acts for its owner, so it does not hear about users on either side of a block with them
+	blockedUserIDs := []int64{}
+	if event.ActorID > 0 && len(webhooks) > 0 {
+		blockedUserIDs, err = w.relation.GetBlockedUserIDs(ctx, event.ActorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, hook := range webhooks {
+		if hook.IsDisabled || !slices.Contains(strings.Split(hook.Events, ","), event.Type) || slices.Contains(blockedUserIDs, hook.UserID) {
+			continue
+		}
+
+		isOwnerActive, err := w.isOwnerActive(ctx, hook)
+		if err != nil {
+			return err
+		} else if !isOwnerActive {
+			w.disable(ctx, hook, "owner is no longer an active member")
+			continue
+		}
+
+		err = w.webhook.CreateDelivery(ctx, entity.WebhookDeliveryInputParam{
+			WebhookID:      hook.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			DeliveryStatus: entity.WebhookDeliveryStatusPending,
+			NextAttemptAt:  null.TimeFrom(Now()),
+			CreatedAt:      null.TimeFrom(Now()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *webhook) getOwned(ctx context.Context, param entity.WebhookParam) (entity.Webhook, error) {
+	webhook, err := w.webhook.Get(ctx, entity.WebhookParam{
+		ID:     param.ID,
+		UserID: int64(appcontext.GetUserId(ctx)),
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return webhook, errors.NewWithCode(codes.CodeNotFound, "webhook not found")
+	} else if err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+// deliverDue sends the deliveries that are due, a failed attempt is retried on a later run
+// after a backoff until the attempts run out
+func (w *webhook) deliverDue(ctx context.Context) error {
+	deliveries, err := w.webhook.GetDueDeliveryList(ctx, Now(), w.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	sem := make(chan struct{}, w.cfg.Concurrency)
+	wg := sync.WaitGroup{}
+	for _, delivery := range deliveries {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(delivery entity.WebhookDelivery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			w.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (w *webhook) deliver(ctx context.Context, delivery entity.WebhookDelivery) {
+	attempt := delivery.Attempt + 1
+	updateParam := entity.WebhookDeliveryUpdateParam{
+		Attempt:   null.Int64From(attempt),
+		UpdatedAt: null.TimeFrom(Now()),
+	}
+
+	hook, err := w.webhook.Get(ctx, entity.WebhookParam{
+		ID: delivery.WebhookID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist {
+		// the delivery stays due and is picked up by the next run
+		w.log.Error(ctx, err)
+		return
+	}
+
+	isOwnerActive := false
+	if err == nil && !hook.IsDisabled {
+		// the owner may have left, been erased or suspended since the delivery was queued
+		isOwnerActive, err = w.isOwnerActive(ctx, hook)
+		if err != nil {
+			w.log.Error(ctx, err)
+			return
+		}
+	}
+
+	switch {
+	case err != nil:
+		updateParam.DeliveryStatus = null.StringFrom(entity.WebhookDeliveryStatusFailed)
+		updateParam.ErrorMessage = null.StringFrom("webhook was deleted")
+	case hook.IsDisabled:
+		updateParam.DeliveryStatus = null.StringFrom(entity.WebhookDeliveryStatusFailed)
+		updateParam.ErrorMessage = null.StringFrom("webhook is disabled")
+	case !isOwnerActive:
+		updateParam.DeliveryStatus = null.StringFrom(entity.WebhookDeliveryStatusFailed)
+		updateParam.ErrorMessage = null.StringFrom("webhook owner is no longer an active member")
+		w.disable(ctx, hook, "owner is no longer an active member")
+	default:
+		start := Now()
+		statusCode, err := w.send(ctx, hook, entity.Event{ID: delivery.EventID, Type: delivery.EventType}, []byte(delivery.Payload))
+		updateParam.ResponseCode = null.Int64From(int64(statusCode))
+		updateParam.DurationMs = null.Int64From(Now().Sub(start).Milliseconds())
+
+		switch {
+		case err == nil:
+			updateParam.DeliveryStatus = null.StringFrom(entity.WebhookDeliveryStatusSuccess)
+			w.resetFailure(ctx, hook)
+		case attempt >= int64(w.cfg.MaxAttempt):
+			updateParam.DeliveryStatus = null.StringFrom(entity.WebhookDeliveryStatusFailed)
+			updateParam.ErrorMessage = null.StringFrom(err.Error())
+			w.recordFailure(ctx, hook)
+		default:
+			updateParam.NextAttemptAt = null.TimeFrom(Now().Add(w.backoff(int(attempt))))
+			updateParam.ErrorMessage = null.StringFrom(err.Error())
+		}
+	}
+
+	err = w.webhook.UpdateDelivery(ctx, updateParam, entity.WebhookDeliveryParam{ID: delivery.ID})
+	if err != nil {
+		w.log.Error(ctx, err)
+	}
+}
+
+func (w *webhook) send(ctx context.Context, hook entity.Webhook, event entity.Event, payload []byte) (int, error) {
+	timestamp := Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(entity.WebhookEventHeader, event.Type)
+	req.Header.Set(entity.WebhookEventIDHeader, event.ID)
+	req.Header.Set(entity.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(entity.WebhookSignatureHeader, Sign(hook.Secret, timestamp, payload))
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff doubles the configured interval on every attempt
+func (w *webhook) backoff(attempt int) time.Duration {
+	return w.cfg.BackoffInterval * time.Duration(1<<(attempt-1))
+}
+
+func (w *webhook) resetFailure(ctx context.Context, hook entity.Webhook) {
+	if hook.FailureCount == 0 {
+		return
+	}
+
+	err := w.webhook.Update(ctx, entity.WebhookUpdateParam{
+		FailureCount: null.Int64From(0),
+		UpdatedAt:    null.TimeFrom(Now()),
+	}, entity.WebhookParam{
+		ID: hook.ID,
+	})
+	if err != nil {
+		w.log.Error(ctx, err)
+	}
+}
+
+func (w *webhook) recordFailure(ctx context.Context, hook entity.Webhook) {
+	// re-read the counter since other deliveries may have updated it meanwhile
+	current, err := w.webhook.Get(ctx, entity.WebhookParam{
+		ID:          hook.ID,
+		BypassCache: true,
+	})
+	if err != nil {
+		w.log.Error(ctx, err)
+		return
+	}
+
+	updateParam := entity.WebhookUpdateParam{
+		FailureCount: null.Int64From(current.FailureCount + 1),
+		UpdatedAt:    null.TimeFrom(Now()),
+	}
+
+	if current.FailureCount+1 >= w.cfg.MaxFailure {
+		updateParam.IsDisabled = null.BoolFrom(true)
+		updateParam.DisabledAt = null.TimeFrom(Now())
+		w.log.Info(ctx, fmt.Sprintf("webhook %v disabled after %v consecutive failures", hook.ID, current.FailureCount+1))
+	}
+
+	err = w.webhook.Update(ctx, updateParam, entity.WebhookParam{
+		ID: hook.ID,
+	})
+	if err != nil {
+		w.log.Error(ctx, err)
+	}
+}
+
+// isOwnerActive reports whether the owner of the webhook is still an active user and a member of its workspace
+func (w *webhook) isOwnerActive(ctx context.Context, hook entity.Webhook) (bool, error) {
+	_, err := w.user.Get(ctx, entity.UserParam{
+		ID: hook.UserID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = w.workspace.EnsureMember(ctx, hook.ScopeID, hook.UserID)
+	if err != nil && errors.GetCode(err) == codes.CodeNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// disable stops a webhook from receiving events, its owner can enable it again once the cause is resolved
+func (w *webhook) disable(ctx context.Context, hook entity.Webhook, reason string) {
+	err := w.webhook.Update(ctx, entity.WebhookUpdateParam{
+		IsDisabled: null.BoolFrom(true),
+		DisabledAt: null.TimeFrom(Now()),
+		UpdatedAt:  null.TimeFrom(Now()),
+	}, entity.WebhookParam{
+		ID: hook.ID,
+	})
+	if err != nil {
+		w.log.Error(ctx, err)
+		return
+	}
+
+	w.log.Info(ctx, fmt.Sprintf("webhook %v disabled, %s", hook.ID, reason))
+}
+
+func (w *webhook) validateInput(ctx context.Context, inputParam entity.WebhookInputParam) error {
+	// workspaces are the only scope whose admins can be checked, conversation membership is not tracked
+	if inputParam.ScopeType != entity.WebhookScopeWorkspace {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid scope type")
+	}
+
+	if inputParam.ScopeID < 1 {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid scope id")
+	}
+
+	u, err := url.ParseRequestURI(inputParam.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NewWithCode(codes.CodeBadRequest, "invalid webhook url")
+	}
+
+	err = checkHost(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+
+	if len(inputParam.Events) == 0 {
+		return errors.NewWithCode(codes.CodeBadRequest, "at least one event is required")
+	}
+
+	for _, event := range inputParam.Events {
+		if !slices.Contains(entity.EventTypes, event) {
+			return errors.NewWithCode(codes.CodeBadRequest, "unknown event %s", event)
+		}
+	}
+
+	return nil
+}
+
+// checkHost rejects hosts that resolve to an address webhooks may not reach
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if isBlockedIP(ip) {
+			return errors.NewWithCode(codes.CodeBadRequest, errBlockedAddress.Error())
+		}
+		return nil
+	}
+
+	addrs, err := LookupIPAddr(ctx, host)
+	if err != nil {
+		return errors.NewWithCode(codes.CodeBadRequest, "webhook host %s cannot be resolved", host)
+	}
+
+	for _, addr := range addrs {
+		if isBlockedIP(addr.IP) {
+			return errors.NewWithCode(codes.CodeBadRequest, errBlockedAddress.Error())
+		}
+	}
+
+	return nil
+}
+
+// guardDial is called with the resolved address of every connection a delivery makes
+func guardDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+		return errBlockedAddress
+	}
+
+	return nil
+}
+
+// isBlockedIP reports whether ip is internal to the network the service runs in
+func isBlockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// Sign returns the value of the signature header, a hex encoded HMAC-SHA256 of
+// "<timestamp>.<payload>" keyed with the webhook secret.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.NewWithCode(codes.CodeInternalServerError, err.Error())
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Sign(t *testing.T) {
+	type args struct {
+		secret    string
+		timestamp int64
+		payload   []byte
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "sign payload",
+			args: args{
+				secret:    "my-secret",
+				timestamp: 1718965949,
+				payload:   []byte(`{"id":"event-id","type":"message.created"}`),
+			},
+			want: "sha256=067c15e7c3e73bc6c637d7de0c753fc13c7690bfcec50f05155a0183a555e5a9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sign(tt.args.secret, tt.args.timestamp, tt.args.payload)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_webhook_backoff(t *testing.T) {
+	w := &webhook{cfg: config.WebhookConfig{BackoffInterval: time.Second}}
+
+	assert.Equal(t, time.Second, w.backoff(1))
+	assert.Equal(t, 2*time.Second, w.backoff(2))
+	assert.Equal(t, 4*time.Second, w.backoff(3))
+}
+
+func Test_webhook_send(t *testing.T) {
+	mockTime := time.Date(2024, 6, 21, 10, 32, 29, 0, time.UTC)
+	Now = func() time.Time { return mockTime }
+	defer func() { Now = time.Now }()
+
+	mockEvent := entity.Event{
+		ID:        "event-id",
+		Type:      entity.EventMemberJoined,
+		ScopeType: entity.WebhookScopeWorkspace,
+		ScopeID:   1,
+	}
+	mockPayload := []byte(`{"id":"event-id","type":"member.joined"}`)
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+
+	tests := []struct {
+		name           string
+		receiverStatus int
+		wantStatus     int
+		wantErr        bool
+	}{
+		{
+			name:           "receiver rejected delivery",
+			receiverStatus: http.StatusInternalServerError,
+			wantStatus:     http.StatusInternalServerError,
+			wantErr:        true,
+		},
+		{
+			name:           "receiver redirected delivery",
+			receiverStatus: http.StatusMovedPermanently,
+			wantStatus:     http.StatusMovedPermanently,
+			wantErr:        true,
+		},
+		{
+			name:           "success",
+			receiverStatus: http.StatusNoContent,
+			wantStatus:     http.StatusNoContent,
+			wantErr:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := received{}
+			receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				got.header = req.Header.Clone()
+				got.body, _ = io.ReadAll(req.Body)
+				rw.WriteHeader(tt.receiverStatus)
+			}))
+			defer receiver.Close()
+
+			hook := entity.Webhook{ID: 1, URL: receiver.URL, Secret: "my-secret"}
+			w := &webhook{httpClient: receiver.Client(), cfg: config.WebhookConfig{}}
+
+			status, err := w.send(context.Background(), hook, mockEvent, mockPayload)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhook.send() err %v, wantErr %v", err, tt.wantErr)
+			}
+
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, mockPayload, got.body)
+			assert.Equal(t, entity.EventMemberJoined, got.header.Get(entity.WebhookEventHeader))
+			assert.Equal(t, "event-id", got.header.Get(entity.WebhookEventIDHeader))
+			assert.Equal(t, strconv.FormatInt(mockTime.Unix(), 10), got.header.Get(entity.WebhookTimestampHeader))
+			assert.Equal(t, Sign("my-secret", mockTime.Unix(), mockPayload), got.header.Get(entity.WebhookSignatureHeader))
+		})
+	}
+}
+
+func Test_webhook_validateInput(t *testing.T) {
+	LookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		addrs := map[string]string{
+			"hooks.example.com": "93.184.216.34",
+			"internal.example":  "10.0.0.7",
+			"metadata.example":  "169.254.169.254",
+			"localhost":         "127.0.0.1",
+		}
+		if addr, ok := addrs[host]; ok {
+			return []net.IPAddr{{IP: net.ParseIP(addr)}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host}
+	}
+	defer func() { LookupIPAddr = net.DefaultResolver.LookupIPAddr }()
+
+	valid := entity.WebhookInputParam{
+		ScopeType: entity.WebhookScopeWorkspace,
+		ScopeID:   1,
+		URL:       "https://hooks.example.com/receive",
+		Events:    []string{entity.EventMemberJoined},
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(p *entity.WebhookInputParam)
+		wantErr bool
+	}{
+		{
+			name:    "public host",
+			mutate:  func(p *entity.WebhookInputParam) {},
+			wantErr: false,
+		},
+		{
+			name:    "conversation scope",
+			mutate:  func(p *entity.WebhookInputParam) { p.ScopeType = "conversation" },
+			wantErr: true,
+		},
+		{
+			name:    "private address",
+			mutate:  func(p *entity.WebhookInputParam) { p.URL = "https://internal.example/receive" },
+			wantErr: true,
+		},
+		{
+			name:    "link-local address",
+			mutate:  func(p *entity.WebhookInputParam) { p.URL = "http://metadata.example/latest" },
+			wantErr: true,
+		},
+		{
+			name:    "loopback address",
+			mutate:  func(p *entity.WebhookInputParam) { p.URL = "http://localhost:8080/receive" },
+			wantErr: true,
+		},
+		{
+			name:    "loopback literal",
+			mutate:  func(p *entity.WebhookInputParam) { p.URL = "http://[::1]/receive" },
+			wantErr: true,
+		},
+		{
+			name:    "unresolvable host",
+			mutate:  func(p *entity.WebhookInputParam) { p.URL = "https://unknown.example/receive" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputParam := valid
+			tt.mutate(&inputParam)
+
+			w := &webhook{}
+			err := w.validateInput(context.Background(), inputParam)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhook.validateInput() err %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_guardDial(t *testing.T) {
+	assert.NoError(t, guardDial("tcp", "93.184.216.34:443", nil))
+	assert.Error(t, guardDial("tcp", "127.0.0.1:80", nil))
+	assert.Error(t, guardDial("tcp", "[fe80::1]:80", nil))
+	assert.Error(t, guardDial("tcp", "192.168.1.10:443", nil))
+	assert.Error(t, guardDial("tcp", "0.0.0.0:80", nil))
+}
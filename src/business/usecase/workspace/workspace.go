@@ -0,0 +1,265 @@
+package workspace
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/appcontext"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichiels/go-pkg/errors"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichiels/go-pkg/query"
+	userDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/user"
+	workspaceDomain "github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/domain/workspace"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/reqctx"
+)
+
+var Now = time.Now
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+type Interface interface {
+	Create(ctx context.Context, inputParam entity.WorkspaceInputParam) (entity.Workspace, error)
+	GetList(ctx context.Context) ([]entity.Workspace, error)
+	// Get returns the workspace the request is scoped to
+	Get(ctx context.Context) (entity.Workspace, error)
+	// VerifyMember returns the membership of the current user, it fails with not found for non members
+	VerifyMember(ctx context.Context, workspaceID int64) (entity.WorkspaceMember, error)
+	GetMemberList(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, *entity.Pagination, error)
+	AddMember(ctx context.Context, inputParam entity.WorkspaceMemberInputParam) (entity.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, param entity.WorkspaceMemberParam) error
+	// EnsureMember fails with not found for users that are not an active member of the workspace
+	EnsureMember(ctx context.Context, workspaceID, userID int64) error
+	// EnsureAdmin fails with not found for non members and forbidden for members below admin
+	EnsureAdmin(ctx context.Context, workspaceID, userID int64) error
+}
+
+type workspace struct {
+	workspace workspaceDomain.Interface
+	user      userDomain.Interface
+}
+
+type InitParam struct {
+	WorkspaceDomain workspaceDomain.Interface
+	UserDomain      userDomain.Interface
+}
+
+func Init(param InitParam) Interface {
+	return &workspace{
+		workspace: param.WorkspaceDomain,
+		user:      param.UserDomain,
+	}
+}
+
+func (w *workspace) Create(ctx context.Context, inputParam entity.WorkspaceInputParam) (entity.Workspace, error) {
+	inputParam.Name = strings.TrimSpace(inputParam.Name)
+	if inputParam.Name == "" {
+		return entity.Workspace{}, errors.NewWithCode(codes.CodeBadRequest, "workspace name is required")
+	}
+
+	if !slugPattern.MatchString(inputParam.Slug) {
+		return entity.Workspace{}, errors.NewWithCode(codes.CodeBadRequest, "slug must be 3-50 lowercase letters, digits or dashes")
+	}
+
+	userID := int64(appcontext.GetUserId(ctx))
+	inputParam.OwnerID = userID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	workspace, err := w.workspace.Create(ctx, inputParam)
+	if err != nil && errors.GetCode(err) == codes.CodeSQLUniqueConstraint {
+		return workspace, errors.NewWithCode(codes.CodeConflict, "slug already used")
+	} else if err != nil {
+		return workspace, err
+	}
+
+	return workspace, nil
+}
+
+func (w *workspace) GetList(ctx context.Context) ([]entity.Workspace, error) {
+	return w.workspace.GetListByMember(ctx, int64(appcontext.GetUserId(ctx)))
+}
+
+func (w *workspace) Get(ctx context.Context) (entity.Workspace, error) {
+	workspace, err := w.workspace.Get(ctx, entity.WorkspaceParam{
+		ID: reqctx.GetWorkspaceID(ctx),
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return workspace, errors.NewWithCode(codes.CodeNotFound, "workspace not found")
+	} else if err != nil {
+		return workspace, err
+	}
+
+	return workspace, nil
+}
+
+func (w *workspace) VerifyMember(ctx context.Context, workspaceID int64) (entity.WorkspaceMember, error) {
+	member, err := w.workspace.GetMember(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: workspaceID,
+		UserID:      int64(appcontext.GetUserId(ctx)),
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		// non members get the same error as a missing workspace so ids cannot be probed
+		return member, errors.NewWithCode(codes.CodeNotFound, "workspace not found")
+	} else if err != nil {
+		return member, err
+	}
+
+	return member, nil
+}
+
+func (w *workspace) GetMemberList(ctx context.Context, param entity.WorkspaceMemberParam) ([]entity.WorkspaceMember, *entity.Pagination, error) {
+	param.WorkspaceID = reqctx.GetWorkspaceID(ctx)
+	param.QueryOption.IsActive = true
+	param.IncludePagination = true
+
+	return w.workspace.GetMemberList(ctx, param)
+}
+
+func (w *workspace) AddMember(ctx context.Context, inputParam entity.WorkspaceMemberInputParam) (entity.WorkspaceMember, error) {
+	member := entity.WorkspaceMember{}
+	workspaceID := reqctx.GetWorkspaceID(ctx)
+	userID := int64(appcontext.GetUserId(ctx))
+
+	err := w.EnsureAdmin(ctx, workspaceID, userID)
+	if err != nil {
+		return member, err
+	}
+
+	if inputParam.MemberRole == "" {
+		inputParam.MemberRole = entity.WorkspaceRoleMember
+	}
+
+	if inputParam.MemberRole != entity.WorkspaceRoleMember && inputParam.MemberRole != entity.WorkspaceRoleAdmin {
+		return member, errors.NewWithCode(codes.CodeBadRequest, "invalid role %s", inputParam.MemberRole)
+	}
+
+	_, err = w.user.Get(ctx, entity.UserParam{
+		ID: inputParam.UserID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return member, errors.NewWithCode(codes.CodeNotFound, "user not found")
+	} else if err != nil {
+		return member, err
+	}
+
+	// a removed member is restored instead of inserted again
+	member, err = w.workspace.GetMember(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: workspaceID,
+		UserID:      inputParam.UserID,
+		BypassCache: true,
+	})
+	switch {
+	case err == nil && member.Status == 1:
+		return member, errors.NewWithCode(codes.CodeConflict, "user is already a member")
+	case err == nil:
+		member.MemberRole = inputParam.MemberRole
+		err = w.workspace.RestoreMember(ctx, member, Now(), strconv.FormatInt(userID, 10))
+		if err != nil {
+			return member, err
+		}
+
+		member.Status = 1
+		member.DeletedAt = null.Time{}
+		member.DeletedBy = null.String{}
+		return member, nil
+	case errors.GetCode(err) != codes.CodeSQLRecordDoesNotExist:
+		return member, err
+	}
+
+	inputParam.WorkspaceID = workspaceID
+	inputParam.CreatedAt = null.TimeFrom(Now())
+	inputParam.CreatedBy = null.StringFrom(strconv.FormatInt(userID, 10))
+
+	return w.workspace.CreateMember(ctx, inputParam)
+}
+
+func (w *workspace) RemoveMember(ctx context.Context, param entity.WorkspaceMemberParam) error {
+	workspaceID := reqctx.GetWorkspaceID(ctx)
+	userID := int64(appcontext.GetUserId(ctx))
+
+	// members can always leave, removing someone else needs admin rights
+	if param.UserID != userID {
+		err := w.EnsureAdmin(ctx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	member, err := w.workspace.GetMember(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: workspaceID,
+		UserID:      param.UserID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "member not found")
+	} else if err != nil {
+		return err
+	}
+
+	if member.MemberRole == entity.WorkspaceRoleOwner {
+		return errors.NewWithCode(codes.CodeForbidden, "workspace owner cannot be removed")
+	}
+
+	return w.workspace.UpdateMember(ctx, entity.WorkspaceMemberUpdateParam{
+		Status:    null.Int64From(0),
+		DeletedAt: null.TimeFrom(Now()),
+		DeletedBy: null.StringFrom(strconv.FormatInt(userID, 10)),
+	}, entity.WorkspaceMemberParam{
+		ID:          member.ID,
+		WorkspaceID: workspaceID,
+		UserID:      member.UserID,
+	})
+}
+
+func (w *workspace) EnsureMember(ctx context.Context, workspaceID, userID int64) error {
+	_, err := w.workspace.GetMember(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		return errors.NewWithCode(codes.CodeNotFound, "workspace not found")
+	}
+
+	return err
+}
+
+func (w *workspace) EnsureAdmin(ctx context.Context, workspaceID, userID int64) error {
+	member, err := w.workspace.GetMember(ctx, entity.WorkspaceMemberParam{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		QueryOption: query.Option{
+			IsActive: true,
+		},
+	})
+	if err != nil && errors.GetCode(err) == codes.CodeSQLRecordDoesNotExist {
+		// non members get the same error as a missing workspace so ids cannot be probed
+		return errors.NewWithCode(codes.CodeNotFound, "workspace not found")
+	} else if err != nil {
+		return err
+	}
+
+	if member.MemberRole != entity.WorkspaceRoleOwner && member.MemberRole != entity.WorkspaceRoleAdmin {
+		return errors.NewWithCode(codes.CodeForbidden, "workspace admin role required")
+	}
+
+	return nil
+}
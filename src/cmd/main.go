@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"errors"
+	"os/signal"
+	"syscall"
 
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/reyhanmichiels/go-pkg/auth"
 	"github.com/reyhanmichiels/go-pkg/configreader"
 	"github.com/reyhanmichiels/go-pkg/files"
@@ -17,6 +20,10 @@ import (
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/handler/rest"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/mail"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/push"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
 )
 
 // @contact.name   Reyhan Hafiz Rusyard
@@ -60,6 +67,12 @@ func main() {
 	// init cache
 	cache := redis.Init(cfg.Redis, log)
 
+	// init redis stream client, the cache client only covers key value commands
+	stream := goredis.NewClient(&goredis.Options{Addr: cfg.Stream.Address, Password: cfg.Stream.Password, DB: cfg.Stream.DB})
+
+	// blocking stream reads hold a connection each, they get a separate bounded pool
+	streamWait := goredis.NewClient(&goredis.Options{Addr: cfg.Stream.Address, Password: cfg.Stream.Password, DB: cfg.Stream.DB, PoolSize: cfg.Stream.WaitPoolSize, PoolTimeout: cfg.Stream.WaitPoolTimeout})
+
 	// init db
 	db := sql.Init(cfg.SQL, log)
 
@@ -70,7 +83,7 @@ func main() {
 	parser := parser.InitParser(log, cfg.Parser)
 
 	// init domain
-	dom := domain.Init(domain.InitParam{Log: log, Db: db, Redis: cache, Json: parser.JSONParser()})
+	dom := domain.Init(domain.InitParam{Log: log, Db: db, Redis: cache, Json: parser.JSONParser(), Stream: stream, StreamWait: streamWait})
 
 	// hash
 	hash := hash.Init()
@@ -78,12 +91,34 @@ func main() {
 	// auth
 	auth := auth.Init(cfg.Auth, log)
 
+	// push provider
+	push := push.Init(cfg.Push, log)
+
+	// mail sender
+	mail := mail.Init(cfg.Mail, log)
+
+	// distributed lock, keeps scheduled jobs to a single replica and retried requests to a single run
+	locker := locker.Init(cfg.Locker, log)
+
+	// job scheduler, usecases register their background jobs on init
+	scheduler := scheduler.Init(cfg.Scheduler, log, locker)
+
 	// init usecase
-	uc := usecase.Init(usecase.InitParam{Dom: dom, Log: log, Json: parser.JSONParser(), Hash: hash, Auth: auth})
+	uc := usecase.Init(usecase.InitParam{Dom: dom, Log: log, Json: parser.JSONParser(), Hash: hash, Auth: auth, Push: push, Mail: mail, Scheduler: scheduler, Locker: locker, Webhook: cfg.Webhook, Outbox: cfg.Outbox, Audit: cfg.Audit, Contact: cfg.Contact, Presence: cfg.Presence, Notification: cfg.Notification, Digest: cfg.Digest, Realtime: cfg.Realtime, Idempotency: cfg.Idempotency, Retention: cfg.Retention, Export: cfg.Export})
 
 	// init http server
-	r := rest.Init(rest.InitParam{Uc: uc, GinConfig: cfg.Gin, Log: log, RateLimiter: rateLimiter, Json: parser.JSONParser(), Auth: auth})
+	r := rest.Init(rest.InitParam{Uc: uc, GinConfig: cfg.Gin, Log: log, RateLimiter: rateLimiter, Json: parser.JSONParser(), Auth: auth, Scheduler: scheduler})
+
+	// the http server and the scheduler stop on the same shutdown signal
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// run background jobs
+	scheduler.Start(ctx)
 
 	// run http server
-	r.Run()
+	r.Run(ctx)
+
+	// let the jobs in flight finish before exiting
+	scheduler.Wait()
 }
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Get Audit List
+// @Description Get Audit Trail of Security Relevant Actions
+// @Security BearerAuth
+// @Tags Admin
+// @Param actorID query integer false "actor id"
+// @Param action query string false "action"
+// @Param targetType query string false "target type"
+// @Param targetID query integer false "target id"
+// @Param requestID query string false "request id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Audit{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/audits [GET]
+func (r *rest) GetAuditList(ctx *gin.Context) {
+	var param entity.AuditParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	audits, pg, err := r.uc.Audit.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, audits, pg)
+}
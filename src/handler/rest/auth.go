@@ -15,7 +15,9 @@ import (
 // @Produce json
 // @Success 200 {object} entity.HTTPResp{data=entity.User{}}
 // @Failure 400 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
 // @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
 // @Failure 500 {object} entity.HTTPResp{}
 // @Router /auth/v1/register [POST]
 func (r *rest) RegisterNewUser(ctx *gin.Context) {
@@ -43,6 +45,8 @@ func (r *rest) RegisterNewUser(ctx *gin.Context) {
 // @Produce json
 // @Success 200 {object} entity.HTTPResp{data=entity.UserLoginResponse{}}
 // @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
 // @Failure 404 {object} entity.HTTPResp{}
 // @Failure 500 {object} entity.HTTPResp{}
 // @Router /auth/v1/login [POST]
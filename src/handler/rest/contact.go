@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Get Contact List
+// @Description Get Accepted Contacts of Current User with Their Presence, Blocked Users Are Left Out
+// @Security BearerAuth
+// @Tags Contact
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.ContactUser{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts [GET]
+func (r *rest) GetContactList(ctx *gin.Context) {
+	var param entity.ContactUserParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	contacts, pg, err := r.uc.Contact.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, contacts, pg)
+}
+
+// @Summary Send Contact Request
+// @Description Send a Friend Request to Another User
+// @Security BearerAuth
+// @Tags Contact
+// @Param data body entity.ContactInputParam true "Contact Request Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Contact{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts/requests [POST]
+func (r *rest) SendContactRequest(ctx *gin.Context) {
+	var param entity.ContactInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	contact, err := r.uc.Contact.SendRequest(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, contact, nil)
+}
+
+// @Summary Get Contact Request List
+// @Description Get Pending Incoming or Outgoing Friend Requests
+// @Security BearerAuth
+// @Tags Contact
+// @Param direction query string false "incoming or outgoing, default incoming"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Contact{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts/requests [GET]
+func (r *rest) GetContactRequestList(ctx *gin.Context) {
+	var param entity.ContactParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	contacts, pg, err := r.uc.Contact.GetRequestList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, contacts, pg)
+}
+
+// @Summary Accept Contact Request
+// @Description Accept an Incoming Friend Request
+// @Security BearerAuth
+// @Tags Contact
+// @Param contact_id path integer true "contact request id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts/requests/{contact_id}/accept [PUT]
+func (r *rest) AcceptContactRequest(ctx *gin.Context) {
+	var param entity.ContactParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Contact.Accept(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Decline Contact Request
+// @Description Decline an Incoming Friend Request
+// @Security BearerAuth
+// @Tags Contact
+// @Param contact_id path integer true "contact request id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts/requests/{contact_id}/decline [PUT]
+func (r *rest) DeclineContactRequest(ctx *gin.Context) {
+	var param entity.ContactParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Contact.Decline(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Cancel Contact Request
+// @Description Cancel an Outgoing Friend Request
+// @Security BearerAuth
+// @Tags Contact
+// @Param contact_id path integer true "contact request id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/contacts/requests/{contact_id}/cancel [PUT]
+func (r *rest) CancelContactRequest(ctx *gin.Context) {
+	var param entity.ContactParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Contact.Cancel(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
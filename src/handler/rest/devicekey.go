@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Upload Device Keys
+// @Description Publish the End-to-End Encryption Public Keys of a Device and Add One-Time Prekeys.
+// @Description Only public keys are accepted, a new identity key drops the unclaimed prekeys of the old one.
+// @Security BearerAuth
+// @Tags Encryption
+// @Param device_id path integer true "device id"
+// @Param data body entity.DeviceKeyInputParam true "Device Key Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.DeviceKey{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/devices/{device_id}/keys [PUT]
+func (r *rest) UploadDeviceKey(ctx *gin.Context) {
+	var param entity.DeviceKeyInputParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	deviceKey, err := r.uc.DeviceKey.Upload(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, deviceKey, nil)
+}
+
+// @Summary Get Device Keys
+// @Description Get the Published Keys of a Device and the Number of One-Time Prekeys Left
+// @Security BearerAuth
+// @Tags Encryption
+// @Param device_id path integer true "device id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.DeviceKey{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/devices/{device_id}/keys [GET]
+func (r *rest) GetDeviceKey(ctx *gin.Context) {
+	var param entity.DeviceKeyParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	deviceKey, err := r.uc.DeviceKey.Get(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, deviceKey, nil)
+}
+
+// @Summary Get Key Bundle List
+// @Description Get a Prekey Bundle for Every Device of a User to Start Encrypted Sessions.
+// @Description Each call hands out one one-time prekey per device, devices without one are returned without it.
+// @Security BearerAuth
+// @Tags Encryption
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.KeyBundle{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/users/{user_id}/keys [GET]
+func (r *rest) GetKeyBundleList(ctx *gin.Context) {
+	var param entity.KeyBundleParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	bundles, err := r.uc.DeviceKey.GetBundleList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, bundles, nil)
+}
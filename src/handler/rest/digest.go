@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Unsubscribe Digest
+// @Description Turn Off Email Digest Using the Signed Link From a Digest Email
+// @Tags Digest
+// @Param token query string true "unsubscribe token"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /public/v1/digest/unsubscribe [GET]
+func (r *rest) UnsubscribeDigest(ctx *gin.Context) {
+	var param entity.DigestUnsubscribeParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Digest.Unsubscribe(ctx.Request.Context(), param.Token)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+const (
+	headerLastEventID  = "Last-Event-ID"
+	eventStreamPath    = "/v1/events"
+	eventHeartbeatName = "ping"
+)
+
+// @Summary Stream Events
+// @Description Stream Real-Time Events of Current User as Server-Sent Events, a Fallback for Clients That Cannot Keep a WebSocket Open.
+// @Description Send Last-Event-ID to resume after a reconnect, a stream.reset event means events were missed and the client should resync.
+// @Security BearerAuth
+// @Tags Event
+// @Param Last-Event-ID header string false "id of the last event received"
+// @Produce text/event-stream
+// @Success 200 {object} entity.Event{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/events [GET]
+func (r *rest) StreamEvent(ctx *gin.Context) {
+	c := ctx.Request.Context()
+
+	lastEventID := ctx.GetHeader(headerLastEventID)
+	if lastEventID == "" {
+		cursor, err := r.uc.Realtime.Cursor(c)
+		if err != nil {
+			r.httpRespError(ctx, err)
+			return
+		}
+		lastEventID = cursor
+	}
+
+	// proxies must not buffer the stream
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	for {
+		events, err := r.uc.Realtime.Read(c, lastEventID)
+		if c.Err() != nil {
+			return
+		} else if err != nil {
+			r.log.Error(c, err)
+			return
+		}
+
+		if len(events) == 0 {
+			ctx.Render(-1, sse.Event{Event: eventHeartbeatName, Data: ""})
+		}
+
+		for _, event := range events {
+			ctx.Render(-1, sse.Event{Id: event.ID, Event: event.Event.Type, Data: event.Event})
+			lastEventID = event.ID
+
+			// the token of a suspended user stays valid until it expires, the stream must not outlive the account
+			if event.Event.Type == entity.EventUserSuspended {
+				ctx.Writer.Flush()
+				return
+			}
+		}
+
+		ctx.Writer.Flush()
+	}
+}
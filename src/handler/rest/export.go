@@ -0,0 +1,184 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Export
+// @Description Request a ZIP Export of Everything Stored About a User Within a Date Range, Built by a Background Job
+// @Security BearerAuth
+// @Tags Admin
+// @Param data body entity.ExportInputParam true "Export Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Export{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/exports [POST]
+func (r *rest) CreateExport(ctx *gin.Context) {
+	var param entity.ExportInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	export, err := r.uc.Export.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, export, nil)
+}
+
+// @Summary Get Export List
+// @Description Get Compliance Exports
+// @Security BearerAuth
+// @Tags Admin
+// @Param subjectType query string false "subject type"
+// @Param subjectID query integer false "subject id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Export{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/exports [GET]
+func (r *rest) GetExportList(ctx *gin.Context) {
+	var param entity.ExportParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	exports, pg, err := r.uc.Export.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, exports, pg)
+}
+
+// @Summary Get Export
+// @Description Get Export Status, Finished Exports Include a Signed Download Link
+// @Security BearerAuth
+// @Tags Admin
+// @Param export_id path integer true "export id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Export{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/exports/{export_id} [GET]
+func (r *rest) GetExport(ctx *gin.Context) {
+	var param entity.ExportParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	export, err := r.uc.Export.Get(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, export, nil)
+}
+
+// @Summary Request Own Export
+// @Description Request a Machine Readable Archive of Everything Stored About the Current User, Built by a Background Job
+// @Security BearerAuth
+// @Tags User
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Export{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/export [POST]
+func (r *rest) RequestOwnExport(ctx *gin.Context) {
+	export, err := r.uc.Export.RequestOwn(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, export, nil)
+}
+
+// @Summary Get Own Export
+// @Description Get the Status of an Export of the Current User, Finished Exports Include a Signed Download Link
+// @Security BearerAuth
+// @Tags User
+// @Param export_id path integer true "export id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Export{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/exports/{export_id} [GET]
+func (r *rest) GetOwnExport(ctx *gin.Context) {
+	var param entity.ExportParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	export, err := r.uc.Export.GetOwn(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, export, nil)
+}
+
+// @Summary Download Export
+// @Description Download the ZIP Archive of a Finished Export Using Its Signed Link
+// @Tags Export
+// @Param token query string true "download token"
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /public/v1/exports/download [GET]
+func (r *rest) DownloadExport(ctx *gin.Context) {
+	var param entity.ExportDownloadParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	file, err := r.uc.Export.Download(ctx.Request.Context(), param.Token)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	ctx.DataFromReader(http.StatusOK, file.Size, "application/zip", file.Content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, file.Name),
+	})
+}
@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+)
+
+// @Summary Get Job List
+// @Description Get Schedule and Last Run Status of Background Jobs on the Serving Replica
+// @Security BearerAuth
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]scheduler.Status{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/jobs [GET]
+func (r *rest) GetJobList(ctx *gin.Context) {
+	r.httpRespSuccess(ctx, codes.CodeSuccess, r.scheduler.Status(), nil)
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Legal Hold
+// @Description Put a User or Workspace on Legal Hold, Suspending Retention Purges and Deletes of Its Data
+// @Security BearerAuth
+// @Tags Admin
+// @Param data body entity.LegalHoldInputParam true "Legal Hold Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.LegalHold{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/legal-holds [POST]
+func (r *rest) CreateLegalHold(ctx *gin.Context) {
+	var param entity.LegalHoldInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	legalHold, err := r.uc.LegalHold.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, legalHold, nil)
+}
+
+// @Summary Get Legal Hold List
+// @Description Get Active Legal Holds
+// @Security BearerAuth
+// @Tags Admin
+// @Param scopeType query string false "scope type"
+// @Param scopeID query integer false "scope id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.LegalHold{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/legal-holds [GET]
+func (r *rest) GetLegalHoldList(ctx *gin.Context) {
+	var param entity.LegalHoldParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	legalHolds, pg, err := r.uc.LegalHold.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, legalHolds, pg)
+}
+
+// @Summary Release Legal Hold
+// @Description Release a Legal Hold, Its Scope Is Purged Again by Retention
+// @Security BearerAuth
+// @Tags Admin
+// @Param legal_hold_id path integer true "legal hold id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/legal-holds/{legal_hold_id} [DELETE]
+func (r *rest) ReleaseLegalHold(ctx *gin.Context) {
+	var param entity.LegalHoldParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.LegalHold.Release(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
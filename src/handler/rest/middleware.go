@@ -1,11 +1,16 @@
 package rest
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,11 +22,19 @@ import (
 	"github.com/reyhanmichiels/go-pkg/header"
 	"github.com/reyhanmichiels/go-pkg/query"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase/idempotency"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/reqctx"
 )
 
 const (
 	infoRequest  string = `httpclient Sent Request: uri=%v method=%v`
 	infoResponse string = `httpclient Received Response: uri=%v method=%v resp_code=%v`
+
+	headerContentType        = "Content-Type"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// maxIdempotentBodySize bounds the body buffered for the fingerprint
+	maxIdempotentBodySize = 1 << 20
 )
 
 func (r *rest) CustomRecovery(ctx *gin.Context) {
@@ -52,6 +65,13 @@ func (r *rest) CustomRecovery(ctx *gin.Context) {
 
 // SetTimeout timeout middleware wraps the request context with a timeout
 func (r *rest) SetTimeout(ctx *gin.Context) {
+	// streams stay open until the client leaves
+	if ctx.FullPath() == eventStreamPath {
+		ctx.Request = ctx.Request.WithContext(appcontext.SetRequestStartTime(ctx.Request.Context(), time.Now()))
+		ctx.Next()
+		return
+	}
+
 	// wrap the request context with a timeout
 	c, cancel := context.WithTimeout(ctx.Request.Context(), 1*time.Second)
 
@@ -113,6 +133,13 @@ func (r *rest) addFieldsToContext(ctx *gin.Context) {
 	ctx.Next()
 }
 
+// SetAuditInfo stores request metadata recorded in the audit trail
+func (r *rest) SetAuditInfo(ctx *gin.Context) {
+	c := reqctx.SetClientIP(ctx.Request.Context(), ctx.ClientIP())
+	ctx.Request = ctx.Request.WithContext(c)
+	ctx.Next()
+}
+
 func (r *rest) VerifyUser(ctx *gin.Context) {
 	userID, err := r.verifyUserToken(ctx)
 	if err != nil {
@@ -126,6 +153,12 @@ func (r *rest) VerifyUser(ctx *gin.Context) {
 		return
 	}
 
+	// presence is best effort, it must not fail the request
+	err = r.uc.Presence.Touch(ctx.Request.Context())
+	if err != nil {
+		r.log.Error(ctx.Request.Context(), err)
+	}
+
 	ctx.Next()
 }
 
@@ -171,3 +204,137 @@ func (r *rest) setUserInfo(ctx *gin.Context, userID int64) error {
 
 	return nil
 }
+
+// VerifyAdmin must run after VerifyUser, it rejects users without the admin role
+func (r *rest) VerifyAdmin(ctx *gin.Context) {
+	user, err := r.uc.User.Get(ctx.Request.Context(), entity.UserParam{
+		ID: int64(appcontext.GetUserId(ctx.Request.Context())),
+	})
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	if user.RoleID != entity.RoleIDAdmin {
+		r.httpRespError(ctx, errors.NewWithCode(codes.CodeForbidden, "admin role required"))
+		return
+	}
+
+	ctx.Next()
+}
+
+// VerifyWorkspaceMember must run after VerifyUser, it scopes the request to the workspace in the path
+// and rejects users who are not members of it
+func (r *rest) VerifyWorkspaceMember(ctx *gin.Context) {
+	workspaceID, err := strconv.ParseInt(ctx.Param("workspace_id"), 10, 64)
+	if err != nil {
+		r.httpRespError(ctx, errors.NewWithCode(codes.CodeBadRequest, "invalid workspace id"))
+		return
+	}
+
+	_, err = r.uc.Workspace.VerifyMember(ctx.Request.Context(), workspaceID)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	c := reqctx.SetWorkspaceID(ctx.Request.Context(), workspaceID)
+	ctx.Request = ctx.Request.WithContext(c)
+
+	ctx.Next()
+}
+
+// Idempotent must run after VerifyUser, a POST retried with the same Idempotency-Key header gets the
+// response of the first attempt instead of running the handler again. Requests without the header pass through
+func (r *rest) Idempotent(ctx *gin.Context) {
+	key := ctx.GetHeader(entity.HeaderIdempotencyKey)
+	if ctx.Request.Method != http.MethodPost || key == "" {
+		ctx.Next()
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		r.httpRespError(ctx, errors.NewWithCode(codes.CodeBadRequest, "idempotency key must be at most %d characters", maxIdempotencyKeyLength))
+		return
+	}
+
+	// the body is read for the fingerprint and restored for the handler
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxIdempotentBodySize))
+	if _, ok := err.(*http.MaxBytesError); ok {
+		r.httpRespError(ctx, errors.NewWithCode(codes.CodeBadRequest, "request body must be at most %d bytes", maxIdempotentBodySize))
+		return
+	} else if err != nil {
+		r.httpRespError(ctx, errors.NewWithCode(codes.CodeBadRequest, err.Error()))
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	fingerprint := sha256.Sum256(body)
+	param := entity.IdempotencyParam{
+		Key:         key,
+		Method:      ctx.Request.Method,
+		Path:        ctx.Request.URL.Path,
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+	}
+
+	lock, err := r.uc.Idempotency.Lock(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	defer func() {
+		// the request context may be done by now, the lock must still be released
+		if err := lock.Release(context.WithoutCancel(ctx.Request.Context())); err != nil {
+			r.log.Error(ctx.Request.Context(), err)
+		}
+	}()
+
+	response, err := r.uc.Idempotency.Get(ctx.Request.Context(), param)
+	if err == nil {
+		ctx.Header(headerIdempotentReplayed, "true")
+		ctx.Data(response.StatusCode, response.ContentType, response.Body)
+		ctx.Abort()
+		return
+	} else if errors.GetCode(err) != codes.CodeNotFound {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	writer := &idempotentWriter{ResponseWriter: ctx.Writer}
+	ctx.Writer = writer
+
+	ctx.Next()
+
+	// only final responses are stored, anything a retry could change is left for the client to retry
+	if !writer.Written() || !idempotency.IsReplayable(writer.Status()) {
+		return
+	}
+
+	// the handler may have committed after the request timed out, its response is saved regardless
+	// so a retry replays it instead of running the handler again
+	err = r.uc.Idempotency.Save(context.WithoutCancel(ctx.Request.Context()), param, entity.IdempotentResponse{
+		StatusCode:  writer.Status(),
+		ContentType: writer.Header().Get(headerContentType),
+		Body:        writer.body.Bytes(),
+	})
+	if err != nil {
+		r.log.Error(ctx.Request.Context(), err)
+	}
+}
+
+// idempotentWriter keeps a copy of the response body so it can be replayed
+type idempotentWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotentWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotentWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Get Notification List
+// @Description Get In-App Notification Feed of Current User
+// @Security BearerAuth
+// @Tags Notification
+// @Param readStatus query string false "unread or read"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Notification{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/notifications [GET]
+func (r *rest) GetNotificationList(ctx *gin.Context) {
+	var param entity.NotificationParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	notifications, pg, err := r.uc.Notification.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, notifications, pg)
+}
+
+// @Summary Mark Notification Read
+// @Description Mark a Notification as Read
+// @Security BearerAuth
+// @Tags Notification
+// @Param notification_id path integer true "notification id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/notifications/{notification_id}/read [PUT]
+func (r *rest) MarkNotificationRead(ctx *gin.Context) {
+	var param entity.NotificationParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Notification.MarkRead(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Register Device
+// @Description Register a Push Token for Current User
+// @Security BearerAuth
+// @Tags Notification
+// @Param data body entity.DeviceInputParam true "Device Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Device{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/devices [POST]
+func (r *rest) RegisterDevice(ctx *gin.Context) {
+	var param entity.DeviceInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	device, err := r.uc.Notification.RegisterDevice(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, device, nil)
+}
+
+// @Summary Delete Device
+// @Description Stop Sending Push Notifications to a Device
+// @Security BearerAuth
+// @Tags Notification
+// @Param device_id path integer true "device id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/devices/{device_id} [DELETE]
+func (r *rest) DeleteDevice(ctx *gin.Context) {
+	var param entity.DeviceParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Notification.DeleteDevice(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
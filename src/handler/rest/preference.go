@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Get Preference
+// @Description Get Notification Preference of Current User
+// @Security BearerAuth
+// @Tags Preference
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Preference{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/preferences [GET]
+func (r *rest) GetPreference(ctx *gin.Context) {
+	preference, err := r.uc.Preference.Get(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, preference, nil)
+}
+
+// @Summary Update Preference
+// @Description Update Do Not Disturb and Quiet Hours of Current User
+// @Security BearerAuth
+// @Tags Preference
+// @Param data body entity.PreferenceInputParam true "Preference Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Preference{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/preferences [PUT]
+func (r *rest) UpdatePreference(ctx *gin.Context) {
+	var param entity.PreferenceInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	preference, err := r.uc.Preference.Update(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, preference, nil)
+}
+
+// @Summary Get Scope Preference List
+// @Description Get Per Conversation Notification Levels of Current User
+// @Security BearerAuth
+// @Tags Preference
+// @Param scopeType query string false "scope type"
+// @Param scopeID query integer false "scope id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.ScopePreference{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/preferences/scopes [GET]
+func (r *rest) GetScopePreferenceList(ctx *gin.Context) {
+	var param entity.ScopePreferenceParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	scopes, pg, err := r.uc.Preference.GetScopeList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, scopes, pg)
+}
+
+// @Summary Update Scope Preference
+// @Description Set Notification Level (all, mentions, none) for a Workspace the User is a Member of
+// @Security BearerAuth
+// @Tags Preference
+// @Param data body entity.ScopePreferenceInputParam true "Scope Preference Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/me/preferences/scopes [PUT]
+func (r *rest) UpdateScopePreference(ctx *gin.Context) {
+	var param entity.ScopePreferenceInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Preference.UpdateScope(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
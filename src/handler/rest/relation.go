@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Relation
+// @Description Block a User or Mute a User or Conversation
+// @Security BearerAuth
+// @Tags Relation
+// @Param data body entity.RelationInputParam true "Relation Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Relation{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/relations [POST]
+func (r *rest) CreateRelation(ctx *gin.Context) {
+	var param entity.RelationInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	relation, err := r.uc.Relation.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, relation, nil)
+}
+
+// @Summary Get Relation List
+// @Description Get Blocks and Mutes of Current User
+// @Security BearerAuth
+// @Tags Relation
+// @Param relationType query string false "relation type"
+// @Param targetType query string false "target type"
+// @Param targetID query integer false "target id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Relation{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/relations [GET]
+func (r *rest) GetRelationList(ctx *gin.Context) {
+	var param entity.RelationParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	relations, pg, err := r.uc.Relation.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, relations, pg)
+}
+
+// @Summary Delete Relation
+// @Description Unblock or Unmute
+// @Security BearerAuth
+// @Tags Relation
+// @Param relation_id path integer true "relation id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/relations/{relation_id} [DELETE]
+func (r *rest) DeleteRelation(ctx *gin.Context) {
+	var param entity.RelationParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Relation.Delete(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Report
+// @Description Report a User to the Moderators With a Reason, Reports About the Same User Are Grouped Into One Case
+// @Security BearerAuth
+// @Tags Report
+// @Param data body entity.ReportInputParam true "Report Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Report{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/reports [POST]
+func (r *rest) CreateReport(ctx *gin.Context) {
+	var param entity.ReportInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	report, err := r.uc.Report.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, report, nil)
+}
+
+// @Summary Get Report Case List
+// @Description Get Report Cases With Their Status and Assignee
+// @Security BearerAuth
+// @Tags Admin
+// @Param caseStatus query string false "case status"
+// @Param targetType query string false "target type"
+// @Param targetID query integer false "target id"
+// @Param assigneeID query integer false "assignee id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.ReportCase{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/reports [GET]
+func (r *rest) GetReportCaseList(ctx *gin.Context) {
+	var param entity.ReportCaseParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	reportCases, pg, err := r.uc.Report.GetCaseList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, reportCases, pg)
+}
+
+// @Summary Get Report Case
+// @Description Get a Report Case With the Reports Filed Under It
+// @Security BearerAuth
+// @Tags Admin
+// @Param case_id path integer true "case id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.ReportCase{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/reports/{case_id} [GET]
+func (r *rest) GetReportCase(ctx *gin.Context) {
+	var param entity.ReportCaseParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	reportCase, err := r.uc.Report.GetCase(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, reportCase, nil)
+}
+
+// @Summary Assign Report Case
+// @Description Assign an Open Report Case to an Admin and Move It Into Review
+// @Security BearerAuth
+// @Tags Admin
+// @Param case_id path integer true "case id"
+// @Param data body entity.ReportCaseAssignParam true "Assignee Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/reports/{case_id}/assign [PUT]
+func (r *rest) AssignReportCase(ctx *gin.Context) {
+	var param entity.ReportCaseAssignParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Report.Assign(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Resolve Report Case
+// @Description Close a Report Case by Warning or Suspending the Reported User, or Dismiss It
+// @Security BearerAuth
+// @Tags Admin
+// @Param case_id path integer true "case id"
+// @Param data body entity.ReportCaseResolveParam true "Resolution Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/reports/{case_id}/resolve [PUT]
+func (r *rest) ResolveReportCase(ctx *gin.Context) {
+	var param entity.ReportCaseResolveParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Report.Resolve(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Unsuspend Report Case User
+// @Description Lift the Suspension a Resolved Report Case Put on Its User
+// @Security BearerAuth
+// @Tags Admin
+// @Param case_id path integer true "case id"
+// @Param data body entity.ReportCaseUnsuspendParam true "Unsuspend Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/reports/{case_id}/unsuspend [PUT]
+func (r *rest) UnsuspendReportCase(ctx *gin.Context) {
+	var param entity.ReportCaseUnsuspendParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Report.Unsuspend(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
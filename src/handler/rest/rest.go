@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -20,12 +18,14 @@ import (
 	"github.com/reyhanmichiels/go-pkg/rate_limiter"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/usecase"
 	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/config"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
 )
 
 var once = &sync.Once{}
 
 type REST interface {
-	Run()
+	// Run serves http until ctx is cancelled, then shuts the server down gracefully
+	Run(ctx context.Context)
 }
 
 type rest struct {
@@ -36,6 +36,7 @@ type rest struct {
 	rateLimiter rate_limiter.Interface
 	json        parser.JSONInterface
 	auth        auth.Interface
+	scheduler   scheduler.Interface
 }
 
 type InitParam struct {
@@ -45,6 +46,7 @@ type InitParam struct {
 	RateLimiter rate_limiter.Interface
 	Json        parser.JSONInterface
 	Auth        auth.Interface
+	Scheduler   scheduler.Interface
 }
 
 func Init(param InitParam) REST {
@@ -63,6 +65,12 @@ func Init(param InitParam) REST {
 		// initialize struct
 		httpServer := gin.New()
 
+		// without trusted proxies any client could choose the ip recorded in the audit trail
+		err := httpServer.SetTrustedProxies(param.GinConfig.TrustedProxies)
+		if err != nil {
+			param.Log.Fatal(context.Background(), err)
+		}
+
 		r = rest{
 			http:        httpServer,
 			uc:          param.Uc,
@@ -71,6 +79,7 @@ func Init(param InitParam) REST {
 			rateLimiter: param.RateLimiter,
 			json:        param.Json,
 			auth:        param.Auth,
+			scheduler:   param.Scheduler,
 		}
 
 		// Set CORS
@@ -98,7 +107,8 @@ func Init(param InitParam) REST {
 		// Set Timeout
 		r.http.Use(r.SetTimeout)
 
-		// TODO: set audit
+		// Set Audit
+		r.http.Use(r.SetAuditInfo)
 
 		r.Register()
 	})
@@ -117,7 +127,7 @@ func (r *rest) Register() {
 		r.rateLimiter.Limiter(), r.addFieldsToContext, r.BodyLogger,
 	}
 	commonPrivateMiddlewares := gin.HandlersChain{
-		r.rateLimiter.Limiter(), r.addFieldsToContext, r.BodyLogger, r.VerifyUser,
+		r.rateLimiter.Limiter(), r.addFieldsToContext, r.BodyLogger, r.VerifyUser, r.Idempotent,
 	}
 
 	// auth api
@@ -126,19 +136,99 @@ func (r *rest) Register() {
 	authV1.POST("/login", r.SignInWithPassword)
 	authV1.POST("/token/refresh", r.RefreshToken)
 
+	// admin api
+	adminV1 := r.http.Group("/admin/v1", append(commonPrivateMiddlewares, r.VerifyAdmin)...)
+	adminV1.GET("/audits", r.GetAuditList)
+	adminV1.PUT("/users/:user_id/role", r.UpdateUserRole)
+	adminV1.GET("/jobs", r.GetJobList)
+	adminV1.GET("/retention/report", r.GetRetentionReport)
+	adminV1.POST("/legal-holds", r.CreateLegalHold)
+	adminV1.GET("/legal-holds", r.GetLegalHoldList)
+	adminV1.DELETE("/legal-holds/:legal_hold_id", r.ReleaseLegalHold)
+	adminV1.POST("/exports", r.CreateExport)
+	adminV1.GET("/exports", r.GetExportList)
+	adminV1.GET("/exports/:export_id", r.GetExport)
+	adminV1.GET("/reports", r.GetReportCaseList)
+	adminV1.GET("/reports/:case_id", r.GetReportCase)
+	adminV1.PUT("/reports/:case_id/assign", r.AssignReportCase)
+	adminV1.PUT("/reports/:case_id/resolve", r.ResolveReportCase)
+	adminV1.PUT("/reports/:case_id/unsuspend", r.UnsuspendReportCase)
+
 	// public api
-	r.http.Group("/public/v1/", commonPublicMiddlewares...)
+	publicV1 := r.http.Group("/public/v1/", commonPublicMiddlewares...)
+	publicV1.GET("/digest/unsubscribe", r.UnsubscribeDigest)
+	publicV1.GET("/exports/download", r.DownloadExport)
 
 	// private api
-	r.http.Group("/v1/", commonPrivateMiddlewares...)
+	v1 := r.http.Group("/v1/", commonPrivateMiddlewares...)
+
+	// account api
+	v1.DELETE("/me", r.DeleteMe)
+	v1.POST("/me/export", r.RequestOwnExport)
+	v1.GET("/me/exports/:export_id", r.GetOwnExport)
+
+	// report api
+	v1.POST("/reports", r.CreateReport)
+
+	// webhook api
+	v1.POST("/webhooks", r.CreateWebhook)
+	v1.GET("/webhooks", r.GetWebhookList)
+	v1.GET("/webhooks/:webhook_id", r.GetWebhook)
+	v1.DELETE("/webhooks/:webhook_id", r.DeleteWebhook)
+	v1.PUT("/webhooks/:webhook_id/enable", r.EnableWebhook)
+	v1.GET("/webhooks/:webhook_id/deliveries", r.GetWebhookDeliveryList)
+
+	// relation api
+	v1.POST("/relations", r.CreateRelation)
+	v1.GET("/relations", r.GetRelationList)
+	v1.DELETE("/relations/:relation_id", r.DeleteRelation)
+
+	// workspace api
+	v1.POST("/workspaces", r.CreateWorkspace)
+	v1.GET("/workspaces", r.GetWorkspaceList)
+	workspaceV1 := v1.Group("/workspaces/:workspace_id", r.VerifyWorkspaceMember)
+	workspaceV1.GET("", r.GetWorkspace)
+	workspaceV1.GET("/members", r.GetWorkspaceMemberList)
+	workspaceV1.POST("/members", r.AddWorkspaceMember)
+	workspaceV1.DELETE("/members/:user_id", r.RemoveWorkspaceMember)
+	workspaceV1.GET("/retention", r.GetRetentionPolicy)
+	workspaceV1.PUT("/retention", r.UpdateRetentionPolicy)
+	workspaceV1.DELETE("/retention", r.DeleteRetentionPolicy)
+
+	// notification api
+	v1.GET("/notifications", r.GetNotificationList)
+	v1.PUT("/notifications/:notification_id/read", r.MarkNotificationRead)
+	v1.POST("/me/devices", r.RegisterDevice)
+	v1.DELETE("/me/devices/:device_id", r.DeleteDevice)
+
+	// encryption key api
+	v1.PUT("/me/devices/:device_id/keys", r.UploadDeviceKey)
+	v1.GET("/me/devices/:device_id/keys", r.GetDeviceKey)
+	v1.GET("/users/:user_id/keys", r.GetKeyBundleList)
+
+	// event api
+	v1.GET("/events", r.StreamEvent)
+	v1.GET("/sync", r.Sync)
+
+	// preference api
+	v1.GET("/me/preferences", r.GetPreference)
+	v1.PUT("/me/preferences", r.UpdatePreference)
+	v1.GET("/me/preferences/scopes", r.GetScopePreferenceList)
+	v1.PUT("/me/preferences/scopes", r.UpdateScopePreference)
+
+	// contact api
+	v1.GET("/contacts", r.GetContactList)
+	v1.POST("/contacts/requests", r.SendContactRequest)
+	v1.GET("/contacts/requests", r.GetContactRequestList)
+	v1.PUT("/contacts/requests/:contact_id/accept", r.AcceptContactRequest)
+	v1.PUT("/contacts/requests/:contact_id/decline", r.DeclineContactRequest)
+	v1.PUT("/contacts/requests/:contact_id/cancel", r.CancelContactRequest)
 }
 
-func (r *rest) Run() {
-	// Create context that listens for the interrupt signal from the OS.
-	c := appcontext.SetServiceVersion(context.Background(), r.ginConfig.Meta.Version)
-
-	ctx, stop := signal.NotifyContext(c, syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+func (r *rest) Run(ctx context.Context) {
+	// ctx is cancelled by the interrupt signal from the OS, c outlives it for the shutdown.
+	ctx = appcontext.SetServiceVersion(ctx, r.ginConfig.Meta.Version)
+	c := context.WithoutCancel(ctx)
 
 	// configure server
 	port := ":8080"
@@ -164,9 +254,7 @@ func (r *rest) Run() {
 	// Listen for the interrupt signal.
 	<-ctx.Done()
 
-	// Restore default behavior on the interrupt signal and notify user of shutdown.
-	stop()
-	r.log.Info(ctx, "Shutting down server...")
+	r.log.Info(c, "Shutting down server...")
 
 	// The context is used to inform the server it has timeout duration to finish
 	// the request it is currently handling
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Get Workspace Retention
+// @Description Get How Long Workspace Data Is Kept Before It Is Purged
+// @Security BearerAuth
+// @Tags Retention
+// @Param workspace_id path integer true "workspace id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.RetentionPolicy{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/retention [GET]
+func (r *rest) GetRetentionPolicy(ctx *gin.Context) {
+	policy, err := r.uc.Retention.Get(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, policy, nil)
+}
+
+// @Summary Update Workspace Retention
+// @Description Set How Many Days Workspace Data Is Kept, Older Data Is Permanently Deleted by the Purge Job
+// @Security BearerAuth
+// @Tags Retention
+// @Param workspace_id path integer true "workspace id"
+// @Param data body entity.RetentionPolicyInputParam true "Retention Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.RetentionPolicy{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/retention [PUT]
+func (r *rest) UpdateRetentionPolicy(ctx *gin.Context) {
+	var param entity.RetentionPolicyInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	policy, err := r.uc.Retention.Upsert(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, policy, nil)
+}
+
+// @Summary Delete Workspace Retention
+// @Description Stop Purging Workspace Data
+// @Security BearerAuth
+// @Tags Retention
+// @Param workspace_id path integer true "workspace id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/retention [DELETE]
+func (r *rest) DeleteRetentionPolicy(ctx *gin.Context) {
+	err := r.uc.Retention.Delete(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Get Retention Report
+// @Description Dry Run Every Retention Policy and Report What the Purge Job Would Delete
+// @Security BearerAuth
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.RetentionReport{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /admin/v1/retention/report [GET]
+func (r *rest) GetRetentionReport(ctx *gin.Context) {
+	reports, err := r.uc.Retention.Purge(ctx.Request.Context(), true)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, reports, nil)
+}
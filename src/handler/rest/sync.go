@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Sync
+// @Description Get Changes Since a Sync Token so Reconnecting Clients Can Catch Up Incrementally.
+// @Description Call without since to get the token to start from, keep calling with nextToken while hasMore is true.
+// @Description A 409 means events after the token were purged by retention, resync and call again without since.
+// @Security BearerAuth
+// @Tags Sync
+// @Param since query string false "sync token"
+// @Param limit query integer false "max changes per page, up to 500"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Sync{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/sync [GET]
+func (r *rest) Sync(ctx *gin.Context) {
+	var param entity.SyncParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	sync, err := r.uc.Sync.Get(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, sync, nil)
+}
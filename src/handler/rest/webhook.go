@@ -0,0 +1,187 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Webhook
+// @Description Subscribe an external URL to chat events, the signing secret is only returned once
+// @Security BearerAuth
+// @Tags Webhook
+// @Param data body entity.WebhookInputParam true "Webhook Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Webhook{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks [POST]
+func (r *rest) CreateWebhook(ctx *gin.Context) {
+	var param entity.WebhookInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	webhook, err := r.uc.Webhook.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, webhook, nil)
+}
+
+// @Summary Get Webhook List
+// @Description Get List of Webhooks Owned by Current User
+// @Security BearerAuth
+// @Tags Webhook
+// @Param scopeType query string false "scope type"
+// @Param scopeID query integer false "scope id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Webhook{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks [GET]
+func (r *rest) GetWebhookList(ctx *gin.Context) {
+	var param entity.WebhookParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	webhooks, pg, err := r.uc.Webhook.GetList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, webhooks, pg)
+}
+
+// @Summary Get Webhook
+// @Description Get Webhook Detail
+// @Security BearerAuth
+// @Tags Webhook
+// @Param webhook_id path integer true "webhook id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Webhook{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks/{webhook_id} [GET]
+func (r *rest) GetWebhook(ctx *gin.Context) {
+	var param entity.WebhookParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	webhook, err := r.uc.Webhook.Get(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, webhook, nil)
+}
+
+// @Summary Delete Webhook
+// @Description Unsubscribe Webhook
+// @Security BearerAuth
+// @Tags Webhook
+// @Param webhook_id path integer true "webhook id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks/{webhook_id} [DELETE]
+func (r *rest) DeleteWebhook(ctx *gin.Context) {
+	var param entity.WebhookParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Webhook.Delete(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Enable Webhook
+// @Description Re-enable Webhook Disabled After Repeated Delivery Failures
+// @Security BearerAuth
+// @Tags Webhook
+// @Param webhook_id path integer true "webhook id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks/{webhook_id}/enable [PUT]
+func (r *rest) EnableWebhook(ctx *gin.Context) {
+	var param entity.WebhookParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Webhook.Enable(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
+
+// @Summary Get Webhook Delivery List
+// @Description Get Delivery Log of a Webhook
+// @Security BearerAuth
+// @Tags Webhook
+// @Param webhook_id path integer true "webhook id"
+// @Param eventID query string false "event id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.WebhookDelivery{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/webhooks/{webhook_id}/deliveries [GET]
+func (r *rest) GetWebhookDeliveryList(ctx *gin.Context) {
+	var param entity.WebhookDeliveryParam
+
+	err := r.BindParams(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	deliveries, pg, err := r.uc.Webhook.GetDeliveryList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, deliveries, pg)
+}
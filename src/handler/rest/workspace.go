@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/reyhanmichiels/go-pkg/codes"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/business/entity"
+)
+
+// @Summary Create Workspace
+// @Description Create a Workspace Owned by Current User
+// @Security BearerAuth
+// @Tags Workspace
+// @Param data body entity.WorkspaceInputParam true "Workspace Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Workspace{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces [POST]
+func (r *rest) CreateWorkspace(ctx *gin.Context) {
+	var param entity.WorkspaceInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	workspace, err := r.uc.Workspace.Create(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, workspace, nil)
+}
+
+// @Summary Get Workspace List
+// @Description Get Workspaces Current User is a Member of
+// @Security BearerAuth
+// @Tags Workspace
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.Workspace{}}
+// @Failure 401 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces [GET]
+func (r *rest) GetWorkspaceList(ctx *gin.Context) {
+	workspaces, err := r.uc.Workspace.GetList(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, workspaces, nil)
+}
+
+// @Summary Get Workspace
+// @Description Get Workspace Detail
+// @Security BearerAuth
+// @Tags Workspace
+// @Param workspace_id path integer true "workspace id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.Workspace{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id} [GET]
+func (r *rest) GetWorkspace(ctx *gin.Context) {
+	workspace, err := r.uc.Workspace.Get(ctx.Request.Context())
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, workspace, nil)
+}
+
+// @Summary Get Workspace Member List
+// @Description Get Members of a Workspace
+// @Security BearerAuth
+// @Tags Workspace
+// @Param workspace_id path integer true "workspace id"
+// @Param page query integer false "page"
+// @Param limit query integer false "limit"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=[]entity.WorkspaceMember{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/members [GET]
+func (r *rest) GetWorkspaceMemberList(ctx *gin.Context) {
+	var param entity.WorkspaceMemberParam
+
+	err := r.BindQuery(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	members, pg, err := r.uc.Workspace.GetMemberList(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, members, pg)
+}
+
+// @Summary Add Workspace Member
+// @Description Add a User to a Workspace, Requires Workspace Admin
+// @Security BearerAuth
+// @Tags Workspace
+// @Param workspace_id path integer true "workspace id"
+// @Param data body entity.WorkspaceMemberInputParam true "Member Data"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{data=entity.WorkspaceMember{}}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 409 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/members [POST]
+func (r *rest) AddWorkspaceMember(ctx *gin.Context) {
+	var param entity.WorkspaceMemberInputParam
+
+	err := r.Bind(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	member, err := r.uc.Workspace.AddMember(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, member, nil)
+}
+
+// @Summary Remove Workspace Member
+// @Description Remove a Member from a Workspace, Members Can Remove Themselves to Leave
+// @Security BearerAuth
+// @Tags Workspace
+// @Param workspace_id path integer true "workspace id"
+// @Param user_id path integer true "user id"
+// @Produce json
+// @Success 200 {object} entity.HTTPResp{}
+// @Failure 400 {object} entity.HTTPResp{}
+// @Failure 403 {object} entity.HTTPResp{}
+// @Failure 404 {object} entity.HTTPResp{}
+// @Failure 500 {object} entity.HTTPResp{}
+// @Router /v1/workspaces/{workspace_id}/members/{user_id} [DELETE]
+func (r *rest) RemoveWorkspaceMember(ctx *gin.Context) {
+	var param entity.WorkspaceMemberParam
+
+	err := r.BindUri(ctx, &param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	err = r.uc.Workspace.RemoveMember(ctx.Request.Context(), param)
+	if err != nil {
+		r.httpRespError(ctx, err)
+		return
+	}
+
+	r.httpRespSuccess(ctx, codes.CodeSuccess, nil, nil)
+}
@@ -10,18 +10,38 @@ import (
 	"github.com/reyhanmichiels/go-pkg/redis"
 	"github.com/reyhanmichiels/go-pkg/sql"
 	"github.com/reyhanmichiels/go-pkg/translator"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/mail"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/push"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/scheduler"
 )
 
 type Application struct {
-	Meta        ApplicationMeta
-	Gin         GinConfig
-	Log         log.Config
-	SQL         sql.Config
-	Auth        auth.Config
-	Redis       redis.Config
-	Translator  translator.Config
-	RateLimiter rate_limiter.Config
-	Parser      parser.Options
+	Meta         ApplicationMeta
+	Gin          GinConfig
+	Log          log.Config
+	SQL          sql.Config
+	Auth         auth.Config
+	Redis        redis.Config
+	Translator   translator.Config
+	RateLimiter  rate_limiter.Config
+	Parser       parser.Options
+	Webhook      WebhookConfig
+	Outbox       OutboxConfig
+	Audit        AuditConfig
+	Contact      ContactConfig
+	Presence     PresenceConfig
+	Push         push.Config
+	Notification NotificationConfig
+	Mail         mail.Config
+	Locker       locker.Config
+	Digest       DigestConfig
+	Scheduler    scheduler.Config
+	Stream       StreamConfig
+	Realtime     RealtimeConfig
+	Idempotency  IdempotencyConfig
+	Retention    RetentionConfig
+	Export       ExportConfig
 }
 
 type ApplicationMeta struct {
@@ -39,10 +59,13 @@ type GinConfig struct {
 	LogResponse     bool
 	Timeout         time.Duration
 	ShutdownTimeout time.Duration
-	CORS            CORSConfig
-	Meta            ApplicationMeta
-	Swagger         SwaggerConfig
-	Dummy           DummyConfig
+	// TrustedProxies are the addresses allowed to set the client ip through X-Forwarded-For,
+	// requests from anywhere else are recorded with their remote address
+	TrustedProxies []string
+	CORS           CORSConfig
+	Meta           ApplicationMeta
+	Swagger        SwaggerConfig
+	Dummy          DummyConfig
 }
 
 type CORSConfig struct {
@@ -65,6 +88,112 @@ type DummyConfig struct {
 	Path    string
 }
 
+type WebhookConfig struct {
+	Timeout         time.Duration
+	MaxAttempt      int
+	BackoffInterval time.Duration
+	MaxFailure      int64
+	// DeliveryInterval is how often queued deliveries are sent, at most BatchSize per run
+	// and Concurrency at a time
+	DeliveryInterval time.Duration
+	BatchSize        int
+	Concurrency      int
+}
+
+type OutboxConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempt is how many times an event is published before it is dead lettered
+	MaxAttempt      int64
+	BackoffInterval time.Duration
+}
+
+type AuditConfig struct {
+	Retention      time.Duration
+	PurgeInterval  time.Duration
+	PurgeBatchSize int
+}
+
+type ContactConfig struct {
+	// ContactsOnlyDirectMessage restricts new direct conversations to accepted contacts
+	ContactsOnlyDirectMessage bool
+}
+
+type PresenceConfig struct {
+	OnlineWindow time.Duration
+	TTL          time.Duration
+}
+
+type NotificationConfig struct {
+	// PushConcurrency bounds the pushes sent at once, pushes beyond it are dropped
+	PushConcurrency int
+	// JobInterval is how often queued fan-outs are processed, at most BatchSize per run
+	JobInterval     time.Duration
+	BatchSize       int
+	MaxAttempt      int
+	BackoffInterval time.Duration
+}
+
+type DigestConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// OfflineAfter is how long a user must have been away before receiving a digest
+	OfflineAfter      time.Duration
+	MaxItemsPerScope  int
+	TemplatePath      string
+	UnsubscribeURL    string
+	UnsubscribeSecret string
+}
+
+// StreamConfig is the redis connection holding the per-user event logs
+type StreamConfig struct {
+	Address  string
+	Password string
+	DB       int
+	// WaitPoolSize caps the connections held by blocking reads of open real-time streams, they get their
+	// own pool so idle streams cannot starve appends, zero keeps the client default
+	WaitPoolSize int
+	// WaitPoolTimeout is how long a stream waits for a free connection before it fails
+	WaitPoolTimeout time.Duration
+}
+
+type RealtimeConfig struct {
+	// MaxLen caps the events kept per user, older events can no longer be resumed
+	MaxLen int64
+	TTL    time.Duration
+	// Heartbeat is how often an idle stream sends a ping so proxies keep it open
+	Heartbeat time.Duration
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed to retries with the same key
+	TTL time.Duration
+	// LockTTL bounds how long a request holds its key before a retry may run it again
+	LockTTL time.Duration
+}
+
+type RetentionConfig struct {
+	PurgeInterval  time.Duration
+	PurgeBatchSize int
+	// DryRun only reports what the purge job would delete
+	DryRun bool
+}
+
+// ExportConfig is how compliance export archives are built and stored and how their download links are signed
+type ExportConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// ChunkSize is the most bytes of an archive stored in a single row
+	ChunkSize int
+	// RunTimeout is how long a running export may take before another replica builds it again
+	RunTimeout time.Duration
+	// FileTTL is how long finished archives are kept
+	FileTTL     time.Duration
+	LinkTTL     time.Duration
+	DownloadURL string
+	Secret      string
+}
+
 type BasicAuthConf struct {
 	Username string
 	Password string
@@ -0,0 +1,77 @@
+package locker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bsm/redislock"
+	"github.com/redis/go-redis/v9"
+	"github.com/reyhanmichiels/go-pkg/log"
+)
+
+// ErrNotObtained is returned when another replica already holds the lock
+var ErrNotObtained = errors.New("locker: lock not obtained")
+
+type Config struct {
+	Address  string
+	Password string
+	DB       int
+}
+
+type Lock interface {
+	// Refresh extends the lock by ttl, it fails with ErrNotObtained once the lock has been lost
+	Refresh(ctx context.Context, ttl time.Duration) error
+	Release(ctx context.Context) error
+}
+
+type Interface interface {
+	// Obtain takes a distributed lock on key for ttl, it does not wait for the lock to be released
+	Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+type locker struct {
+	client *redislock.Client
+	log    log.Interface
+}
+
+func Init(cfg Config, log log.Interface) Interface {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Address,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	return &locker{
+		client: redislock.New(client),
+		log:    log,
+	}
+}
+
+func (l *locker) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	lock, err := l.client.Obtain(ctx, key, ttl, nil)
+	if errors.Is(err, redislock.ErrNotObtained) {
+		return nil, ErrNotObtained
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &redisLock{lock: lock}, nil
+}
+
+type redisLock struct {
+	lock *redislock.Lock
+}
+
+func (r *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	err := r.lock.Refresh(ctx, ttl, nil)
+	if errors.Is(err, redislock.ErrNotObtained) {
+		return ErrNotObtained
+	}
+
+	return err
+}
+
+func (r *redisLock) Release(ctx context.Context) error {
+	return r.lock.Release(ctx)
+}
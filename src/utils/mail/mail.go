@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+)
+
+type Config struct {
+	Enabled  bool
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Headers map[string]string
+}
+
+type Interface interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Init returns an SMTP sender, or a no-op sender when mail is disabled
+func Init(cfg Config, log log.Interface) Interface {
+	if !cfg.Enabled {
+		return &noop{}
+	}
+
+	return &smtpSender{
+		cfg: cfg,
+		log: log,
+	}
+}
+
+type noop struct{}
+
+func (n *noop) Send(ctx context.Context, msg Message) error {
+	return nil
+}
+
+type smtpSender struct {
+	cfg Config
+	log log.Interface
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(s.cfg.Host, fmt.Sprint(s.cfg.Port))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	err := smtp.SendMail(addr, auth, s.cfg.From, []string{msg.To}, Compose(s.cfg.From, msg))
+	if err != nil {
+		return fmt.Errorf("mail: send to %s failed: %w", msg.To, err)
+	}
+
+	s.log.Debug(ctx, fmt.Sprintf("mail %s sent to %s", msg.Subject, msg.To))
+
+	return nil
+}
+
+// Compose builds the raw RFC 5322 message of an HTML email
+func Compose(from string, msg Message) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	for key, value := range msg.Headers {
+		b.WriteString(key + ": " + value + "\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.HTML)
+
+	return []byte(b.String())
+}
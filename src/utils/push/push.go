@@ -0,0 +1,122 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// ErrInvalidToken is returned when the provider no longer accepts the device token,
+// callers should stop sending to that device
+var ErrInvalidToken = errors.New("push: invalid device token")
+
+type Config struct {
+	Enabled   bool
+	URL       string
+	ServerKey string
+	Timeout   time.Duration
+}
+
+type Message struct {
+	Token    string            `json:"to"`
+	Platform string            `json:"platform"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+type Interface interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Init returns a provider that posts messages to an FCM/APNs style HTTP gateway,
+// or a no-op provider when push is disabled
+func Init(cfg Config, log log.Interface) Interface {
+	if !cfg.Enabled {
+		return &noop{}
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &httpProvider{
+		cfg:    cfg,
+		log:    log,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+type noop struct{}
+
+func (n *noop) Send(ctx context.Context, msg Message) error {
+	return nil
+}
+
+type httpProvider struct {
+	cfg    Config
+	log    log.Interface
+	client *http.Client
+}
+
+type payload struct {
+	To           string            `json:"to"`
+	Platform     string            `json:"platform"`
+	Notification notification      `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type notification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (h *httpProvider) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(payload{
+		To:       msg.Token,
+		Platform: msg.Platform,
+		Notification: notification{
+			Title: msg.Title,
+			Body:  msg.Body,
+		},
+		Data: msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+h.cfg.ServerKey)
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return ErrInvalidToken
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		return fmt.Errorf("push: gateway responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
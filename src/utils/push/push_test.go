@@ -0,0 +1,81 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_httpProvider_Send(t *testing.T) {
+	mockMessage := Message{
+		Token:    "device-token",
+		Platform: "android",
+		Title:    "New message",
+		Body:     "hello",
+		Data:     map[string]string{"scopeID": "1"},
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "delivered",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "token no longer registered",
+			statusCode: http.StatusGone,
+			wantErr:    ErrInvalidToken,
+		},
+		{
+			name:       "gateway error",
+			statusCode: http.StatusInternalServerError,
+			wantAnyErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotAuth    string
+				gotPayload payload
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = r.Header.Get("Authorization")
+				_ = json.NewDecoder(r.Body).Decode(&gotPayload)
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			p := Init(Config{Enabled: true, URL: server.URL, ServerKey: "server-key"}, nil)
+			err := p.Send(context.Background(), mockMessage)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantAnyErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, "key=server-key", gotAuth)
+			assert.Equal(t, "device-token", gotPayload.To)
+			assert.Equal(t, "New message", gotPayload.Notification.Title)
+			assert.Equal(t, "hello", gotPayload.Notification.Body)
+			assert.Equal(t, map[string]string{"scopeID": "1"}, gotPayload.Data)
+		})
+	}
+}
+
+func Test_noop_Send(t *testing.T) {
+	p := Init(Config{Enabled: false}, nil)
+	assert.NoError(t, p.Send(context.Background(), Message{Token: "device-token"}))
+}
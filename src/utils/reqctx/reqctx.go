@@ -0,0 +1,32 @@
+package reqctx
+
+import "context"
+
+type contextKey string
+
+const (
+	clientIPKey    contextKey = "clientIP"
+	workspaceIDKey contextKey = "workspaceID"
+)
+
+// SetClientIP stores the IP address of the client that sent the request
+func SetClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// GetClientIP returns the IP address stored by SetClientIP, or an empty string
+func GetClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
+
+// SetWorkspaceID stores the workspace the request is scoped to
+func SetWorkspaceID(ctx context.Context, workspaceID int64) context.Context {
+	return context.WithValue(ctx, workspaceIDKey, workspaceID)
+}
+
+// GetWorkspaceID returns the workspace stored by SetWorkspaceID, or 0 when the request is not workspace scoped
+func GetWorkspaceID(ctx context.Context) int64 {
+	workspaceID, _ := ctx.Value(workspaceIDKey).(int64)
+	return workspaceID
+}
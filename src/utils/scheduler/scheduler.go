@@ -0,0 +1,325 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/reyhanmichiels/go-pkg/log"
+	"github.com/reyhanmichiels/go-pkg/null"
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+)
+
+var Now = time.Now
+
+const (
+	lockKey    = "boilerplate:job:%s:%d"
+	runLockKey = "boilerplate:job:%s:running"
+
+	defaultMaxAttempt      = 3
+	defaultBackoffInterval = time.Second
+	defaultTimeout         = 10 * time.Minute
+	defaultLockTTL         = 30 * time.Second
+)
+
+type Config struct {
+	// MaxAttempt is how many times a failing run is tried before it is recorded as failed
+	MaxAttempt      int
+	BackoffInterval time.Duration
+	// Timeout bounds a single run including its retries
+	Timeout time.Duration
+	// LockTTL is how long the lock of a running job outlives a replica that died mid run,
+	// the lock is extended every third of it while the run is alive
+	LockTTL time.Duration
+	// Schedules overrides the default schedule of a job by name, e.g. {"audit-purge": "0 3 * * *"}
+	Schedules map[string]string
+}
+
+type Func func(ctx context.Context) error
+
+// Status is the state of a job as seen by this replica
+type Status struct {
+	Name           string    `json:"name"`
+	Schedule       string    `json:"schedule"`
+	Running        bool      `json:"running"`
+	NextRunAt      null.Time `json:"nextRunAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	LastStartedAt  null.Time `json:"lastStartedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	LastFinishedAt null.Time `json:"lastFinishedAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	LastSuccessAt  null.Time `json:"lastSuccessAt" swaggertype:"string" example:"2022-06-21T10:32:29Z"`
+	LastError      string    `json:"lastError,omitempty"`
+	LastAttempt    int       `json:"lastAttempt"`
+	RunCount       int64     `json:"runCount"`
+	FailureCount   int64     `json:"failureCount"`
+	// SkipCount counts activations that another replica ran
+	SkipCount int64 `json:"skipCount"`
+}
+
+type Interface interface {
+	// Register adds a job, the schedule can be overridden by name through the config
+	Register(name string, schedule Schedule, fn Func) error
+	// Start runs every registered job on its schedule until ctx is done
+	Start(ctx context.Context)
+	// Wait blocks until the runs in flight have finished after ctx is done
+	Wait()
+	Status() []Status
+}
+
+type scheduler struct {
+	cfg    Config
+	log    log.Interface
+	locker locker.Interface
+	mu     sync.Mutex
+	jobs   map[string]*job
+	wg     sync.WaitGroup
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       Func
+	status   Status
+}
+
+func Init(cfg Config, log log.Interface, locker locker.Interface) Interface {
+	if cfg.MaxAttempt < 1 {
+		cfg.MaxAttempt = defaultMaxAttempt
+	}
+
+	if cfg.BackoffInterval <= 0 {
+		cfg.BackoffInterval = defaultBackoffInterval
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultLockTTL
+	}
+
+	return &scheduler{
+		cfg:    cfg,
+		log:    log,
+		locker: locker,
+		jobs:   map[string]*job{},
+	}
+}
+
+func (s *scheduler) Register(name string, schedule Schedule, fn Func) error {
+	if spec, ok := s.cfg.Schedules[name]; ok {
+		override, err := Parse(spec)
+		if err != nil {
+			return err
+		}
+		schedule = override
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("scheduler: job %s is already registered", name)
+	}
+
+	s.jobs[name] = &job{
+		name:     name,
+		schedule: schedule,
+		fn:       fn,
+		status:   Status{Name: name, Schedule: schedule.String()},
+	}
+
+	return nil
+}
+
+func (s *scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+func (s *scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *scheduler) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status)
+	}
+
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].Name < statuses[k].Name
+	})
+
+	return statuses
+}
+
+func (s *scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(Now())
+		if next.IsZero() {
+			s.log.Error(ctx, fmt.Sprintf("job %s has no upcoming run", j.name))
+			return
+		}
+
+		s.update(j, func(status *Status) {
+			status.NextRunAt = null.TimeFrom(next)
+		})
+
+		timer := time.NewTimer(next.Sub(Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// a run in flight is allowed to finish on shutdown, it only stops retrying
+		s.run(ctx, j, next)
+	}
+}
+
+func (s *scheduler) run(ctx context.Context, j *job, activation time.Time) {
+	// the lock is keyed by activation and left to expire, so a replica whose timer fires late
+	// cannot run the same activation again
+	ttl := j.schedule.Next(activation).Sub(activation)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	_, err := s.locker.Obtain(ctx, fmt.Sprintf(lockKey, j.name, activation.Unix()), ttl)
+	if errors.Is(err, locker.ErrNotObtained) {
+		s.update(j, func(status *Status) {
+			status.SkipCount++
+		})
+		return
+	} else if err != nil {
+		s.log.Error(ctx, fmt.Sprintf("job %s lock failed: %v", j.name, err))
+		return
+	}
+
+	// the activation lock only dedupes replicas, a run outlasting its interval would overlap the next
+	// activation, so the job also holds a lock for as long as it runs
+	runLock, err := s.locker.Obtain(ctx, fmt.Sprintf(runLockKey, j.name), s.cfg.LockTTL)
+	if errors.Is(err, locker.ErrNotObtained) {
+		s.log.Warn(ctx, fmt.Sprintf("job %s skipped, the previous run is still in flight", j.name))
+		s.update(j, func(status *Status) {
+			status.SkipCount++
+		})
+		return
+	} else if err != nil {
+		s.log.Error(ctx, fmt.Sprintf("job %s lock failed: %v", j.name, err))
+		return
+	}
+
+	s.update(j, func(status *Status) {
+		status.Running = true
+		status.LastStartedAt = null.TimeFrom(Now())
+	})
+
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.Timeout)
+	defer cancel()
+
+	refreshed := make(chan struct{})
+	go s.refresh(runCtx, cancel, j, runLock, refreshed)
+
+	attempt, err := s.retry(ctx, runCtx, j)
+
+	cancel()
+	<-refreshed
+	if err := runLock.Release(context.WithoutCancel(ctx)); err != nil {
+		s.log.Error(ctx, fmt.Sprintf("job %s unlock failed: %v", j.name, err))
+	}
+
+	s.update(j, func(status *Status) {
+		status.Running = false
+		status.LastFinishedAt = null.TimeFrom(Now())
+		status.LastAttempt = attempt
+		status.RunCount++
+		status.LastError = ""
+		if err != nil {
+			status.FailureCount++
+			status.LastError = err.Error()
+		} else {
+			status.LastSuccessAt = status.LastFinishedAt
+		}
+	})
+
+	if err != nil {
+		s.log.Error(ctx, fmt.Sprintf("job %s failed after %d attempts: %v", j.name, attempt, err))
+	}
+}
+
+// refresh extends the run lock until the run is over, a run whose lock is lost is cancelled since
+// another replica may already be running the job
+func (s *scheduler) refresh(runCtx context.Context, cancel context.CancelFunc, j *job, lock locker.Lock, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.cfg.LockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := lock.Refresh(runCtx, s.cfg.LockTTL)
+		if errors.Is(err, locker.ErrNotObtained) {
+			s.log.Error(runCtx, fmt.Sprintf("job %s lost its lock, cancelling the run", j.name))
+			cancel()
+			return
+		} else if err != nil {
+			s.log.Warn(runCtx, fmt.Sprintf("job %s lock refresh failed: %v", j.name, err))
+		}
+	}
+}
+
+// retry calls the job until it succeeds, the attempts run out or the service shuts down,
+// waiting twice as long after every failure
+func (s *scheduler) retry(ctx, runCtx context.Context, j *job) (int, error) {
+	backoff := s.cfg.BackoffInterval
+
+	attempt := 1
+	for ; ; attempt++ {
+		err := j.fn(runCtx)
+		if err == nil || attempt >= s.cfg.MaxAttempt {
+			return attempt, err
+		}
+
+		s.log.Warn(ctx, fmt.Sprintf("job %s attempt %d failed: %v", j.name, attempt, err))
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-runCtx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+func (s *scheduler) update(j *job, fn func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&j.status)
+}
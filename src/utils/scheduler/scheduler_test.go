@@ -0,0 +1,281 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reyhanmichies/go-rest-api-boiler-plate/src/utils/locker"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Next(t *testing.T) {
+	from := time.Date(2024, 6, 21, 10, 32, 29, 0, time.UTC) // friday
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "every minute",
+			spec: "* * * * *",
+			want: time.Date(2024, 6, 21, 10, 33, 0, 0, time.UTC),
+		},
+		{
+			name: "every fifteen minutes",
+			spec: "*/15 * * * *",
+			want: time.Date(2024, 6, 21, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "daily at three",
+			spec: "0 3 * * *",
+			want: time.Date(2024, 6, 22, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays range and list",
+			spec: "30 9,18 * * 1-5",
+			want: time.Date(2024, 6, 21, 18, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as seven",
+			spec: "0 0 * * 7",
+			want: time.Date(2024, 6, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 1 * 1",
+			want: time.Date(2024, 6, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month step alone",
+			spec: "0 0 */2 * *",
+			want: time.Date(2024, 6, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month step narrows day of week",
+			spec: "0 0 */2 * 1",
+			want: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week step narrows day of month",
+			spec: "0 0 1 * */2",
+			want: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "descriptor",
+			spec: "@monthly",
+			want: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "interval",
+			spec: "@every 10m0s",
+			want: time.Date(2024, 6, 21, 10, 40, 0, 0, time.UTC),
+		},
+		{
+			name:    "missing field",
+			spec:    "0 3 * *",
+			wantErr: true,
+		},
+		{
+			name:    "out of range",
+			spec:    "60 * * * *",
+			wantErr: true,
+		},
+		{
+			name:    "invalid interval",
+			spec:    "@every soon",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+			assert.Equal(t, tt.spec, schedule.String())
+		})
+	}
+}
+
+type mockLocker struct {
+	err error
+	// busy fails only the keys it lists
+	busy map[string]bool
+	lock *mockLock
+}
+
+func (m *mockLocker) Obtain(ctx context.Context, key string, ttl time.Duration) (locker.Lock, error) {
+	if m.err != nil {
+		return nil, m.err
+	} else if m.busy[key] {
+		return nil, locker.ErrNotObtained
+	}
+
+	if m.lock == nil {
+		m.lock = &mockLock{}
+	}
+
+	return m.lock, nil
+}
+
+type mockLock struct {
+	mu        sync.Mutex
+	refreshed int
+	released  int
+	lost      bool
+}
+
+func (m *mockLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lost {
+		return locker.ErrNotObtained
+	}
+	m.refreshed++
+
+	return nil
+}
+
+func (m *mockLock) Release(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.released++
+
+	return nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(ctx context.Context, obj interface{}) {}
+func (nopLogger) Info(ctx context.Context, obj interface{})  {}
+func (nopLogger) Warn(ctx context.Context, obj interface{})  {}
+func (nopLogger) Error(ctx context.Context, obj interface{}) {}
+func (nopLogger) Fatal(ctx context.Context, obj interface{}) {}
+func (nopLogger) Panic(obj interface{})                      {}
+
+func Test_scheduler_run(t *testing.T) {
+	activation := time.Date(2024, 6, 21, 10, 0, 0, 0, time.UTC)
+	errMock := errors.New("temporary failure")
+
+	tests := []struct {
+		name        string
+		lockErr     error
+		busy        map[string]bool
+		failures    int
+		wantCalls   int
+		wantStatus  Status
+		wantSuccess bool
+	}{
+		{
+			name:        "succeeds first time",
+			wantCalls:   1,
+			wantStatus:  Status{Name: "job", Schedule: "@every 1m0s", LastAttempt: 1, RunCount: 1},
+			wantSuccess: true,
+		},
+		{
+			name:        "succeeds after retries",
+			failures:    2,
+			wantCalls:   3,
+			wantStatus:  Status{Name: "job", Schedule: "@every 1m0s", LastAttempt: 3, RunCount: 1},
+			wantSuccess: true,
+		},
+		{
+			name:       "gives up after max attempt",
+			failures:   5,
+			wantCalls:  3,
+			wantStatus: Status{Name: "job", Schedule: "@every 1m0s", LastAttempt: 3, RunCount: 1, FailureCount: 1, LastError: errMock.Error()},
+		},
+		{
+			name:       "another replica holds the lock",
+			lockErr:    locker.ErrNotObtained,
+			wantStatus: Status{Name: "job", Schedule: "@every 1m0s", SkipCount: 1},
+		},
+		{
+			name:       "previous run still in flight",
+			busy:       map[string]bool{"boilerplate:job:job:running": true},
+			wantStatus: Status{Name: "job", Schedule: "@every 1m0s", SkipCount: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Init(Config{MaxAttempt: 3, BackoffInterval: time.Millisecond}, nopLogger{}, &mockLocker{err: tt.lockErr, busy: tt.busy}).(*scheduler)
+
+			calls := 0
+			err := s.Register("job", Every(time.Minute), func(ctx context.Context) error {
+				calls++
+				if calls <= tt.failures {
+					return errMock
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+
+			s.run(context.Background(), s.jobs["job"], activation)
+
+			status := s.Status()[0]
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantSuccess, status.LastSuccessAt.Valid)
+
+			status.LastStartedAt, status.LastFinishedAt, status.LastSuccessAt = tt.wantStatus.LastStartedAt, tt.wantStatus.LastFinishedAt, tt.wantStatus.LastSuccessAt
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func Test_scheduler_run_lock(t *testing.T) {
+	activation := time.Date(2024, 6, 21, 10, 0, 0, 0, time.UTC)
+
+	t.Run("lock is extended while the run is alive", func(t *testing.T) {
+		l := &mockLocker{}
+		s := Init(Config{MaxAttempt: 1, LockTTL: 30 * time.Millisecond}, nopLogger{}, l).(*scheduler)
+
+		err := s.Register("job", Every(time.Minute), func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		s.run(context.Background(), s.jobs["job"], activation)
+
+		assert.Greater(t, l.lock.refreshed, 0)
+		assert.Equal(t, 1, l.lock.released)
+	})
+
+	t.Run("run is cancelled once the lock is lost", func(t *testing.T) {
+		l := &mockLocker{lock: &mockLock{lost: true}}
+		s := Init(Config{MaxAttempt: 1, LockTTL: 30 * time.Millisecond}, nopLogger{}, l).(*scheduler)
+
+		err := s.Register("job", Every(time.Minute), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.NoError(t, err)
+
+		s.run(context.Background(), s.jobs["job"], activation)
+
+		status := s.Status()[0]
+		assert.Equal(t, int64(1), status.FailureCount)
+		assert.Equal(t, 1, l.lock.released)
+	})
+}
+
+func Test_scheduler_Register(t *testing.T) {
+	s := Init(Config{Schedules: map[string]string{"purge": "0 3 * * *"}}, nopLogger{}, &mockLocker{})
+
+	assert.NoError(t, s.Register("purge", Every(time.Hour), func(ctx context.Context) error { return nil }))
+	assert.Error(t, s.Register("purge", Every(time.Hour), func(ctx context.Context) error { return nil }))
+	assert.Equal(t, "0 3 * * *", s.Status()[0].Schedule)
+}